@@ -46,6 +46,12 @@ func (*database) Template() *exql.Template {
 	return template
 }
 
+// SupportsReturning reports that statements that insert many rows can return
+// them.
+func (*database) SupportsReturning() bool {
+	return true
+}
+
 func (*database) Collections(sess sqladapter.Session) (collections []string, err error) {
 	q := sess.SQL().
 		Select("table_name").
@@ -233,3 +239,145 @@ func quotedTableName(s string) string {
 	}
 	return strings.Join(chunks, ".")
 }
+
+func (*database) DescribeTable(sess sqladapter.Session, tableName string) (*db.Table, error) {
+	columns, err := describeColumns(sess, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &db.Table{
+		Columns: sqladapter.Columns(columns),
+	}
+
+	indexes := []sqladapter.IndexColumn{}
+	q := `
+		SELECT
+			s.index_name AS index_name,
+			s.column_name AS column_name,
+			(s.non_unique = 'NO') AS is_unique,
+			(tc.constraint_type IS NOT NULL) AS is_primary
+		FROM information_schema.statistics AS s
+		LEFT JOIN information_schema.table_constraints AS tc
+			ON tc.table_schema = s.table_schema
+			AND tc.table_name = s.table_name
+			AND tc.constraint_name = s.index_name
+			AND tc.constraint_type = 'PRIMARY KEY'
+		WHERE s.table_schema = current_schema()
+			AND s.table_name = ?
+			AND s.storing = 'NO'
+			AND s.implicit = 'NO'
+		ORDER BY s.index_name, s.seq_in_index
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&indexes); err != nil {
+		return nil, err
+	}
+	table.Indexes = sqladapter.Indexes(indexes)
+
+	uniques := []sqladapter.ConstraintColumn{}
+	q = `
+		SELECT
+			tc.constraint_name AS constraint_name,
+			kcu.column_name AS column_name
+		FROM information_schema.table_constraints AS tc
+		JOIN information_schema.key_column_usage AS kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.table_name = tc.table_name
+			AND kcu.constraint_name = tc.constraint_name
+		WHERE tc.table_schema = current_schema()
+			AND tc.table_name = ?
+			AND tc.constraint_type = 'UNIQUE'
+		ORDER BY tc.constraint_name, kcu.ordinal_position
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&uniques); err != nil {
+		return nil, err
+	}
+	table.Uniques = sqladapter.UniqueConstraints(uniques)
+
+	fks := []sqladapter.ForeignKeyColumn{}
+	q = `
+		SELECT
+			kcu.constraint_name AS constraint_name,
+			kcu.column_name AS column_name,
+			rc.referenced_table_name AS referenced_table,
+			rcu.column_name AS referenced_column,
+			rc.update_rule AS on_update,
+			rc.delete_rule AS on_delete
+		FROM information_schema.referential_constraints AS rc
+		JOIN information_schema.key_column_usage AS kcu
+			ON kcu.constraint_schema = rc.constraint_schema
+			AND kcu.table_name = rc.table_name
+			AND kcu.constraint_name = rc.constraint_name
+		JOIN information_schema.key_column_usage AS rcu
+			ON rcu.constraint_schema = rc.unique_constraint_schema
+			AND rcu.table_name = rc.referenced_table_name
+			AND rcu.constraint_name = rc.unique_constraint_name
+			AND rcu.ordinal_position = kcu.position_in_unique_constraint
+		WHERE rc.constraint_schema = current_schema()
+			AND rc.table_name = ?
+		ORDER BY kcu.constraint_name, kcu.ordinal_position
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&fks); err != nil {
+		return nil, err
+	}
+	table.ForeignKeys = sqladapter.ForeignKeys(fks)
+
+	return table, nil
+}
+
+func (*database) Views(sess sqladapter.Session) ([]*db.View, error) {
+	rows := []struct {
+		Name       string `db:"table_name"`
+		Definition string `db:"view_definition"`
+	}{}
+
+	err := sess.SQL().
+		Select("table_name", "view_definition").
+		From("information_schema.views").
+		Where("table_schema = current_schema()").
+		OrderBy("table_name").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*db.View, 0, len(rows))
+	for _, row := range rows {
+		columns, err := describeColumns(sess, row.Name)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &db.View{
+			Name:       row.Name,
+			Definition: row.Definition,
+			Columns:    sqladapter.Columns(columns),
+		})
+	}
+
+	return views, nil
+}
+
+func describeColumns(sess sqladapter.Session, tableName string) ([]sqladapter.ColumnDescription, error) {
+	columns := []sqladapter.ColumnDescription{}
+	q := `
+		SELECT
+			column_name AS column_name,
+			crdb_sql_type AS data_type,
+			(is_nullable = 'YES') AS is_nullable,
+			column_default AS column_default,
+			(
+				is_identity = 'YES'
+				OR COALESCE(column_default, '') LIKE 'nextval(%'
+				OR COALESCE(column_default, '') = 'unique_rowid()'
+			) AS is_autoincrement
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+			AND table_name = ?
+			AND is_hidden = 'NO'
+		ORDER BY ordinal_position
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
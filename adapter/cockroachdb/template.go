@@ -147,6 +147,9 @@ const (
     {{else}}
       (default)
     {{end}}
+    {{if defined .OnConflict}}
+      {{.OnConflict | compile}}
+    {{end}}
     {{if defined .Returning}}
       RETURNING {{.Returning | compile}}
     {{end}}
@@ -161,7 +164,7 @@ const (
   `
 
 	adapterDropTableLayout = `
-    DROP TABLE {{.Table | compile}}
+    DROP TABLE {{if .IfExists}}IF EXISTS {{end}}{{.Table | compile}}
   `
 
 	adapterGroupByLayout = `
@@ -169,40 +172,112 @@ const (
       GROUP BY {{.GroupColumns}}
     {{end}}
   `
+
+	adapterTrueKeyword  = `TRUE`
+	adapterFalseKeyword = `FALSE`
+
+	adapterCreateTableLayout = `
+    CREATE TABLE {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Table | compile}} (
+      {{.Definition | compile}}
+    )
+  `
+
+	adapterAlterTableLayout = `
+    {{.Definition | compile}}
+  `
+
+	adapterCreateIndexLayout = `
+    CREATE {{if .Unique}}UNIQUE {{end}}INDEX {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Name | compile}}
+      ON {{.Table | compile}} ({{.Columns | compile}})
+  `
+
+	adapterDropIndexLayout = `
+    DROP INDEX {{if .IfExists}}IF EXISTS {{end}}{{.Name | compile}}
+  `
+
+	adapterColumnDefinitionLayout = `{{.Name}} {{.Type}}{{if .NotNull}} NOT NULL{{end}}{{if .Default}} DEFAULT {{.Default}}{{end}}{{if .Unique}} UNIQUE{{end}}`
+	adapterPrimaryKeyLayout       = `PRIMARY KEY ({{.Columns}})`
+	adapterUniqueLayout           = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}UNIQUE ({{.Columns}})`
+	adapterForeignKeyLayout       = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}FOREIGN KEY ({{.Columns}}) REFERENCES {{.ReferencedTable}} ({{.ReferencedColumns}}){{if .OnUpdate}} ON UPDATE {{.OnUpdate}}{{end}}{{if .OnDelete}} ON DELETE {{.OnDelete}}{{end}}`
+	adapterAddColumnLayout        = `ALTER TABLE {{.Table}} ADD COLUMN {{.Column}}`
+	adapterDropColumnLayout       = `ALTER TABLE {{.Table}} DROP COLUMN {{.Name}}`
+	adapterRenameColumnLayout     = `ALTER TABLE {{.Table}} RENAME COLUMN {{.Name}} TO {{.NewName}}`
+	adapterRenameTableLayout      = `ALTER TABLE {{.Table}} RENAME TO {{.NewName}}`
+	adapterAddConstraintLayout    = `ALTER TABLE {{.Table}} ADD {{.Constraint}}`
+	adapterDropConstraintLayout   = `ALTER TABLE {{.Table}} DROP CONSTRAINT {{.Name}}`
+
+	adapterOnConflictLayout     = `ON CONFLICT ({{.Columns}}) {{if .Update}}DO UPDATE SET {{.Update}} {{.Where}}{{else}}DO NOTHING{{end}}`
+	adapterConflictUpdateLayout = `{{.}} = excluded.{{.}}`
 )
 
+var adapterColumnTypes = map[string]string{
+	"serial":    `SERIAL`,
+	"bigserial": `BIGSERIAL`,
+	"integer":   `INTEGER`,
+	"bigint":    `BIGINT`,
+	"string":    `VARCHAR({{if .Size}}{{.Size}}{{else}}255{{end}})`,
+	"text":      `TEXT`,
+	"boolean":   `BOOLEAN`,
+	"float":     `DOUBLE PRECISION`,
+	"decimal":   `NUMERIC{{if .Precision}}({{.Precision}}, {{.Scale}}){{end}}`,
+	"timestamp": `TIMESTAMPTZ`,
+	"date":      `DATE`,
+	"binary":    `BYTEA`,
+	"json":      `JSONB`,
+	"uuid":      `UUID`,
+}
+
 var template = &exql.Template{
-	ColumnSeparator:     adapterColumnSeparator,
-	IdentifierSeparator: adapterIdentifierSeparator,
-	IdentifierQuote:     adapterIdentifierQuote,
-	ValueSeparator:      adapterValueSeparator,
-	ValueQuote:          adapterValueQuote,
-	AndKeyword:          adapterAndKeyword,
-	OrKeyword:           adapterOrKeyword,
-	DescKeyword:         adapterDescKeyword,
-	AscKeyword:          adapterAscKeyword,
-	AssignmentOperator:  adapterAssignmentOperator,
-	ClauseGroup:         adapterClauseGroup,
-	ClauseOperator:      adapterClauseOperator,
-	ColumnValue:         adapterColumnValue,
-	TableAliasLayout:    adapterTableAliasLayout,
-	ColumnAliasLayout:   adapterColumnAliasLayout,
-	SortByColumnLayout:  adapterSortByColumnLayout,
-	WhereLayout:         adapterWhereLayout,
-	JoinLayout:          adapterJoinLayout,
-	OnLayout:            adapterOnLayout,
-	UsingLayout:         adapterUsingLayout,
-	OrderByLayout:       adapterOrderByLayout,
-	InsertLayout:        adapterInsertLayout,
-	SelectLayout:        adapterSelectLayout,
-	UpdateLayout:        adapterUpdateLayout,
-	DeleteLayout:        adapterDeleteLayout,
-	TruncateLayout:      adapterTruncateLayout,
-	DropDatabaseLayout:  adapterDropDatabaseLayout,
-	DropTableLayout:     adapterDropTableLayout,
-	CountLayout:         adapterSelectCountLayout,
-	GroupByLayout:       adapterGroupByLayout,
-	Cache:               cache.NewCache(),
+	ColumnSeparator:        adapterColumnSeparator,
+	IdentifierSeparator:    adapterIdentifierSeparator,
+	IdentifierQuote:        adapterIdentifierQuote,
+	ValueSeparator:         adapterValueSeparator,
+	ValueQuote:             adapterValueQuote,
+	AndKeyword:             adapterAndKeyword,
+	OrKeyword:              adapterOrKeyword,
+	DescKeyword:            adapterDescKeyword,
+	AscKeyword:             adapterAscKeyword,
+	AssignmentOperator:     adapterAssignmentOperator,
+	ClauseGroup:            adapterClauseGroup,
+	ClauseOperator:         adapterClauseOperator,
+	ColumnValue:            adapterColumnValue,
+	TableAliasLayout:       adapterTableAliasLayout,
+	ColumnAliasLayout:      adapterColumnAliasLayout,
+	SortByColumnLayout:     adapterSortByColumnLayout,
+	WhereLayout:            adapterWhereLayout,
+	JoinLayout:             adapterJoinLayout,
+	OnLayout:               adapterOnLayout,
+	UsingLayout:            adapterUsingLayout,
+	OrderByLayout:          adapterOrderByLayout,
+	InsertLayout:           adapterInsertLayout,
+	SelectLayout:           adapterSelectLayout,
+	UpdateLayout:           adapterUpdateLayout,
+	DeleteLayout:           adapterDeleteLayout,
+	TruncateLayout:         adapterTruncateLayout,
+	DropDatabaseLayout:     adapterDropDatabaseLayout,
+	DropTableLayout:        adapterDropTableLayout,
+	CountLayout:            adapterSelectCountLayout,
+	GroupByLayout:          adapterGroupByLayout,
+	TrueKeyword:            adapterTrueKeyword,
+	FalseKeyword:           adapterFalseKeyword,
+	CreateTableLayout:      adapterCreateTableLayout,
+	AlterTableLayout:       adapterAlterTableLayout,
+	CreateIndexLayout:      adapterCreateIndexLayout,
+	DropIndexLayout:        adapterDropIndexLayout,
+	ColumnDefinitionLayout: adapterColumnDefinitionLayout,
+	PrimaryKeyLayout:       adapterPrimaryKeyLayout,
+	UniqueLayout:           adapterUniqueLayout,
+	ForeignKeyLayout:       adapterForeignKeyLayout,
+	AddColumnLayout:        adapterAddColumnLayout,
+	DropColumnLayout:       adapterDropColumnLayout,
+	RenameColumnLayout:     adapterRenameColumnLayout,
+	RenameTableLayout:      adapterRenameTableLayout,
+	AddConstraintLayout:    adapterAddConstraintLayout,
+	DropConstraintLayout:   adapterDropConstraintLayout,
+	OnConflictLayout:       adapterOnConflictLayout,
+	ConflictUpdateLayout:   adapterConflictUpdateLayout,
+	ColumnTypes:            adapterColumnTypes,
+	Cache:                  cache.NewCache(),
 	ComparisonOperator: map[adapter.ComparisonOperator]string{
 		adapter.ComparisonOperatorRegExp:    "~",
 		adapter.ComparisonOperatorNotRegExp: "!~",
@@ -260,3 +260,41 @@ func TestTemplateDelete(t *testing.T) {
 		b.DeleteFrom("artist").Where("id > 5").String(),
 	)
 }
+
+func TestTemplateDDL(t *testing.T) {
+	b := sqlbuilder.WithTemplate(template)
+	assert := assert.New(t)
+
+	assert.Equal(
+		`CREATE TABLE "artist" ( "id" SERIAL, "name" VARCHAR(60) NOT NULL, "active" BOOLEAN DEFAULT TRUE, PRIMARY KEY ("id") )`,
+		b.CreateTable("artist").
+			Column("id", db.TypeSerial).
+			Column("name", db.TypeString, db.Size(60), db.NotNull()).
+			Column("active", db.TypeBoolean, db.Default(true)).
+			PrimaryKey("id").
+			String(),
+	)
+
+	assert.Equal(
+		`ALTER TABLE "artist" ADD COLUMN "country" VARCHAR(2) DEFAULT 'MX'; ALTER TABLE "artist" RENAME COLUMN "name" TO "full_name"`,
+		b.AlterTable("artist").
+			AddColumn("country", db.TypeString, db.Size(2), db.Default("MX")).
+			RenameColumn("name", "full_name").
+			String(),
+	)
+
+	assert.Equal(
+		`CREATE UNIQUE INDEX IF NOT EXISTS "artist_name_idx" ON "artist" ("name")`,
+		b.CreateIndex("artist_name_idx").On("artist", "name").Unique().IfNotExists().String(),
+	)
+
+	assert.Equal(
+		`DROP INDEX IF EXISTS "artist_name_idx"`,
+		b.DropIndex("artist_name_idx").On("artist").IfExists().String(),
+	)
+
+	assert.Equal(
+		`DROP TABLE IF EXISTS "artist"`,
+		b.DropTable("artist").IfExists().String(),
+	)
+}
@@ -270,6 +270,11 @@ func (col *Collection) UpdateReturning(item interface{}) error {
 func (col *Collection) Insert(item interface{}) (db.InsertResult, error) {
 	var err error
 
+	if err = generateKeys(col.parent.mapper, item); err != nil {
+		return nil, err
+	}
+
+	item = toDocument(col.parent.mapper, item)
 	id := getID(item)
 
 	if col.parent.versionAtLeast(2, 6, 0, 0) {
@@ -312,9 +317,12 @@ func getID(item interface{}) interface{} {
 	case reflect.Map:
 		if inItem, ok := item.(map[string]interface{}); ok {
 			if id, ok := inItem["_id"]; ok {
-				bsonID, ok := id.(bson.ObjectId)
-				if ok {
-					return bsonID
+				if bsonID, ok := id.(bson.ObjectId); ok {
+					if bsonID.Valid() {
+						return bsonID
+					}
+				} else if id != nil {
+					return id
 				}
 			}
 		}
@@ -32,6 +32,8 @@ import (
 	"time"
 
 	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/stores"
 	mgo "gopkg.in/mgo.v2"
 )
 
@@ -53,6 +55,10 @@ type Source struct {
 	version       []int
 	collections   map[string]*Collection
 	collectionsMu sync.Mutex
+
+	mapper *reflectx.Mapper
+
+	declarations *stores.Declarations
 }
 
 type mongoAdapter struct {
@@ -204,19 +210,55 @@ func (s *Source) Save(db.Record) error {
 	return db.ErrNotImplemented
 }
 
+func (s *Source) Reload(db.Record) error {
+	return db.ErrNotImplemented
+}
+
+func (s *Source) Exists(db.Record) (bool, error) {
+	return false, db.ErrNotImplemented
+}
+
+func (s *Source) SaveAll(...db.Record) error {
+	return db.ErrNotImplemented
+}
+
+func (s *Source) DeleteAll(...db.Record) error {
+	return db.ErrNotImplemented
+}
+
 func (s *Source) Context() context.Context {
 	return s.ctx
 }
 
 func (s *Source) WithContext(ctx context.Context) db.Session {
 	return &Source{
-		ctx:      ctx,
-		Settings: s.Settings,
-		name:     s.name,
-		connURL:  s.connURL,
-		session:  s.session,
-		database: s.database,
-		version:  s.version,
+		ctx:          ctx,
+		Settings:     s.Settings,
+		name:         s.name,
+		connURL:      s.connURL,
+		session:      s.session,
+		database:     s.database,
+		version:      s.version,
+		collections:  map[string]*Collection{},
+		mapper:       s.mapper,
+		declarations: s.declarations,
+	}
+}
+
+// WithStores returns a copy of the session that applies the declarations of
+// the given stores to their collections, see db.WithStores.
+func (s *Source) WithStores(stores ...db.Store) db.Session {
+	return &Source{
+		ctx:          s.ctx,
+		Settings:     s.Settings,
+		name:         s.name,
+		connURL:      s.connURL,
+		session:      s.session,
+		database:     s.database,
+		version:      s.version,
+		collections:  map[string]*Collection{},
+		mapper:       s.mapper,
+		declarations: s.declarations.Declare(stores...),
 	}
 }
 
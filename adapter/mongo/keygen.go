@@ -0,0 +1,89 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"reflect"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/records"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// generateKeys sets the empty fields of the given pointer to struct that are
+// tagged with the generate option, like `db:"_id,generate=uuidv7"`, with keys
+// of the named generator. Tags are read by mapper, if not nil.
+func generateKeys(mapper *reflectx.Mapper, item interface{}) error {
+	v := reflect.ValueOf(item)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	if mapper != nil {
+		for _, fi := range mappedFields(mapper, v.Type()) {
+			name, ok := fi.Options["generate"]
+			if !ok || !v.Field(fi.Index[0]).IsZero() {
+				continue
+			}
+			if err := generateKey(v.Field(fi.Index[0]), name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := generatorOf(strings.Split(field.Tag.Get("db"), ",")[1:])
+		if name == "" || !v.Field(i).IsZero() {
+			continue
+		}
+		if err := generateKey(v.Field(i), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateKey sets field with a key of the named generator.
+func generateKey(field reflect.Value, name string) error {
+	key, err := db.GenerateKey(name)
+	if err != nil {
+		return err
+	}
+	return records.SetKeyField(field, key)
+}
+
+// generatorOf returns the name of the key generator given by the generate
+// option.
+func generatorOf(options []string) string {
+	for i := range options {
+		kv := strings.SplitN(strings.TrimSpace(options[i]), "=", 2)
+		if len(kv) == 2 && kv[0] == "generate" {
+			return kv[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"reflect"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// WithFieldMapper returns a copy of the session that maps struct fields to
+// document keys as defined by the given field mapper.
+func (s *Source) WithFieldMapper(fieldMapper *db.FieldMapper) db.Session {
+	return &Source{
+		ctx:          s.ctx,
+		Settings:     s.Settings,
+		name:         s.name,
+		connURL:      s.connURL,
+		session:      s.session,
+		database:     s.database,
+		version:      s.version,
+		collections:  map[string]*Collection{},
+		mapper:       sqlbuilder.NewMapper(*fieldMapper),
+		declarations: s.declarations,
+	}
+}
+
+// bsonKey returns the key the bson package uses for the given field.
+func bsonKey(field reflect.StructField) string {
+	key := strings.Split(field.Tag.Get("bson"), ",")[0]
+	if key == "" {
+		key = strings.ToLower(field.Name)
+	}
+	return key
+}
+
+// mappedFields returns the top-level fields of a struct type that are mapped
+// by mapper.
+func mappedFields(mapper *reflectx.Mapper, t reflect.Type) []*reflectx.FieldInfo {
+	fields := []*reflectx.FieldInfo{}
+	for _, fi := range mapper.TypeMap(t).Index {
+		if len(fi.Index) != 1 || fi.Name == "" || fi.Embedded {
+			continue
+		}
+		fields = append(fields, fi)
+	}
+	return fields
+}
+
+// toDocument returns a document with the mapped fields of item, which is a
+// struct or a pointer to a struct. Other values are returned as they are.
+func toDocument(mapper *reflectx.Mapper, item interface{}) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	if mapper == nil || v.Kind() != reflect.Struct {
+		return item
+	}
+
+	doc := map[string]interface{}{}
+	for _, fi := range mappedFields(mapper, v.Type()) {
+		field := v.Field(fi.Index[0])
+		if _, ok := fi.Options["omitempty"]; ok && field.IsZero() {
+			continue
+		}
+		doc[fi.Name] = field.Interface()
+	}
+	return doc
+}
+
+// fromDocument sets the mapped fields of dst, a pointer to a struct, with the
+// values of the given document.
+func fromDocument(mapper *reflectx.Mapper, doc bson.M, dst interface{}) error {
+	v := reflect.ValueOf(dst).Elem()
+
+	// Keys are renamed to the ones the bson package expects, so values are
+	// converted to the types of the fields by bson.
+	renamed := bson.M{}
+	for _, fi := range mappedFields(mapper, v.Type()) {
+		if value, ok := doc[fi.Name]; ok {
+			renamed[bsonKey(fi.Field)] = value
+		}
+	}
+	data, err := bson.Marshal(renamed)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, dst)
+}
+
+// isMappedStruct reports whether dst points to a struct that must be decoded
+// with the mapper of the session.
+func isMappedStruct(mapper *reflectx.Mapper, dst interface{}) bool {
+	v := reflect.ValueOf(dst)
+	return mapper != nil && v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct
+}
+
+// isMappedSlice reports whether dst points to a slice of structs that must be
+// decoded with the mapper of the session.
+func isMappedSlice(mapper *reflectx.Mapper, dst interface{}) bool {
+	v := reflect.ValueOf(dst)
+	if mapper == nil || v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return false
+	}
+	return reflectx.Deref(v.Elem().Type().Elem()).Kind() == reflect.Struct
+}
+
+// fromDocuments sets the slice dst points to with structs decoded from the
+// given documents.
+func fromDocuments(mapper *reflectx.Mapper, docs []bson.M, dst interface{}) error {
+	slice := reflect.ValueOf(dst).Elem()
+	itemT := slice.Type().Elem()
+	isPtr := itemT.Kind() == reflect.Ptr
+
+	items := reflect.MakeSlice(slice.Type(), 0, len(docs))
+	for i := range docs {
+		item := reflect.New(reflectx.Deref(itemT))
+		if err := fromDocument(mapper, docs[i], item.Interface()); err != nil {
+			return err
+		}
+		if isPtr {
+			items = reflect.Append(items, item)
+		} else {
+			items = reflect.Append(items, item.Elem())
+		}
+	}
+	slice.Set(items)
+	return nil
+}
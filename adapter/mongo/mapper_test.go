@@ -0,0 +1,79 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type mappedTestItem struct {
+	ID        string `json:"_id,omitempty"`
+	FullName  string
+	CreatedAt time.Time
+	Version   int64  `json:"version,optlock"`
+	Ignored   string `json:"-"`
+}
+
+func TestMappedDocuments(t *testing.T) {
+	mapper := reflectx.NewMapperFunc("json", db.SnakeCase)
+
+	created := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	item := mappedTestItem{FullName: "Ada", CreatedAt: created, Version: 2, Ignored: "x"}
+
+	doc := toDocument(mapper, &item)
+	assert.Equal(t, map[string]interface{}{
+		"full_name":  "Ada",
+		"created_at": created,
+		"version":    int64(2),
+	}, doc)
+
+	// Values that are not structs are not converted.
+	assert.Equal(t, bson.M{"a": 1}, toDocument(mapper, bson.M{"a": 1}))
+	assert.Equal(t, &item, toDocument(nil, &item))
+
+	var loaded mappedTestItem
+	err := fromDocument(mapper, bson.M{"_id": "a1", "full_name": "Ada", "created_at": created, "version": 3}, &loaded)
+	assert.NoError(t, err)
+	assert.True(t, created.Equal(loaded.CreatedAt))
+	loaded.CreatedAt = time.Time{}
+	assert.Equal(t, mappedTestItem{ID: "a1", FullName: "Ada", Version: 3}, loaded)
+
+	var items []*mappedTestItem
+	assert.True(t, isMappedSlice(mapper, &items))
+	assert.False(t, isMappedSlice(nil, &items))
+	err = fromDocuments(mapper, []bson.M{{"full_name": "Ada"}, {"full_name": "Grace"}}, &items)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "Grace", items[1].FullName)
+	}
+
+	version := versionFieldOf(mapper, &item)
+	if assert.NotNil(t, version) {
+		assert.Equal(t, "version", version.key)
+	}
+}
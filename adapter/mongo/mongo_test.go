@@ -23,6 +23,7 @@
 package mongo
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -519,6 +520,46 @@ func (s *AdapterTests) TestUpdate() {
 	s.Equal(value.Name, rowT.Value1)
 }
 
+func (s *AdapterTests) TestUpdateWithVersion() {
+	sess, err := Open(settings)
+	s.NoError(err)
+
+	defer sess.Close()
+
+	artist := sess.Collection("artist")
+
+	type versionedArtist struct {
+		ID      bson.ObjectId `bson:"_id"`
+		Name    string        `bson:"name"`
+		Version int           `bson:"version" db:"version,optlock"`
+	}
+
+	id := bson.NewObjectId()
+	_, err = artist.Insert(versionedArtist{ID: id, Name: "Versioned"})
+	s.NoError(err)
+
+	var first, second versionedArtist
+	s.NoError(artist.Find(db.Cond{"_id": id}).One(&first))
+	s.NoError(artist.Find(db.Cond{"_id": id}).One(&second))
+
+	first.Name = "First"
+	err = artist.Find(db.Cond{"_id": id}).Update(&first)
+	s.NoError(err)
+	s.Equal(1, first.Version)
+
+	second.Name = "Second"
+	err = artist.Find(db.Cond{"_id": id}).Update(&second)
+	s.True(errors.Is(err, db.ErrStaleRecord))
+	s.Equal(0, second.Version)
+
+	var stored versionedArtist
+	s.NoError(artist.Find(db.Cond{"_id": id}).One(&stored))
+	s.Equal("First", stored.Name)
+	s.Equal(1, stored.Version)
+
+	s.NoError(artist.Find(db.Cond{"_id": id}).Delete())
+}
+
 func (s *AdapterTests) TestOperators() {
 	// Opening database.
 	sess, err := Open(settings)
@@ -0,0 +1,110 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/upper/db/v4/internal/reflectx"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// versionField is the field of a document used for optimistic locking, it's
+// tagged with the optlock option, like `db:"version,optlock"`.
+type versionField struct {
+	key   string
+	field reflect.Value
+}
+
+// versionFieldOf returns the version field of the given pointer to struct, or
+// nil if it has none. Tags are read by mapper, if not nil.
+func versionFieldOf(mapper *reflectx.Mapper, item interface{}) *versionField {
+	v := reflect.ValueOf(item)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	if mapper != nil {
+		for _, fi := range mappedFields(mapper, v.Type()) {
+			if _, ok := fi.Options["optlock"]; ok && isInteger(fi.Field.Type) {
+				return &versionField{key: fi.Name, field: v.Field(fi.Index[0])}
+			}
+		}
+		return nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		options := strings.Split(field.Tag.Get("db"), ",")
+		if !hasOption(options[1:], "optlock") {
+			continue
+		}
+		if !isInteger(field.Type) {
+			continue
+		}
+		// Documents are marshaled by the bson package, which uses the bson tag
+		// or the lowercased field name as key.
+		return &versionField{key: bsonKey(field), field: v.Field(i)}
+	}
+	return nil
+}
+
+func isInteger(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func hasOption(options []string, option string) bool {
+	for i := range options {
+		if strings.TrimSpace(options[i]) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// increment adds one to the version and returns a function that restores the
+// previous value.
+func (f *versionField) increment() (restore func()) {
+	prev := reflect.ValueOf(f.field.Interface())
+	if f.field.Kind() >= reflect.Uint && f.field.Kind() <= reflect.Uint64 {
+		f.field.SetUint(f.field.Uint() + 1)
+	} else {
+		f.field.SetInt(f.field.Int() + 1)
+	}
+	return func() {
+		f.field.Set(prev)
+	}
+}
+
+// conditions adds a condition on the current version to the given ones.
+func (f *versionField) conditions(conds interface{}) interface{} {
+	version := bson.M{f.key: f.field.Interface()}
+	if conds == nil {
+		return version
+	}
+	return bson.M{"$and": []interface{}{conds, version}}
+}
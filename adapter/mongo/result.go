@@ -36,6 +36,8 @@ import (
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/upper/db/v4/internal/immutable"
+	"github.com/upper/db/v4/internal/records"
+	"github.com/upper/db/v4/internal/reflectx"
 )
 
 type resultQuery struct {
@@ -54,12 +56,16 @@ type resultQuery struct {
 	cursorValue        interface{}
 	cursorCond         db.Cond
 	cursorReverseOrder bool
+
+	scoped bool
 }
 
 type result struct {
-	iter  *mgo.Iter
-	err   error
-	errMu sync.Mutex
+	iter   *mgo.Iter
+	sess   db.Session
+	mapper *reflectx.Mapper
+	err    error
+	errMu  sync.Mutex
 
 	fn   func(*resultQuery) error
 	prev *result
@@ -102,6 +108,57 @@ func (res *result) Where(terms ...interface{}) db.Result {
 	})
 }
 
+func (res *result) Preload(relations ...string) db.Result {
+	return res.frame(func(r *resultQuery) error {
+		return db.ErrNotSupportedByAdapter
+	})
+}
+
+func (res *result) WithDeleted() db.Result {
+	return res
+}
+
+func (res *result) OnlyDeleted() db.Result {
+	return res.frame(func(r *resultQuery) error {
+		return db.ErrNotSupportedByAdapter
+	})
+}
+
+func (res *result) Scope(name string, args ...interface{}) db.Result {
+	rqi, err := immutable.FastForward(res)
+	if err != nil {
+		return res.frame(func(*resultQuery) error {
+			return err
+		})
+	}
+	rq := rqi.(*resultQuery)
+	if rq.c == nil {
+		return res.frame(func(*resultQuery) error {
+			return db.ErrInvalidCollection
+		})
+	}
+	scope, ok := rq.c.parent.declarations.Scope(rq.c.Name(), name)
+	if !ok {
+		return res.frame(func(*resultQuery) error {
+			return fmt.Errorf("%w: %q", db.ErrUnknownScope, name)
+		})
+	}
+	return scope(res, args...)
+}
+
+func (res *result) Strict() db.Result {
+	return res.frame(func(r *resultQuery) error {
+		return db.ErrNotSupportedByAdapter
+	})
+}
+
+func (res *result) Unscoped() db.Result {
+	return res.frame(func(r *resultQuery) error {
+		r.scoped = true
+		return nil
+	})
+}
+
 func (res *result) Paginate(pageSize uint) db.Result {
 	return res.frame(func(r *resultQuery) error {
 		r.pageSize = pageSize
@@ -216,6 +273,12 @@ func (res *result) Select(fields ...interface{}) db.Result {
 	})
 }
 
+// Pluck dumps the values of the given field of all results into a pointer to
+// slice.
+func (res *result) Pluck(field string, dst interface{}) error {
+	return res.Select(field).All(dst)
+}
+
 // All dumps all results into a pointer to an slice of structs or maps.
 func (res *result) All(dst interface{}) error {
 	rq, err := res.build()
@@ -237,11 +300,31 @@ func (res *result) All(dst interface{}) error {
 		})
 	}(time.Now())
 
-	err = q.All(dst)
+	mapper := rq.c.parent.mapper
+	if isScalarSlice(dst) {
+		var field string
+		if field, err = scalarField(rq.fields); err != nil {
+			return err
+		}
+		var docs []bson.Raw
+		if err = q.All(&docs); err == nil {
+			err = fromScalars(docs, field, dst)
+		}
+	} else if isMappedSlice(mapper, dst) {
+		var docs []bson.M
+		if err = q.All(&docs); err == nil {
+			err = fromDocuments(mapper, docs, dst)
+		}
+	} else {
+		err = q.All(dst)
+	}
 	if errors.Is(err, mgo.ErrNotFound) {
 		return db.ErrNoMoreRows
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return records.AfterFind(rq.c.Session(), dst)
 }
 
 // GroupBy is used to group results that have the same value in the same column
@@ -274,11 +357,31 @@ func (res *result) One(dst interface{}) error {
 		})
 	}(time.Now())
 
-	err = q.One(dst)
+	mapper := rq.c.parent.mapper
+	if isScalarPtr(dst) {
+		var field string
+		if field, err = scalarField(rq.fields); err != nil {
+			return err
+		}
+		var doc bson.Raw
+		if err = q.One(&doc); err == nil {
+			err = fromScalar(doc, field, dst)
+		}
+	} else if isMappedStruct(mapper, dst) {
+		var doc bson.M
+		if err = q.One(&doc); err == nil {
+			err = fromDocument(mapper, doc, dst)
+		}
+	} else {
+		err = q.One(dst)
+	}
 	if errors.Is(err, mgo.ErrNotFound) {
 		return db.ErrNoMoreRows
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return records.AfterFind(rq.c.Session(), dst)
 }
 
 func (res *result) Err() error {
@@ -316,13 +419,30 @@ func (res *result) Next(dst interface{}) bool {
 		}(time.Now())
 
 		res.iter = q.Iter()
+		res.sess = rq.c.Session()
+		res.mapper = rq.c.parent.mapper
 	}
 
-	if !res.iter.Next(dst) {
+	if isMappedStruct(res.mapper, dst) {
+		var doc bson.M
+		if !res.iter.Next(&doc) {
+			res.setErr(res.iter.Err())
+			return false
+		}
+		if err := fromDocument(res.mapper, doc, dst); err != nil {
+			res.setErr(err)
+			return false
+		}
+	} else if !res.iter.Next(dst) {
 		res.setErr(res.iter.Err())
 		return false
 	}
 
+	if err := records.AfterFind(res.sess, dst); err != nil {
+		res.setErr(err)
+		return false
+	}
+
 	return true
 }
 
@@ -350,6 +470,16 @@ func (res *result) Delete() error {
 	return nil
 }
 
+// HardDelete is the same as Delete, MongoDB collections are not
+// soft-deletable.
+func (res *result) HardDelete() error {
+	return res.Delete()
+}
+
+func (res *result) Restore() error {
+	return db.ErrNotSupportedByAdapter
+}
+
 // Close closes the result set.
 func (r *result) Close() error {
 	var err error
@@ -363,12 +493,11 @@ func (r *result) Close() error {
 // Update modified matching items from the collection with values of the given
 // map or struct.
 func (res *result) Update(src interface{}) (err error) {
-	updateSet := map[string]interface{}{"$set": src}
-
 	rq, err := res.build()
 	if err != nil {
 		return err
 	}
+	mapper := rq.c.parent.mapper
 
 	defer func(start time.Time) {
 		queryLog(&db.QueryStatus{
@@ -379,6 +508,25 @@ func (res *result) Update(src interface{}) (err error) {
 		})
 	}(time.Now())
 
+	// Structs with a version field are only updated if the version of the
+	// stored document matches, see db.ErrStaleRecord.
+	if version := versionFieldOf(mapper, src); version != nil {
+		conditions := version.conditions(rq.conditions)
+		restore := version.increment()
+
+		updateSet := map[string]interface{}{"$set": toDocument(mapper, src)}
+		info, err := rq.c.collection.UpdateAll(conditions, updateSet)
+		if err == nil && info.Matched == 0 {
+			err = db.ErrStaleRecord
+		}
+		if err != nil {
+			restore()
+			return err
+		}
+		return nil
+	}
+
+	updateSet := map[string]interface{}{"$set": toDocument(mapper, src)}
 	_, err = rq.c.collection.UpdateAll(rq.conditions, updateSet)
 	if err != nil {
 		return err
@@ -393,6 +541,16 @@ func (res *result) build() (*resultQuery, error) {
 	}
 
 	rq := rqi.(*resultQuery)
+	if !rq.scoped && rq.c != nil {
+		if scope, ok := rq.c.parent.declarations.Scope(rq.c.Name(), db.DefaultScope); ok {
+			scoped, ok := scope(res.Unscoped()).(*result)
+			if !ok {
+				return nil, fmt.Errorf("upper: the default scope of %q must return a result of the same collection", rq.c.Name())
+			}
+			return scoped.build()
+		}
+	}
+
 	if !rq.cursorCond.Empty() {
 		if err := rq.and(rq.cursorCond); err != nil {
 			return nil, err
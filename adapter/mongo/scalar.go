@@ -0,0 +1,118 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// isScalar returns true if values of type t are decoded from a single field
+// instead of a whole document.
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
+
+// isScalarPtr returns true if dst is a pointer to a single value.
+func isScalarPtr(dst interface{}) bool {
+	t := reflect.TypeOf(dst)
+	return t != nil && t.Kind() == reflect.Ptr && isScalar(t.Elem())
+}
+
+// isScalarSlice returns true if dst is a pointer to a slice of single values.
+func isScalarSlice(dst interface{}) bool {
+	t := reflect.TypeOf(dst)
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice &&
+		isScalar(t.Elem().Elem())
+}
+
+// scalarField returns the only field that was selected.
+func scalarField(fields []string) (string, error) {
+	if len(fields) != 1 || fields[0] == "*" {
+		return "", db.ErrExpectingSingleColumn
+	}
+	return fields[0], nil
+}
+
+// rawField returns the value of the given field of doc, nested fields are
+// given as dotted paths.
+func rawField(doc bson.Raw, field string) (bson.Raw, bool) {
+	for _, key := range strings.Split(field, ".") {
+		var fields map[string]bson.Raw
+		if err := doc.Unmarshal(&fields); err != nil {
+			return bson.Raw{}, false
+		}
+		value, ok := fields[key]
+		if !ok {
+			return bson.Raw{}, false
+		}
+		doc = value
+	}
+	return doc, true
+}
+
+// fromScalar decodes the given field of doc into dst, which is a pointer to a
+// single value. dst is set to its zero value if doc has no such field.
+func fromScalar(doc bson.Raw, field string, dst interface{}) error {
+	dstv := reflect.ValueOf(dst).Elem()
+	value, ok := rawField(doc, field)
+	if !ok {
+		dstv.Set(reflect.Zero(dstv.Type()))
+		return nil
+	}
+	return value.Unmarshal(dst)
+}
+
+// fromScalars decodes the given field of docs into dst, which is a pointer to
+// a slice of single values.
+func fromScalars(docs []bson.Raw, field string, dst interface{}) error {
+	slicev := reflect.ValueOf(dst).Elem()
+	values := reflect.MakeSlice(slicev.Type(), len(docs), len(docs))
+	for i := range docs {
+		if err := fromScalar(docs[i], field, values.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	slicev.Set(values)
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func rawDocument(t *testing.T, doc bson.M) bson.Raw {
+	data, err := bson.Marshal(doc)
+	assert.NoError(t, err)
+	var raw bson.Raw
+	assert.NoError(t, bson.Unmarshal(data, &raw))
+	return raw
+}
+
+func TestScalars(t *testing.T) {
+	assert.True(t, isScalarSlice(&[]int64{}))
+	assert.True(t, isScalarSlice(&[]bson.ObjectId{}))
+	assert.True(t, isScalarPtr(new(time.Time)))
+	assert.True(t, isScalarPtr(new(*string)))
+	assert.False(t, isScalarSlice(&[]bson.M{}))
+	assert.False(t, isScalarSlice(&[]bson.D{}))
+	assert.False(t, isScalarSlice(&[]interface{}{}))
+	assert.False(t, isScalarPtr(&struct{}{}))
+
+	_, err := scalarField([]string{"name", "age"})
+	assert.Equal(t, db.ErrExpectingSingleColumn, err)
+
+	docs := []bson.Raw{
+		rawDocument(t, bson.M{"age": 31, "address": bson.M{"city": "Lima"}}),
+		rawDocument(t, bson.M{"age": int64(42)}),
+	}
+
+	var ages []int64
+	assert.NoError(t, fromScalars(docs, "age", &ages))
+	assert.Equal(t, []int64{31, 42}, ages)
+
+	var cities []string
+	assert.NoError(t, fromScalars(docs, "address.city", &cities))
+	assert.Equal(t, []string{"Lima", ""}, cities)
+
+	age := 7
+	assert.NoError(t, fromScalar(docs[1], "missing", &age))
+	assert.Equal(t, 0, age)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	db "github.com/upper/db/v4"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// schemaSampleSize is the number of documents that are inspected to guess the
+// fields of a collection.
+const schemaSampleSize = 100
+
+// schema describes MongoDB collections. Since collections have no fixed
+// structure, columns are inferred from a sample of documents.
+type schema struct {
+	sess *Source
+}
+
+// Schema returns a db.Schema that describes the collections of the database.
+func (s *Source) Schema() db.Schema {
+	return &schema{sess: s}
+}
+
+func (s *schema) Tables() ([]*db.Table, error) {
+	names, err := s.sess.database.CollectionNames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	tables := make([]*db.Table, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, "system.") {
+			continue
+		}
+		table, err := s.Table(name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+func (s *schema) Table(name string) (*db.Table, error) {
+	col, ok := s.sess.Collection(name).(*Collection)
+	if !ok {
+		return nil, db.ErrCollectionDoesNotExist
+	}
+	if exists, err := col.Exists(); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, db.ErrCollectionDoesNotExist
+	}
+
+	columns, err := sampleColumns(col.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &db.Table{
+		Name:       name,
+		Columns:    columns,
+		PrimaryKey: []string{"_id"},
+	}
+
+	indexes, err := col.collection.Indexes()
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range indexes {
+		keys := make([]string, 0, len(idx.Key))
+		for _, key := range idx.Key {
+			keys = append(keys, strings.TrimLeft(key, "-+"))
+		}
+		table.Indexes = append(table.Indexes, &db.Index{
+			Name:    idx.Name,
+			Columns: keys,
+			Unique:  idx.Unique || idx.Name == "_id_",
+			Primary: idx.Name == "_id_",
+		})
+		if idx.Unique {
+			table.Uniques = append(table.Uniques, &db.UniqueConstraint{
+				Name:    idx.Name,
+				Columns: keys,
+			})
+		}
+	}
+
+	return table, nil
+}
+
+// Views returns an empty list, views are not described by this adapter.
+func (s *schema) Views() ([]*db.View, error) {
+	return []*db.View{}, nil
+}
+
+// sampleColumns guesses the fields of a collection from its first documents.
+// A field that is missing or null in any of the sampled documents is reported
+// as nullable.
+func sampleColumns(collection *mgo.Collection) ([]*db.Column, error) {
+	var (
+		columns []*db.Column
+		seen    = map[string]*db.Column{}
+		counts  = map[string]int{}
+		total   int
+	)
+
+	iter := collection.Find(nil).Limit(schemaSampleSize).Iter()
+
+	var doc bson.D
+	for iter.Next(&doc) {
+		total++
+		for _, elem := range doc {
+			column, ok := seen[elem.Name]
+			if !ok {
+				column = &db.Column{Name: elem.Name}
+				seen[elem.Name] = column
+				columns = append(columns, column)
+			}
+			if elem.Value == nil {
+				column.Nullable = true
+				continue
+			}
+			counts[elem.Name]++
+			if column.Type == "" {
+				column.Type = bsonType(elem.Value)
+			} else if t := bsonType(elem.Value); t != column.Type {
+				column.Type = "mixed"
+			}
+		}
+		doc = nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, column := range columns {
+		if counts[column.Name] < total {
+			column.Nullable = true
+		}
+	}
+
+	return columns, nil
+}
+
+func bsonType(v interface{}) string {
+	switch v.(type) {
+	case bson.ObjectId:
+		return "objectId"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int32:
+		return "int"
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case bson.Decimal128:
+		return "decimal"
+	case bson.D, bson.M, map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case bson.Binary, []byte:
+		return "binData"
+	case time.Time:
+		return "date"
+	case bson.MongoTimestamp:
+		return "timestamp"
+	case bson.RegEx:
+		return "regex"
+	}
+	return fmt.Sprintf("%T", v)
+}
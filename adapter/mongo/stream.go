@@ -0,0 +1,151 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"reflect"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/records"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// iterate calls fn with a pointer to each result, itemT is the type the
+// results are decoded into. The iterator is always closed.
+func (res *result) iterate(itemT reflect.Type, fn func(item reflect.Value) error) (err error) {
+	rq, err := res.build()
+	if err != nil {
+		return err
+	}
+
+	q, err := rq.query()
+	if err != nil {
+		return err
+	}
+
+	iter := q.Iter()
+	defer func() {
+		if closeErr := iter.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	sess := rq.c.Session()
+	mapper := rq.c.parent.mapper
+
+	field := ""
+	if isScalar(itemT) {
+		if field, err = scalarField(rq.fields); err != nil {
+			return err
+		}
+	}
+
+	ctx := sess.Context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		item := reflect.New(itemT)
+		dst := item.Interface()
+
+		switch {
+		case field != "":
+			var doc bson.Raw
+			if !iter.Next(&doc) {
+				return nil
+			}
+			if err := fromScalar(doc, field, dst); err != nil {
+				return err
+			}
+		case isMappedStruct(mapper, dst):
+			var doc bson.M
+			if !iter.Next(&doc) {
+				return nil
+			}
+			if err := fromDocument(mapper, doc, dst); err != nil {
+				return err
+			}
+		default:
+			if !iter.Next(dst) {
+				return nil
+			}
+		}
+
+		if err := records.AfterFind(sess, dst); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// Each calls fn with each result.
+func (res *result) Each(fn interface{}) error {
+	fnv, argT, err := records.CallbackOf(fn)
+	if err != nil {
+		return err
+	}
+
+	itemT := argT
+	isPtr := argT.Kind() == reflect.Ptr
+	if isPtr {
+		itemT = argT.Elem()
+	}
+
+	return res.iterate(itemT, func(item reflect.Value) error {
+		if isPtr {
+			return records.Call(fnv, item)
+		}
+		return records.Call(fnv, item.Elem())
+	})
+}
+
+// Chunk calls fn with batches of up to size results.
+func (res *result) Chunk(size int, fn interface{}) error {
+	if size < 1 {
+		return db.ErrInvalidChunkSize
+	}
+	fnv, sliceT, err := records.CallbackOf(fn)
+	if err == nil && sliceT.Kind() != reflect.Slice {
+		err = db.ErrInvalidCallback
+	}
+	if err != nil {
+		return err
+	}
+
+	batch := reflect.MakeSlice(sliceT, 0, size)
+	err = res.iterate(sliceT.Elem(), func(item reflect.Value) error {
+		batch = reflect.Append(batch, item.Elem())
+		if batch.Len() < size {
+			return nil
+		}
+		full := batch
+		batch = reflect.MakeSlice(sliceT, 0, size)
+		return records.Call(fnv, full)
+	})
+	if err == nil && batch.Len() > 0 {
+		err = records.Call(fnv, batch)
+	}
+	return err
+}
@@ -156,3 +156,139 @@ func (*database) PrimaryKeys(sess sqladapter.Session, tableName string) ([]strin
 
 	return pk, nil
 }
+
+func (*database) DescribeTable(sess sqladapter.Session, tableName string) (*db.Table, error) {
+	columns, err := describeColumns(sess, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &db.Table{
+		Columns: sqladapter.Columns(columns),
+	}
+
+	indexes := []sqladapter.IndexColumn{}
+	q := `
+		SELECT
+			i.name AS index_name,
+			c.name AS column_name,
+			i.is_unique AS is_unique,
+			i.is_primary_key AS is_primary
+		FROM sys.indexes AS i
+		JOIN sys.index_columns AS ic
+			ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns AS c
+			ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(?)
+			AND ic.is_included_column = 0
+		ORDER BY i.name, ic.key_ordinal
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&indexes); err != nil {
+		return nil, err
+	}
+	table.Indexes = sqladapter.Indexes(indexes)
+
+	uniques := []sqladapter.ConstraintColumn{}
+	q = `
+		SELECT
+			tc.constraint_name AS constraint_name,
+			kcu.column_name AS column_name
+		FROM information_schema.table_constraints AS tc
+		JOIN information_schema.key_column_usage AS kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.constraint_name = tc.constraint_name
+		WHERE tc.table_catalog = DB_NAME()
+			AND tc.table_name = ?
+			AND tc.constraint_type = 'UNIQUE'
+		ORDER BY tc.constraint_name, kcu.ordinal_position
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&uniques); err != nil {
+		return nil, err
+	}
+	table.Uniques = sqladapter.UniqueConstraints(uniques)
+
+	fks := []sqladapter.ForeignKeyColumn{}
+	q = `
+		SELECT
+			fk.name AS constraint_name,
+			c.name AS column_name,
+			OBJECT_NAME(fk.referenced_object_id) AS referenced_table,
+			rc.name AS referenced_column,
+			REPLACE(fk.update_referential_action_desc, '_', ' ') AS on_update,
+			REPLACE(fk.delete_referential_action_desc, '_', ' ') AS on_delete
+		FROM sys.foreign_keys AS fk
+		JOIN sys.foreign_key_columns AS fkc
+			ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.columns AS c
+			ON c.object_id = fkc.parent_object_id AND c.column_id = fkc.parent_column_id
+		JOIN sys.columns AS rc
+			ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE fk.parent_object_id = OBJECT_ID(?)
+		ORDER BY fk.name, fkc.constraint_column_id
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&fks); err != nil {
+		return nil, err
+	}
+	table.ForeignKeys = sqladapter.ForeignKeys(fks)
+
+	return table, nil
+}
+
+func (*database) Views(sess sqladapter.Session) ([]*db.View, error) {
+	rows := []struct {
+		Name       string `db:"table_name"`
+		Definition string `db:"view_definition"`
+	}{}
+
+	err := sess.SQL().
+		Select(`table_name`, `view_definition`).
+		From(`information_schema.views`).
+		Where(`table_catalog`, sess.Name()).
+		OrderBy(`table_name`).
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*db.View, 0, len(rows))
+	for _, row := range rows {
+		columns, err := describeColumns(sess, row.Name)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &db.View{
+			Name:       row.Name,
+			Definition: row.Definition,
+			Columns:    sqladapter.Columns(columns),
+		})
+	}
+
+	return views, nil
+}
+
+func describeColumns(sess sqladapter.Session, tableName string) ([]sqladapter.ColumnDescription, error) {
+	columns := []sqladapter.ColumnDescription{}
+	q := `
+		SELECT
+			c.name AS column_name,
+			TYPE_NAME(c.user_type_id) + CASE
+				WHEN TYPE_NAME(c.user_type_id) IN ('varchar', 'char', 'varbinary', 'binary')
+					THEN '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length AS VARCHAR(10)) END + ')'
+				WHEN TYPE_NAME(c.user_type_id) IN ('nvarchar', 'nchar')
+					THEN '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length / 2 AS VARCHAR(10)) END + ')'
+				WHEN TYPE_NAME(c.user_type_id) IN ('decimal', 'numeric')
+					THEN '(' + CAST(c.precision AS VARCHAR(10)) + ',' + CAST(c.scale AS VARCHAR(10)) + ')'
+				ELSE ''
+			END AS data_type,
+			c.is_nullable AS is_nullable,
+			OBJECT_DEFINITION(c.default_object_id) AS column_default,
+			c.is_identity AS is_autoincrement
+		FROM sys.columns AS c
+		WHERE c.object_id = OBJECT_ID(?)
+		ORDER BY c.column_id
+	`
+	if err := sess.SQL().Iterator(q, tableName).All(&columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
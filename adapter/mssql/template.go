@@ -173,7 +173,7 @@ const (
   `
 
 	adapterDropTableLayout = `
-    DROP TABLE {{.Table | compile}}
+    DROP TABLE {{if .IfExists}}IF EXISTS {{end}}{{.Table | compile}}
   `
 
 	adapterGroupByLayout = `
@@ -181,38 +181,114 @@ const (
       GROUP BY {{.GroupColumns}}
     {{end}}
   `
+
+	adapterTrueKeyword  = `1`
+	adapterFalseKeyword = `0`
+
+	adapterCreateTableLayout = `
+    {{if .IfNotExists}}
+      IF OBJECT_ID('{{.Table | compile}}', 'U') IS NULL
+    {{end}}
+    CREATE TABLE {{.Table | compile}} (
+      {{.Definition | compile}}
+    )
+  `
+
+	adapterAlterTableLayout = `
+    {{.Definition | compile}}
+  `
+
+	adapterCreateIndexLayout = `
+    {{if .IfNotExists}}
+      IF NOT EXISTS (
+        SELECT 1 FROM sys.indexes
+        WHERE object_id = OBJECT_ID('{{.Table | compile}}') AND QUOTENAME(name) = '{{.Name | compile}}'
+      )
+    {{end}}
+    CREATE {{if .Unique}}UNIQUE {{end}}INDEX {{.Name | compile}}
+      ON {{.Table | compile}} ({{.Columns | compile}})
+  `
+
+	adapterDropIndexLayout = `
+    DROP INDEX {{if .IfExists}}IF EXISTS {{end}}{{.Name | compile}} ON {{.Table | compile}}
+  `
+
+	adapterColumnDefinitionLayout = `{{.Name}} {{.Type}}{{if .NotNull}} NOT NULL{{end}}{{if .Default}} DEFAULT {{.Default}}{{end}}{{if .Unique}} UNIQUE{{end}}`
+	adapterPrimaryKeyLayout       = `PRIMARY KEY ({{.Columns}})`
+	adapterUniqueLayout           = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}UNIQUE ({{.Columns}})`
+	adapterForeignKeyLayout       = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}FOREIGN KEY ({{.Columns}}) REFERENCES {{.ReferencedTable}} ({{.ReferencedColumns}}){{if .OnUpdate}} ON UPDATE {{.OnUpdate}}{{end}}{{if .OnDelete}} ON DELETE {{.OnDelete}}{{end}}`
+	adapterAddColumnLayout        = `ALTER TABLE {{.Table}} ADD {{.Column}}`
+	adapterDropColumnLayout       = `ALTER TABLE {{.Table}} DROP COLUMN {{.Name}}`
+	adapterRenameColumnLayout     = `EXEC sp_rename '{{.RawTable}}.{{.RawName}}', '{{.RawNewName}}', 'COLUMN'`
+	adapterRenameTableLayout      = `EXEC sp_rename '{{.RawTable}}', '{{.RawNewName}}'`
+	adapterAddConstraintLayout    = `ALTER TABLE {{.Table}} ADD {{.Constraint}}`
+	adapterDropConstraintLayout   = `ALTER TABLE {{.Table}} DROP CONSTRAINT {{.Name}}`
 )
 
+var adapterColumnTypes = map[string]string{
+	"serial":    `INT IDENTITY(1, 1)`,
+	"bigserial": `BIGINT IDENTITY(1, 1)`,
+	"integer":   `INT`,
+	"bigint":    `BIGINT`,
+	"string":    `NVARCHAR({{if .Size}}{{.Size}}{{else}}255{{end}})`,
+	"text":      `NVARCHAR(MAX)`,
+	"boolean":   `BIT`,
+	"float":     `FLOAT`,
+	"decimal":   `DECIMAL{{if .Precision}}({{.Precision}}, {{.Scale}}){{end}}`,
+	"timestamp": `DATETIME2`,
+	"date":      `DATE`,
+	"binary":    `VARBINARY(MAX)`,
+	"json":      `NVARCHAR(MAX)`,
+	"uuid":      `UNIQUEIDENTIFIER`,
+}
+
 var template = &exql.Template{
-	ColumnSeparator:     adapterColumnSeparator,
-	IdentifierSeparator: adapterIdentifierSeparator,
-	IdentifierQuote:     adapterIdentifierQuote,
-	ValueSeparator:      adapterValueSeparator,
-	ValueQuote:          adapterValueQuote,
-	AndKeyword:          adapterAndKeyword,
-	OrKeyword:           adapterOrKeyword,
-	DescKeyword:         adapterDescKeyword,
-	AscKeyword:          adapterAscKeyword,
-	AssignmentOperator:  adapterAssignmentOperator,
-	ClauseGroup:         adapterClauseGroup,
-	ClauseOperator:      adapterClauseOperator,
-	ColumnValue:         adapterColumnValue,
-	TableAliasLayout:    adapterTableAliasLayout,
-	ColumnAliasLayout:   adapterColumnAliasLayout,
-	SortByColumnLayout:  adapterSortByColumnLayout,
-	WhereLayout:         adapterWhereLayout,
-	JoinLayout:          adapterJoinLayout,
-	OnLayout:            adapterOnLayout,
-	UsingLayout:         adapterUsingLayout,
-	OrderByLayout:       adapterOrderByLayout,
-	InsertLayout:        adapterInsertLayout,
-	SelectLayout:        adapterSelectLayout,
-	UpdateLayout:        adapterUpdateLayout,
-	DeleteLayout:        adapterDeleteLayout,
-	TruncateLayout:      adapterTruncateLayout,
-	DropDatabaseLayout:  adapterDropDatabaseLayout,
-	DropTableLayout:     adapterDropTableLayout,
-	CountLayout:         adapterSelectCountLayout,
-	GroupByLayout:       adapterGroupByLayout,
-	Cache:               cache.NewCache(),
+	ColumnSeparator:        adapterColumnSeparator,
+	IdentifierSeparator:    adapterIdentifierSeparator,
+	IdentifierQuote:        adapterIdentifierQuote,
+	ValueSeparator:         adapterValueSeparator,
+	ValueQuote:             adapterValueQuote,
+	AndKeyword:             adapterAndKeyword,
+	OrKeyword:              adapterOrKeyword,
+	DescKeyword:            adapterDescKeyword,
+	AscKeyword:             adapterAscKeyword,
+	AssignmentOperator:     adapterAssignmentOperator,
+	ClauseGroup:            adapterClauseGroup,
+	ClauseOperator:         adapterClauseOperator,
+	ColumnValue:            adapterColumnValue,
+	TableAliasLayout:       adapterTableAliasLayout,
+	ColumnAliasLayout:      adapterColumnAliasLayout,
+	SortByColumnLayout:     adapterSortByColumnLayout,
+	WhereLayout:            adapterWhereLayout,
+	JoinLayout:             adapterJoinLayout,
+	OnLayout:               adapterOnLayout,
+	UsingLayout:            adapterUsingLayout,
+	OrderByLayout:          adapterOrderByLayout,
+	InsertLayout:           adapterInsertLayout,
+	SelectLayout:           adapterSelectLayout,
+	UpdateLayout:           adapterUpdateLayout,
+	DeleteLayout:           adapterDeleteLayout,
+	TruncateLayout:         adapterTruncateLayout,
+	DropDatabaseLayout:     adapterDropDatabaseLayout,
+	DropTableLayout:        adapterDropTableLayout,
+	CountLayout:            adapterSelectCountLayout,
+	GroupByLayout:          adapterGroupByLayout,
+	TrueKeyword:            adapterTrueKeyword,
+	FalseKeyword:           adapterFalseKeyword,
+	CreateTableLayout:      adapterCreateTableLayout,
+	AlterTableLayout:       adapterAlterTableLayout,
+	CreateIndexLayout:      adapterCreateIndexLayout,
+	DropIndexLayout:        adapterDropIndexLayout,
+	ColumnDefinitionLayout: adapterColumnDefinitionLayout,
+	PrimaryKeyLayout:       adapterPrimaryKeyLayout,
+	UniqueLayout:           adapterUniqueLayout,
+	ForeignKeyLayout:       adapterForeignKeyLayout,
+	AddColumnLayout:        adapterAddColumnLayout,
+	DropColumnLayout:       adapterDropColumnLayout,
+	RenameColumnLayout:     adapterRenameColumnLayout,
+	RenameTableLayout:      adapterRenameTableLayout,
+	AddConstraintLayout:    adapterAddConstraintLayout,
+	DropConstraintLayout:   adapterDropConstraintLayout,
+	ColumnTypes:            adapterColumnTypes,
+	Cache:                  cache.NewCache(),
 }
@@ -244,3 +244,41 @@ func TestTemplateDelete(t *testing.T) {
 		b.DeleteFrom("artist").Where("id > 5").String(),
 	)
 }
+
+func TestTemplateDDL(t *testing.T) {
+	b := sqlbuilder.WithTemplate(template)
+	assert := assert.New(t)
+
+	assert.Equal(
+		`CREATE TABLE [artist] ( [id] INT IDENTITY(1, 1), [name] NVARCHAR(60) NOT NULL, [active] BIT DEFAULT 1, PRIMARY KEY ([id]) )`,
+		b.CreateTable("artist").
+			Column("id", db.TypeSerial).
+			Column("name", db.TypeString, db.Size(60), db.NotNull()).
+			Column("active", db.TypeBoolean, db.Default(true)).
+			PrimaryKey("id").
+			String(),
+	)
+
+	assert.Equal(
+		`ALTER TABLE [artist] ADD [country] NVARCHAR(2) DEFAULT 'MX'; EXEC sp_rename 'artist.name', 'full_name', 'COLUMN'`,
+		b.AlterTable("artist").
+			AddColumn("country", db.TypeString, db.Size(2), db.Default("MX")).
+			RenameColumn("name", "full_name").
+			String(),
+	)
+
+	assert.Equal(
+		`IF NOT EXISTS ( SELECT 1 FROM sys.indexes WHERE object_id = OBJECT_ID('[artist]') AND QUOTENAME(name) = '[artist_name_idx]' ) CREATE UNIQUE INDEX [artist_name_idx] ON [artist] ([name])`,
+		b.CreateIndex("artist_name_idx").On("artist", "name").Unique().IfNotExists().String(),
+	)
+
+	assert.Equal(
+		`DROP INDEX IF EXISTS [artist_name_idx] ON [artist]`,
+		b.DropIndex("artist_name_idx").On("artist").IfExists().String(),
+	)
+
+	assert.Equal(
+		`DROP TABLE IF EXISTS [artist]`,
+		b.DropTable("artist").IfExists().String(),
+	)
+}
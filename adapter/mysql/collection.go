@@ -31,7 +31,11 @@ type collectionAdapter struct {
 }
 
 func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (interface{}, error) {
-	columnNames, columnValues, err := sqlbuilder.Map(item, nil)
+	columnNames, columnValues, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{
+		Mapper: sqlbuilder.MapperOf(col.Session()),
+		Codecs: sqlbuilder.CodecsOf(col.Session()),
+		Table:  col.Name(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -187,3 +187,133 @@ func (*database) PrimaryKeys(sess sqladapter.Session, tableName string) ([]strin
 
 	return pk, nil
 }
+
+func (*database) DescribeTable(sess sqladapter.Session, tableName string) (*db.Table, error) {
+	columns, err := describeColumns(sess, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &db.Table{
+		Columns: sqladapter.Columns(columns),
+	}
+
+	indexes := []sqladapter.IndexColumn{}
+	q := `
+		SELECT
+			index_name AS index_name,
+			column_name AS column_name,
+			(non_unique = 0) AS is_unique,
+			(index_name = 'PRIMARY') AS is_primary
+		FROM information_schema.statistics
+		WHERE table_schema = ?
+			AND table_name = ?
+			AND column_name IS NOT NULL
+		ORDER BY index_name, seq_in_index
+	`
+	if err := sess.SQL().Iterator(q, sess.Name(), tableName).All(&indexes); err != nil {
+		return nil, err
+	}
+	table.Indexes = sqladapter.Indexes(indexes)
+
+	uniques := []sqladapter.ConstraintColumn{}
+	q = `
+		SELECT
+			tc.constraint_name AS constraint_name,
+			kcu.column_name AS column_name
+		FROM information_schema.table_constraints AS tc
+		JOIN information_schema.key_column_usage AS kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.table_name = tc.table_name
+			AND kcu.constraint_name = tc.constraint_name
+		WHERE tc.table_schema = ?
+			AND tc.table_name = ?
+			AND tc.constraint_type = 'UNIQUE'
+		ORDER BY tc.constraint_name, kcu.ordinal_position
+	`
+	if err := sess.SQL().Iterator(q, sess.Name(), tableName).All(&uniques); err != nil {
+		return nil, err
+	}
+	table.Uniques = sqladapter.UniqueConstraints(uniques)
+
+	fks := []sqladapter.ForeignKeyColumn{}
+	q = `
+		SELECT
+			kcu.constraint_name AS constraint_name,
+			kcu.column_name AS column_name,
+			kcu.referenced_table_name AS referenced_table,
+			kcu.referenced_column_name AS referenced_column,
+			rc.update_rule AS on_update,
+			rc.delete_rule AS on_delete
+		FROM information_schema.key_column_usage AS kcu
+		JOIN information_schema.referential_constraints AS rc
+			ON rc.constraint_schema = kcu.constraint_schema
+			AND rc.table_name = kcu.table_name
+			AND rc.constraint_name = kcu.constraint_name
+		WHERE kcu.table_schema = ?
+			AND kcu.table_name = ?
+			AND kcu.referenced_table_name IS NOT NULL
+		ORDER BY kcu.constraint_name, kcu.ordinal_position
+	`
+	if err := sess.SQL().Iterator(q, sess.Name(), tableName).All(&fks); err != nil {
+		return nil, err
+	}
+	table.ForeignKeys = sqladapter.ForeignKeys(fks)
+
+	return table, nil
+}
+
+func (*database) Views(sess sqladapter.Session) ([]*db.View, error) {
+	rows := []struct {
+		Name       string `db:"table_name"`
+		Definition string `db:"view_definition"`
+	}{}
+
+	err := sess.SQL().
+		Select(
+			db.Raw("table_name AS table_name"),
+			db.Raw("view_definition AS view_definition"),
+		).
+		From("information_schema.views").
+		Where("table_schema = ?", sess.Name()).
+		OrderBy("table_name").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*db.View, 0, len(rows))
+	for _, row := range rows {
+		columns, err := describeColumns(sess, row.Name)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &db.View{
+			Name:       row.Name,
+			Definition: row.Definition,
+			Columns:    sqladapter.Columns(columns),
+		})
+	}
+
+	return views, nil
+}
+
+func describeColumns(sess sqladapter.Session, tableName string) ([]sqladapter.ColumnDescription, error) {
+	columns := []sqladapter.ColumnDescription{}
+	q := `
+		SELECT
+			column_name AS column_name,
+			column_type AS data_type,
+			(is_nullable = 'YES') AS is_nullable,
+			column_default AS column_default,
+			(extra LIKE '%auto_increment%') AS is_autoincrement
+		FROM information_schema.columns
+		WHERE table_schema = ?
+			AND table_name = ?
+		ORDER BY ordinal_position
+	`
+	if err := sess.SQL().Iterator(q, sess.Name(), tableName).All(&columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
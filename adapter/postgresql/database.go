@@ -41,6 +41,12 @@ func (*database) Template() *exql.Template {
 	return template
 }
 
+// SupportsReturning reports that statements that insert many rows can return
+// them.
+func (*database) SupportsReturning() bool {
+	return true
+}
+
 func (*database) Collections(sess sqladapter.Session) (collections []string, err error) {
 	q := sess.SQL().
 		Select("table_name").
@@ -199,3 +205,139 @@ func quotedTableName(s string) string {
 	}
 	return strings.Join(chunks, ".")
 }
+
+func (*database) DescribeTable(sess sqladapter.Session, tableName string) (*db.Table, error) {
+	columns, err := describeColumns(sess, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &db.Table{
+		Columns: sqladapter.Columns(columns),
+	}
+
+	indexes := []sqladapter.IndexColumn{}
+	q := `
+		SELECT
+			i.relname AS index_name,
+			a.attname AS column_name,
+			ix.indisunique AS is_unique,
+			ix.indisprimary AS is_primary
+		FROM pg_index AS ix
+		JOIN pg_class AS i ON i.oid = ix.indexrelid
+		JOIN pg_attribute AS a ON a.attrelid = ix.indrelid AND a.attnum = ANY(ix.indkey)
+		WHERE ix.indrelid = '` + quotedTableName(tableName) + `'::regclass
+		ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)
+	`
+	if err := sess.SQL().Iterator(q).All(&indexes); err != nil {
+		return nil, err
+	}
+	table.Indexes = sqladapter.Indexes(indexes)
+
+	uniques := []sqladapter.ConstraintColumn{}
+	q = `
+		SELECT
+			con.conname AS constraint_name,
+			a.attname AS column_name
+		FROM pg_constraint AS con
+		JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS k(attnum, n) ON TRUE
+		JOIN pg_attribute AS a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		WHERE con.conrelid = '` + quotedTableName(tableName) + `'::regclass
+			AND con.contype = 'u'
+		ORDER BY con.conname, k.n
+	`
+	if err := sess.SQL().Iterator(q).All(&uniques); err != nil {
+		return nil, err
+	}
+	table.Uniques = sqladapter.UniqueConstraints(uniques)
+
+	fks := []sqladapter.ForeignKeyColumn{}
+	q = `
+		SELECT
+			con.conname AS constraint_name,
+			a.attname AS column_name,
+			con.confrelid::regclass::text AS referenced_table,
+			ra.attname AS referenced_column,
+			` + referentialAction("con.confupdtype") + ` AS on_update,
+			` + referentialAction("con.confdeltype") + ` AS on_delete
+		FROM pg_constraint AS con
+		JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, n) ON TRUE
+		JOIN pg_attribute AS a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute AS ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+		WHERE con.conrelid = '` + quotedTableName(tableName) + `'::regclass
+			AND con.contype = 'f'
+		ORDER BY con.conname, k.n
+	`
+	if err := sess.SQL().Iterator(q).All(&fks); err != nil {
+		return nil, err
+	}
+	table.ForeignKeys = sqladapter.ForeignKeys(fks)
+
+	return table, nil
+}
+
+func (*database) Views(sess sqladapter.Session) ([]*db.View, error) {
+	rows := []struct {
+		Name       string `db:"table_name"`
+		Definition string `db:"view_definition"`
+	}{}
+
+	err := sess.SQL().
+		Select("table_name", "view_definition").
+		From("information_schema.views").
+		Where("table_schema = current_schema()").
+		OrderBy("table_name").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*db.View, 0, len(rows))
+	for _, row := range rows {
+		columns, err := describeColumns(sess, row.Name)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &db.View{
+			Name:       row.Name,
+			Definition: row.Definition,
+			Columns:    sqladapter.Columns(columns),
+		})
+	}
+
+	return views, nil
+}
+
+func describeColumns(sess sqladapter.Session, tableName string) ([]sqladapter.ColumnDescription, error) {
+	columns := []sqladapter.ColumnDescription{}
+	q := `
+		SELECT
+			a.attname AS column_name,
+			format_type(a.atttypid, a.atttypmod) AS data_type,
+			NOT a.attnotnull AS is_nullable,
+			pg_get_expr(d.adbin, d.adrelid) AS column_default,
+			(a.attidentity <> '' OR COALESCE(pg_get_expr(d.adbin, d.adrelid), '') LIKE 'nextval(%') AS is_autoincrement
+		FROM pg_attribute AS a
+		LEFT JOIN pg_attrdef AS d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = '` + quotedTableName(tableName) + `'::regclass
+			AND a.attnum > 0
+			AND NOT a.attisdropped
+		ORDER BY a.attnum
+	`
+	if err := sess.SQL().Iterator(q).All(&columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// referentialAction converts the single character codes PostgreSQL uses for
+// ON UPDATE and ON DELETE actions into their SQL names.
+func referentialAction(column string) string {
+	return `CASE ` + column + `
+		WHEN 'c' THEN 'CASCADE'
+		WHEN 'n' THEN 'SET NULL'
+		WHEN 'd' THEN 'SET DEFAULT'
+		WHEN 'r' THEN 'RESTRICT'
+		ELSE 'NO ACTION'
+	END`
+}
@@ -81,7 +81,11 @@ func (*collectionAdapter) Find(col sqladapter.Collection, res *sqladapter.Result
 }
 
 func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (interface{}, error) {
-	columnNames, columnValues, err := sqlbuilder.Map(item, nil)
+	columnNames, columnValues, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{
+		Mapper: sqlbuilder.MapperOf(col.Session()),
+		Codecs: sqlbuilder.CodecsOf(col.Session()),
+		Table:  col.Name(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -143,3 +143,105 @@ func (*database) TableExists(sess sqladapter.Session, name string) error {
 func (*database) PrimaryKeys(sess sqladapter.Session, tableName string) ([]string, error) {
 	return []string{"id()"}, nil
 }
+
+func (d *database) DescribeTable(sess sqladapter.Session, tableName string) (*db.Table, error) {
+	columns, err := d.describeColumns(sess, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// id() is an implicit column every QL table has.
+	id := &db.Column{Name: "id()", Type: "int64", AutoIncrement: true}
+
+	table := &db.Table{
+		Columns:     append([]*db.Column{id}, sqladapter.Columns(columns)...),
+		Indexes:     []*db.Index{},
+		Uniques:     []*db.UniqueConstraint{},
+		ForeignKeys: []*db.ForeignKey{},
+	}
+
+	indexes := []sqladapter.IndexColumn{}
+	err = sess.SQL().
+		Select(
+			"Name AS index_name",
+			"ColumnName AS column_name",
+			"IsUnique AS is_unique",
+		).
+		From("__Index").
+		Where("TableName == ?", tableName).
+		OrderBy("index_name").
+		All(&indexes)
+	if err != nil {
+		return nil, err
+	}
+	table.Indexes = sqladapter.Indexes(indexes)
+
+	return table, nil
+}
+
+func (*database) Views(sess sqladapter.Session) ([]*db.View, error) {
+	// QL has no views.
+	return []*db.View{}, nil
+}
+
+func (d *database) describeColumns(sess sqladapter.Session, tableName string) ([]sqladapter.ColumnDescription, error) {
+	rows := []struct {
+		Ordinal int64  `db:"Ordinal"`
+		Name    string `db:"Name"`
+		Type    string `db:"Type"`
+	}{}
+
+	err := sess.SQL().
+		Select("Ordinal", "Name", "Type").
+		From("__Column").
+		Where("TableName == ?", tableName).
+		OrderBy("Ordinal").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// __Column2 holds NOT NULL and DEFAULT constraints, QL creates it only
+	// after the first constraint is defined.
+	constraints := []struct {
+		Name         string `db:"Name"`
+		NotNull      bool   `db:"NotNull"`
+		DefaultValue string `db:"DefaultExpr"`
+	}{}
+
+	switch err := d.TableExists(sess, "__Column2"); err {
+	case nil:
+		err = sess.SQL().
+			Select("Name", "NotNull", "DefaultExpr").
+			From("__Column2").
+			Where("TableName == ?", tableName).
+			All(&constraints)
+		if err != nil {
+			return nil, err
+		}
+	case db.ErrCollectionDoesNotExist:
+	default:
+		return nil, err
+	}
+
+	columns := make([]sqladapter.ColumnDescription, 0, len(rows))
+	for _, row := range rows {
+		column := sqladapter.ColumnDescription{
+			Name:     row.Name,
+			Type:     row.Type,
+			Nullable: true,
+		}
+		for i := range constraints {
+			if constraints[i].Name != row.Name {
+				continue
+			}
+			column.Nullable = !constraints[i].NotNull
+			if constraints[i].DefaultValue != "" {
+				column.Default = &constraints[i].DefaultValue
+			}
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
@@ -163,7 +163,7 @@ const (
   `
 
 	adapterDropTableLayout = `
-    DROP TABLE {{.Table | compile}}
+    DROP TABLE {{if .IfExists}}IF EXISTS {{end}}{{.Table | compile}}
   `
 
 	adapterGroupByLayout = `
@@ -171,40 +171,107 @@ const (
       GROUP BY {{.GroupColumns}}
     {{end}}
   `
+
+	adapterTrueKeyword  = `true`
+	adapterFalseKeyword = `false`
+
+	adapterCreateTableLayout = `
+    CREATE TABLE {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Table | compile}} (
+      {{.Definition | compile}}
+    )
+  `
+
+	adapterAlterTableLayout = `
+    {{.Definition | compile}}
+  `
+
+	adapterCreateIndexLayout = `
+    CREATE {{if .Unique}}UNIQUE {{end}}INDEX {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Name | compile}}
+      ON {{.Table | compile}} ({{.Columns | compile}})
+  `
+
+	adapterDropIndexLayout = `
+    DROP INDEX {{if .IfExists}}IF EXISTS {{end}}{{.Name | compile}}
+  `
+
+	adapterColumnDefinitionLayout = `{{.Name}} {{.Type}}{{if .NotNull}} NOT NULL{{end}}{{if .Default}} DEFAULT {{.Default}}{{end}}`
+	adapterPrimaryKeyLayout       = ``
+	adapterUniqueLayout           = ``
+	adapterForeignKeyLayout       = ``
+	adapterAddColumnLayout        = `ALTER TABLE {{.Table}} ADD {{.Column}}`
+	adapterDropColumnLayout       = `ALTER TABLE {{.Table}} DROP COLUMN {{.Name}}`
+	adapterRenameColumnLayout     = `ALTER TABLE {{.Table}} RENAME COLUMN {{.Name}} TO {{.NewName}}`
+	adapterRenameTableLayout      = `ALTER TABLE {{.Table}} RENAME TO {{.NewName}}`
+	adapterAddConstraintLayout    = `ALTER TABLE {{.Table}} ADD {{.Constraint}}`
+	adapterDropConstraintLayout   = `ALTER TABLE {{.Table}} DROP CONSTRAINT {{.Name}}`
 )
 
+var adapterColumnTypes = map[string]string{
+	"serial":    `int64`,
+	"bigserial": `int64`,
+	"integer":   `int64`,
+	"bigint":    `int64`,
+	"string":    `string`,
+	"text":      `string`,
+	"boolean":   `bool`,
+	"float":     `float64`,
+	"decimal":   `float64`,
+	"timestamp": `time`,
+	"date":      `time`,
+	"binary":    `blob`,
+	"json":      `string`,
+	"uuid":      `string`,
+}
+
 var template = &exql.Template{
-	ColumnSeparator:     adapterColumnSeparator,
-	IdentifierSeparator: adapterIdentifierSeparator,
-	IdentifierQuote:     adapterIdentifierQuote,
-	ValueSeparator:      adapterValueSeparator,
-	ValueQuote:          adapterValueQuote,
-	AndKeyword:          adapterAndKeyword,
-	OrKeyword:           adapterOrKeyword,
-	DescKeyword:         adapterDescKeyword,
-	AscKeyword:          adapterAscKeyword,
-	AssignmentOperator:  adapterAssignmentOperator,
-	ClauseGroup:         adapterClauseGroup,
-	ClauseOperator:      adapterClauseOperator,
-	ColumnValue:         adapterColumnValue,
-	TableAliasLayout:    adapterTableAliasLayout,
-	ColumnAliasLayout:   adapterColumnAliasLayout,
-	SortByColumnLayout:  adapterSortByColumnLayout,
-	WhereLayout:         adapterWhereLayout,
-	JoinLayout:          adapterJoinLayout,
-	OnLayout:            adapterOnLayout,
-	UsingLayout:         adapterUsingLayout,
-	OrderByLayout:       adapterOrderByLayout,
-	InsertLayout:        adapterInsertLayout,
-	SelectLayout:        adapterSelectLayout,
-	UpdateLayout:        adapterUpdateLayout,
-	DeleteLayout:        adapterDeleteLayout,
-	TruncateLayout:      adapterTruncateLayout,
-	DropDatabaseLayout:  adapterDropDatabaseLayout,
-	DropTableLayout:     adapterDropTableLayout,
-	CountLayout:         adapterSelectCountLayout,
-	GroupByLayout:       adapterGroupByLayout,
-	Cache:               cache.NewCache(),
+	ColumnSeparator:        adapterColumnSeparator,
+	IdentifierSeparator:    adapterIdentifierSeparator,
+	IdentifierQuote:        adapterIdentifierQuote,
+	ValueSeparator:         adapterValueSeparator,
+	ValueQuote:             adapterValueQuote,
+	AndKeyword:             adapterAndKeyword,
+	OrKeyword:              adapterOrKeyword,
+	DescKeyword:            adapterDescKeyword,
+	AscKeyword:             adapterAscKeyword,
+	AssignmentOperator:     adapterAssignmentOperator,
+	ClauseGroup:            adapterClauseGroup,
+	ClauseOperator:         adapterClauseOperator,
+	ColumnValue:            adapterColumnValue,
+	TableAliasLayout:       adapterTableAliasLayout,
+	ColumnAliasLayout:      adapterColumnAliasLayout,
+	SortByColumnLayout:     adapterSortByColumnLayout,
+	WhereLayout:            adapterWhereLayout,
+	JoinLayout:             adapterJoinLayout,
+	OnLayout:               adapterOnLayout,
+	UsingLayout:            adapterUsingLayout,
+	OrderByLayout:          adapterOrderByLayout,
+	InsertLayout:           adapterInsertLayout,
+	SelectLayout:           adapterSelectLayout,
+	UpdateLayout:           adapterUpdateLayout,
+	DeleteLayout:           adapterDeleteLayout,
+	TruncateLayout:         adapterTruncateLayout,
+	DropDatabaseLayout:     adapterDropDatabaseLayout,
+	DropTableLayout:        adapterDropTableLayout,
+	CountLayout:            adapterSelectCountLayout,
+	GroupByLayout:          adapterGroupByLayout,
+	TrueKeyword:            adapterTrueKeyword,
+	FalseKeyword:           adapterFalseKeyword,
+	CreateTableLayout:      adapterCreateTableLayout,
+	AlterTableLayout:       adapterAlterTableLayout,
+	CreateIndexLayout:      adapterCreateIndexLayout,
+	DropIndexLayout:        adapterDropIndexLayout,
+	ColumnDefinitionLayout: adapterColumnDefinitionLayout,
+	PrimaryKeyLayout:       adapterPrimaryKeyLayout,
+	UniqueLayout:           adapterUniqueLayout,
+	ForeignKeyLayout:       adapterForeignKeyLayout,
+	AddColumnLayout:        adapterAddColumnLayout,
+	DropColumnLayout:       adapterDropColumnLayout,
+	RenameColumnLayout:     adapterRenameColumnLayout,
+	RenameTableLayout:      adapterRenameTableLayout,
+	AddConstraintLayout:    adapterAddConstraintLayout,
+	DropConstraintLayout:   adapterDropConstraintLayout,
+	ColumnTypes:            adapterColumnTypes,
+	Cache:                  cache.NewCache(),
 	ComparisonOperator: map[adapter.ComparisonOperator]string{
 		adapter.ComparisonOperatorEqual:     "==",
 		adapter.ComparisonOperatorNotLike:   "!(:column LIKE ?)",
@@ -28,6 +28,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite3 driver.
 	db "github.com/upper/db/v4"
@@ -44,6 +45,12 @@ func (*database) Template() *exql.Template {
 	return template
 }
 
+// SupportsReturning reports that statements that insert many rows can return
+// them, which SQLite does since 3.35.
+func (*database) SupportsReturning() bool {
+	return true
+}
+
 func (*database) OpenDSN(sess sqladapter.Session, dsn string) (*sql.DB, error) {
 	return sql.Open("sqlite3", dsn)
 }
@@ -186,3 +193,160 @@ func (*database) PrimaryKeys(sess sqladapter.Session, tableName string) ([]strin
 
 	return pk, nil
 }
+
+func (*database) DescribeTable(sess sqladapter.Session, tableName string) (*db.Table, error) {
+	columns, err := describeColumns(sess, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &db.Table{
+		Columns:     sqladapter.Columns(columns),
+		Indexes:     []*db.Index{},
+		Uniques:     []*db.UniqueConstraint{},
+		ForeignKeys: []*db.ForeignKey{},
+	}
+
+	indexList := []struct {
+		Name   string `db:"name"`
+		Unique bool   `db:"unique"`
+		Origin string `db:"origin"`
+	}{}
+	if err := queryPragma(sess, "INDEX_LIST", tableName, &indexList); err != nil {
+		return nil, err
+	}
+
+	for _, index := range indexList {
+		indexInfo := []struct {
+			Name string `db:"name"`
+		}{}
+		if err := queryPragma(sess, "INDEX_INFO", index.Name, &indexInfo); err != nil {
+			return nil, err
+		}
+
+		idx := &db.Index{
+			Name:    index.Name,
+			Unique:  index.Unique,
+			Primary: index.Origin == "pk",
+			Columns: make([]string, 0, len(indexInfo)),
+		}
+		for i := range indexInfo {
+			idx.Columns = append(idx.Columns, indexInfo[i].Name)
+		}
+		table.Indexes = append(table.Indexes, idx)
+
+		if index.Origin == "u" {
+			table.Uniques = append(table.Uniques, &db.UniqueConstraint{
+				Name:    index.Name,
+				Columns: idx.Columns,
+			})
+		}
+	}
+
+	fkList := []struct {
+		ID       int     `db:"id"`
+		Table    string  `db:"table"`
+		From     string  `db:"from"`
+		To       *string `db:"to"`
+		OnUpdate string  `db:"on_update"`
+		OnDelete string  `db:"on_delete"`
+	}{}
+	if err := queryPragma(sess, "FOREIGN_KEY_LIST", tableName, &fkList); err != nil {
+		return nil, err
+	}
+
+	fkColumns := make([]sqladapter.ForeignKeyColumn, 0, len(fkList))
+	for _, fk := range fkList {
+		column := sqladapter.ForeignKeyColumn{
+			Constraint:      fmt.Sprintf("%s_fk_%d", tableName, fk.ID),
+			Column:          fk.From,
+			ReferencedTable: fk.Table,
+			OnUpdate:        fk.OnUpdate,
+			OnDelete:        fk.OnDelete,
+		}
+		if fk.To != nil {
+			column.ReferencedColumn = *fk.To
+		}
+		fkColumns = append(fkColumns, column)
+	}
+	table.ForeignKeys = sqladapter.ForeignKeys(fkColumns)
+
+	return table, nil
+}
+
+func (*database) Views(sess sqladapter.Session) ([]*db.View, error) {
+	rows := []struct {
+		Name string `db:"name"`
+		SQL  string `db:"sql"`
+	}{}
+
+	err := sess.SQL().
+		Select("name", "sql").
+		From("sqlite_master").
+		Where("type = ?", "view").
+		OrderBy("name").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*db.View, 0, len(rows))
+	for _, row := range rows {
+		columns, err := describeColumns(sess, row.Name)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &db.View{
+			Name:       row.Name,
+			Definition: row.SQL,
+			Columns:    sqladapter.Columns(columns),
+		})
+	}
+
+	return views, nil
+}
+
+func describeColumns(sess sqladapter.Session, tableName string) ([]sqladapter.ColumnDescription, error) {
+	tableInfo := []struct {
+		Name         string  `db:"name"`
+		Type         string  `db:"type"`
+		NotNull      bool    `db:"notnull"`
+		DefaultValue *string `db:"dflt_value"`
+		PK           int     `db:"pk"`
+	}{}
+	if err := queryPragma(sess, "TABLE_INFO", tableName, &tableInfo); err != nil {
+		return nil, err
+	}
+
+	pkColumns := 0
+	for i := range tableInfo {
+		if tableInfo[i].PK > 0 {
+			pkColumns++
+		}
+	}
+
+	columns := make([]sqladapter.ColumnDescription, 0, len(tableInfo))
+	for _, column := range tableInfo {
+		columns = append(columns, sqladapter.ColumnDescription{
+			Name:     column.Name,
+			Type:     column.Type,
+			Nullable: !column.NotNull && column.PK == 0,
+			Default:  column.DefaultValue,
+			// A single INTEGER PRIMARY KEY column is an alias for the ROWID.
+			AutoIncrement: pkColumns == 1 && column.PK > 0 && strings.EqualFold(column.Type, "integer"),
+		})
+	}
+
+	return columns, nil
+}
+
+func queryPragma(sess sqladapter.Session, pragma string, name string, dst interface{}) error {
+	stmt := exql.RawSQL(fmt.Sprintf("PRAGMA %s('%s')", pragma, strings.Replace(name, "'", "''", -1)))
+
+	rows, err := sess.SQL().Query(stmt)
+	if err != nil {
+		return err
+	}
+
+	return sess.SQL().NewIterator(rows).All(dst)
+}
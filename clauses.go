@@ -274,6 +274,17 @@ type Inserter interface {
 	// RETURNING may not be supported by all SQL databases.
 	Returning(columns ...string) Inserter
 
+	// OnConflictUpdate turns the statement into an upsert: inserted rows that
+	// conflict with existing rows on the given unique columns update the given
+	// columns of those rows with their values instead. Conflicting rows are
+	// left as they are and the inserted rows are skipped if no columns to
+	// update are given.
+	//
+	// MySQL ignores the conflict columns and uses any unique key, and skips
+	// rows with INSERT IGNORE, which also ignores other errors. Databases
+	// without upserts fail with ErrNotSupportedByAdapter.
+	OnConflictUpdate(columns []string, update ...string) Inserter
+
 	// Iterator provides methods to iterate over the results returned by the
 	// Inserter. This is only possible when using Returning().
 	Iterator() Iterator
@@ -460,11 +471,21 @@ type ResultMapper interface {
 	// key and the values of the result will be set as values for the keys.
 	//
 	// Depending on the type of map key and value, the results columns and values
-	// may need to be transformed.
+	// may need to be transformed. Values of a map[string]interface{} are
+	// normalized to the same Go types on every adapter, according to the type
+	// of their column: string, int64, float64, bool, time.Time, string for
+	// decimals, json.RawMessage for JSON and []byte for binary data.
 	//
 	// If dest if a pointer to struct, each one of the fields will be tested for
 	// a `db` tag which defines the column mapping. The value of the result will
-	// be set as the value of the field.
+	// be set as the value of the field. Columns named like "author.name" or
+	// "author__name" are set on the name field of the struct field tagged
+	// `db:"author"`, which is left nil if it's a pointer and all its columns are
+	// NULL.
+	//
+	// If dest is a pointer to any other type, like *int64, *time.Time or a
+	// pointer to a sql.Scanner, the result must have exactly one column and
+	// its value is scanned into dest.
 	One(dest interface{}) error
 }
 
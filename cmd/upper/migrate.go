@@ -0,0 +1,105 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/migrate"
+)
+
+func migrateNew(w io.Writer, opts options, name string) error {
+	paths, err := migrate.Create(opts.dir, name, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		fmt.Fprintf(w, "created %s\n", path)
+	}
+	return nil
+}
+
+func migrateCommand(w io.Writer, opts options, adapter string, sess db.Session, args []string) error {
+	migrations, err := migrate.Load(opts.dir)
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.New(adapter, sess, migrations)
+	if err != nil {
+		return err
+	}
+	if opts.table != "" {
+		m.SetTable(opts.table)
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := parseCount(args[1:])
+		if err != nil {
+			return err
+		}
+		applied, err := m.Up(n)
+		for _, migration := range applied {
+			fmt.Fprintf(w, "applied %s\n", migration)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(w, "no pending migrations")
+		}
+		return err
+	case "down":
+		n, err := parseCount(args[1:])
+		if err != nil {
+			return err
+		}
+		reverted, err := m.Down(n)
+		for _, migration := range reverted {
+			fmt.Fprintf(w, "reverted %s\n", migration)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Fprintln(w, "no applied migrations")
+		}
+		return err
+	case "status":
+		status, err := m.Status()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, st := range status {
+			appliedAt := "pending"
+			if st.Applied {
+				appliedAt = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", st.Version, st.Name, appliedAt)
+		}
+		return tw.Flush()
+	}
+
+	return flag.ErrHelp
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/adapter/cockroachdb"
+	"github.com/upper/db/v4/adapter/mongo"
+	"github.com/upper/db/v4/adapter/mssql"
+	"github.com/upper/db/v4/adapter/mysql"
+	"github.com/upper/db/v4/adapter/postgresql"
+	"github.com/upper/db/v4/adapter/ql"
+	"github.com/upper/db/v4/adapter/sqlite"
+)
+
+// parsers maps adapter names to the ParseURL function each adapter provides.
+var parsers = map[string]func(string) (db.ConnectionURL, error){
+	cockroachdb.Adapter: func(s string) (db.ConnectionURL, error) {
+		return cockroachdb.ParseURL(s)
+	},
+	mongo.Adapter: func(s string) (db.ConnectionURL, error) {
+		return mongo.ParseURL(s)
+	},
+	mssql.Adapter: func(s string) (db.ConnectionURL, error) {
+		return mssql.ParseURL(s)
+	},
+	mysql.Adapter: func(s string) (db.ConnectionURL, error) {
+		return mysql.ParseURL(s)
+	},
+	postgresql.Adapter: func(s string) (db.ConnectionURL, error) {
+		return postgresql.ParseURL(s)
+	},
+	ql.Adapter: func(s string) (db.ConnectionURL, error) {
+		return ql.ParseURL(s)
+	},
+	sqlite.Adapter: func(s string) (db.ConnectionURL, error) {
+		return sqlite.ParseURL(s)
+	},
+}
+
+// schemes maps URL schemes to adapter names, only schemes that identify a
+// single adapter are listed here.
+var schemes = map[string]string{
+	"postgres":   postgresql.Adapter,
+	"postgresql": postgresql.Adapter,
+	"mongodb":    mongo.Adapter,
+	"sqlserver":  mssql.Adapter,
+	"mssql":      mssql.Adapter,
+	"memory":     ql.Adapter,
+	"file":       sqlite.Adapter,
+}
+
+// detectAdapter guesses the adapter name from the scheme of the connection
+// URL.
+func detectAdapter(connURL string) (string, error) {
+	if i := strings.Index(connURL, "://"); i > 0 {
+		if adapter, ok := schemes[connURL[:i]]; ok {
+			return adapter, nil
+		}
+	}
+	return "", fmt.Errorf("could not guess the adapter for %q, use -adapter", connURL)
+}
+
+// open parses the connection URL with the adapter's own ParseURL and opens a
+// session.
+func open(adapter string, connURL string) (string, db.Session, error) {
+	if connURL == "" {
+		return "", nil, db.ErrMissingConnURL
+	}
+
+	if adapter == "" {
+		var err error
+		if adapter, err = detectAdapter(connURL); err != nil {
+			return "", nil, err
+		}
+	}
+
+	parseURL, ok := parsers[adapter]
+	if !ok {
+		return "", nil, fmt.Errorf("%w %q", db.ErrMissingAdapter, adapter)
+	}
+
+	settings, err := parseURL(connURL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sess, err := db.Open(adapter, settings)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return adapter, sess, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	db "github.com/upper/db/v4"
+)
+
+func collectionNames(sess db.Session) ([]string, error) {
+	collections, err := sess.Collections()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(collections))
+	for i := range collections {
+		names = append(names, collections[i].Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func listCollections(w io.Writer, sess db.Session) error {
+	names, err := collectionNames(sess)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+func schemaDump(w io.Writer, sess db.Session) error {
+	tables, err := sess.Schema().Tables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		fmt.Fprintf(w, "%s\n", table.Name)
+		for _, column := range table.Columns {
+			fmt.Fprintf(w, "  %s %s", column.Name, column.Type)
+			if !column.Nullable {
+				fmt.Fprint(w, " not null")
+			}
+			if column.AutoIncrement {
+				fmt.Fprint(w, " auto increment")
+			}
+			if column.Default != nil {
+				fmt.Fprintf(w, " default %s", *column.Default)
+			}
+			fmt.Fprintln(w)
+		}
+		if len(table.PrimaryKey) > 0 {
+			fmt.Fprintf(w, "  primary key (%s)\n", strings.Join(table.PrimaryKey, ", "))
+		}
+		for _, index := range table.Indexes {
+			if index.Primary {
+				continue
+			}
+			kind := "index"
+			if index.Unique {
+				kind = "unique index"
+			}
+			fmt.Fprintf(w, "  %s %s (%s)\n", kind, index.Name, strings.Join(index.Columns, ", "))
+		}
+		for _, fk := range table.ForeignKeys {
+			fmt.Fprintf(w, "  foreign key %s (%s) references %s (%s)",
+				fk.Name, strings.Join(fk.Columns, ", "), fk.ReferencedTable, strings.Join(fk.ReferencedColumns, ", "))
+			if fk.OnUpdate != "" {
+				fmt.Fprintf(w, " on update %s", fk.OnUpdate)
+			}
+			if fk.OnDelete != "" {
+				fmt.Fprintf(w, " on delete %s", fk.OnDelete)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
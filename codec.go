@@ -0,0 +1,190 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// CodecJSON is the name of the built-in codec that stores values as JSON
+// text, see RegisterCodec.
+const CodecJSON = "json"
+
+// Codec encodes Go values into values the database driver accepts and decodes
+// the values read from the database back into Go values.
+type Codec interface {
+	// Encode returns the value that is stored in the database for v.
+	Encode(v interface{}) (interface{}, error)
+
+	// Decode sets the value dst points to from the value src read from the
+	// database, src is nil for NULL.
+	Decode(src interface{}, dst interface{}) error
+}
+
+// Codecs maps Go types to the codecs that encode and decode their values, see
+// WithCodecs. The zero value is ready to use.
+type Codecs struct {
+	types sync.Map
+
+	mu        sync.RWMutex
+	keys      KeyProvider
+	encrypted map[string]map[string]bool
+}
+
+// Register sets the codec of the values of the type of v, like
+// decimal.Decimal{}. The codec is also used for pointers to that type. A nil
+// codec removes the codec of that type.
+func (c *Codecs) Register(v interface{}, codec Codec) {
+	t := reflect.TypeOf(v)
+	if codec == nil {
+		c.types.Delete(t)
+		return
+	}
+	c.types.Store(t, codec)
+}
+
+// Lookup returns the codec of the given type, or nil if there's none.
+func (c *Codecs) Lookup(t reflect.Type) Codec {
+	if c == nil || t == nil {
+		return nil
+	}
+	if codec, ok := c.types.Load(t); ok {
+		return codec.(Codec)
+	}
+	return nil
+}
+
+// SetKeyProvider sets the provider of the keys that encrypt the fields tagged
+// with the encrypted option.
+//
+// Fields tagged like `db:"ssn,encrypted"` are encrypted with AES-GCM and a
+// random nonce, so they can't be used in conditions. Fields tagged like
+// `db:"email,encrypted=deterministic"` are encrypted with a nonce derived from
+// the value, so equal values have equal ciphertexts and the column can be
+// compared for equality with a Cond, like db.Cond{"email": "a@example.com"},
+// at the cost of revealing which rows have equal values. Such conditions only
+// match values encrypted with the current key.
+//
+// Values are stored as text prefixed with the id of their key, so keys can be
+// rotated by changing the current key of the provider while it keeps
+// providing the previous keys.
+func (c *Codecs) SetKeyProvider(keys KeyProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keys = keys
+}
+
+// KeyProvider returns the provider of the keys that encrypt the fields tagged
+// with the encrypted option, or nil if there's none.
+func (c *Codecs) KeyProvider() KeyProvider {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.keys
+}
+
+// WithCodecs returns a copy of sess that encodes and decodes the values of the
+// types registered in codecs with their codecs, when inserting and updating
+// values and loading results.
+//
+// Example:
+//
+//  codecs := &db.Codecs{}
+//  codecs.Register(decimal.Decimal{}, decimalCodec)
+//  sess, err = db.WithCodecs(sess, codecs)
+func WithCodecs(sess Session, codecs *Codecs) (Session, error) {
+	if s, ok := sess.(interface {
+		WithCodecs(*Codecs) Session
+	}); ok {
+		return s.WithCodecs(codecs), nil
+	}
+	return nil, ErrNotSupportedByAdapter
+}
+
+var codecRegistry = struct {
+	sync.RWMutex
+	codecs map[string]Codec
+}{codecs: map[string]Codec{
+	CodecJSON: jsonCodec{},
+}}
+
+// RegisterCodec registers a codec with the given name, the built-in codecs can
+// be replaced. A nil codec removes the codec registered with that name.
+//
+// Fields tagged with the codec option are encoded and decoded with the named
+// codec instead of the codec of their type:
+//
+//  type Event struct {
+//    ID      uint64  `db:"id,omitempty"`
+//    Payload Payload `db:"payload,codec=json"`
+//  }
+func RegisterCodec(name string, codec Codec) {
+	codecRegistry.Lock()
+	defer codecRegistry.Unlock()
+
+	if codec == nil {
+		delete(codecRegistry.codecs, name)
+		return
+	}
+	codecRegistry.codecs[name] = codec
+}
+
+// LookupCodec returns the codec registered with the given name.
+func LookupCodec(name string) (Codec, error) {
+	codecRegistry.RLock()
+	codec, ok := codecRegistry.codecs[name]
+	codecRegistry.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCodec, name)
+	}
+	return codec, nil
+}
+
+// jsonCodec stores values as JSON text.
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+func (jsonCodec) Decode(src interface{}, dst interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	}
+	return fmt.Errorf("upper: can't decode %T as JSON", src)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONCodec(t *testing.T) {
+	codec, err := LookupCodec(CodecJSON)
+	assert.NoError(t, err)
+
+	value, err := codec.Encode(map[string]int{"a": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, value)
+
+	var decoded map[string]int
+	assert.NoError(t, codec.Decode([]byte(`{"b":2}`), &decoded))
+	assert.Equal(t, map[string]int{"b": 2}, decoded)
+
+	decoded = nil
+	assert.NoError(t, codec.Decode(nil, &decoded))
+	assert.Nil(t, decoded)
+
+	assert.Error(t, codec.Decode(int64(1), &decoded))
+
+	_, err = LookupCodec("unknown")
+	assert.True(t, errors.Is(err, ErrUnknownCodec))
+}
+
+func TestCodecs(t *testing.T) {
+	type money int64
+
+	codec, _ := LookupCodec(CodecJSON)
+
+	var codecs *Codecs
+	assert.Nil(t, codecs.Lookup(reflect.TypeOf(money(0))))
+
+	codecs = &Codecs{}
+	codecs.Register(money(0), codec)
+	assert.Equal(t, codec, codecs.Lookup(reflect.TypeOf(money(0))))
+	assert.Nil(t, codecs.Lookup(reflect.TypeOf(int64(0))))
+
+	codecs.Register(money(0), nil)
+	assert.Nil(t, codecs.Lookup(reflect.TypeOf(money(0))))
+}
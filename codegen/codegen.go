@@ -0,0 +1,315 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package codegen generates Go structs and stores from the schema of a
+// database.
+//
+// For every table a struct with `db` tags is generated, along with a Store
+// method that makes it a db.Record and a typed store that wraps the table's
+// db.Collection:
+//
+//  // Account represents a row of the "accounts" table.
+//  type Account struct {
+//    ID   int64  `db:"id,omitempty"`
+//    Name string `db:"name"`
+//  }
+//
+//  func (*Account) Store(sess db.Session) db.Store {
+//    return Accounts(sess)
+//  }
+//
+//  // AccountStore provides typed access to the "accounts" table.
+//  type AccountStore struct {
+//    db.Collection
+//  }
+//
+//  func Accounts(sess db.Session) *AccountStore {
+//    return &AccountStore{sess.Collection("accounts")}
+//  }
+//
+// Columns that have a default value or are auto incremented are tagged with
+// omitempty, so the database can fill them in, and nullable columns are
+// mapped to pointers.
+//
+// Diff goes the other way around and reports how existing structs drifted
+// from the database, DDL turns the missing tables and columns it finds into
+// statements.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	db "github.com/upper/db/v4"
+)
+
+// DefaultPackage is the name of the package generated code belongs to when
+// no other name is given.
+const DefaultPackage = `models`
+
+// Options configures the generated code.
+type Options struct {
+	// Package is the name of the package of the generated file.
+	Package string
+
+	// Adapter is the name of the adapter the schema was read from, it's used
+	// to choose adapter specific types like postgresql.JSONB.
+	Adapter string
+
+	// Tables limits code generation to the given tables, all tables are
+	// generated if empty.
+	Tables []string
+}
+
+// Generate reads the schema of the database sess is connected to and returns
+// gofmt'ed Go source code for its tables.
+func Generate(sess db.Session, opts Options) ([]byte, error) {
+	var tables []*db.Table
+	if len(opts.Tables) > 0 {
+		for _, name := range opts.Tables {
+			table, err := sess.Schema().Table(name)
+			if err != nil {
+				return nil, fmt.Errorf("upper: could not describe table %q: %w", name, err)
+			}
+			tables = append(tables, table)
+		}
+	} else {
+		all, err := sess.Schema().Tables()
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range all {
+			// SQLite keeps internal tables like sqlite_sequence along with
+			// user tables.
+			if opts.Adapter == "sqlite" && strings.HasPrefix(table.Name, "sqlite_") {
+				continue
+			}
+			tables = append(tables, table)
+		}
+	}
+	return Render(tables, opts)
+}
+
+// Render returns gofmt'ed Go source code for the given table definitions.
+func Render(tables []*db.Table, opts Options) ([]byte, error) {
+	file := fileT{
+		Package: opts.Package,
+	}
+	if file.Package == "" {
+		file.Package = DefaultPackage
+	}
+
+	imports := map[string]bool{
+		"github.com/upper/db/v4": true,
+	}
+	names := map[string]string{}
+
+	for _, table := range tables {
+		record := newRecord(opts.Adapter, table)
+		for _, name := range []string{record.Name, record.Store, record.Constructor} {
+			if other, ok := names[name]; ok {
+				return nil, fmt.Errorf("upper: tables %q and %q are both mapped to %s", other, table.Name, name)
+			}
+			names[name] = table.Name
+		}
+		for _, field := range record.Fields {
+			if field.Import != "" {
+				imports[field.Import] = true
+			}
+		}
+		file.Records = append(file.Records, record)
+	}
+
+	// Standard library packages go first, in a group of their own.
+	var std, other []string
+	for path := range imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, group := range [][]string{std, other} {
+		if len(group) > 0 {
+			file.Imports = append(file.Imports, group)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, file); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("upper: generated code could not be formatted: %w", err)
+	}
+	return src, nil
+}
+
+type fileT struct {
+	Package string
+	Imports [][]string
+	Records []*recordT
+}
+
+type recordT struct {
+	Table       string
+	Name        string
+	Store       string
+	Constructor string
+	Fields      []*fieldT
+
+	// PrimaryKey is set for tables with a single column primary key.
+	PrimaryKey *fieldT
+}
+
+type fieldT struct {
+	Name     string
+	Type     string
+	BaseType string
+	Tag      string
+	Import   string
+}
+
+func newRecord(adapter string, table *db.Table) *recordT {
+	tableName := goName(table.Name)
+
+	record := &recordT{
+		Table: table.Name,
+		Name:  singular(tableName),
+	}
+	record.Store = record.Name + "Store"
+	record.Constructor = tableName
+	if record.Constructor == record.Name {
+		record.Constructor = plural(record.Name)
+	}
+
+	primaryKey := map[string]bool{}
+	for _, column := range table.PrimaryKey {
+		primaryKey[column] = true
+	}
+
+	used := map[string]int{}
+	for _, column := range table.Columns {
+		// QL exposes its implicit primary key as "id()" and maps it to "id".
+		columnName := strings.TrimSuffix(column.Name, "()")
+
+		name := goName(columnName)
+		if used[name]++; used[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, used[name])
+		}
+
+		t := columnType(adapter, column)
+		typeName := t.Name
+		if column.Nullable && !t.Nillable {
+			typeName = "*" + typeName
+		}
+
+		tag := columnName
+		if column.AutoIncrement || column.Default != nil {
+			tag += ",omitempty"
+		}
+
+		field := &fieldT{
+			Name:     name,
+			Type:     typeName,
+			BaseType: t.Name,
+			Tag:      fmt.Sprintf("`db:%q`", tag),
+			Import:   t.Import,
+		}
+		record.Fields = append(record.Fields, field)
+
+		if len(table.PrimaryKey) == 1 && primaryKey[column.Name] {
+			record.PrimaryKey = field
+		}
+	}
+
+	return record
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by upper generate. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range $i, $group := .Imports}}
+{{- if $i}}
+{{end}}
+{{- range $group}}
+	{{if eq . "github.com/upper/db/v4"}}db {{end}}"{{.}}"
+{{- end}}
+{{- end}}
+)
+{{range $record := .Records}}
+// {{.Name}} represents a row of the "{{.Table}}" table.
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{.Tag}}
+{{- end}}
+}
+
+// Store returns the store of the "{{.Table}}" table.
+func (*{{.Name}}) Store(sess db.Session) db.Store {
+	return {{.Constructor}}(sess)
+}
+
+// {{.Store}} provides typed access to the "{{.Table}}" table.
+type {{.Store}} struct {
+	db.Collection
+}
+
+// {{.Constructor}} returns the store of the "{{.Table}}" table.
+func {{.Constructor}}(sess db.Session) *{{.Store}} {
+	return &{{.Store}}{sess.Collection("{{.Table}}")}
+}
+
+// FindOne returns the first {{.Name}} that matches the given conditions.
+func (store *{{.Store}}) FindOne(conds ...interface{}) (*{{.Name}}, error) {
+	var record {{.Name}}
+	if err := store.Find(conds...).One(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindAll returns all the {{.Name}} records that match the given conditions.
+func (store *{{.Store}}) FindAll(conds ...interface{}) ([]*{{.Name}}, error) {
+	var records []*{{.Name}}
+	if err := store.Find(conds...).All(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+{{- with .PrimaryKey}}
+
+// FindBy{{.Name}} returns the {{$record.Name}} with the given primary key.
+func (store *{{$record.Store}}) FindBy{{.Name}}(id {{.BaseType}}) (*{{$record.Name}}, error) {
+	return store.FindOne(id)
+}
+{{- end}}
+{{end}}`))
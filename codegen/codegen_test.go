@@ -0,0 +1,207 @@
+package codegen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+func TestGoName(t *testing.T) {
+	testCases := map[string]string{
+		"accounts":       "Accounts",
+		"account_id":     "AccountID",
+		"id()":           "ID",
+		"avatar_url":     "AvatarURL",
+		"first-name":     "FirstName",
+		"2fa_enabled":    "X2faEnabled",
+		"createdAt":      "CreatedAt",
+		"public.artists": "PublicArtists",
+	}
+	for in, out := range testCases {
+		assert.Equal(t, out, goName(in), in)
+	}
+}
+
+func TestSingularPlural(t *testing.T) {
+	assert.Equal(t, "Account", singular("Accounts"))
+	assert.Equal(t, "Category", singular("Categories"))
+	assert.Equal(t, "Address", singular("Addresses"))
+	assert.Equal(t, "Status", singular("Status"))
+	assert.Equal(t, "Artist", singular("Artist"))
+
+	assert.Equal(t, "Artists", plural("Artist"))
+	assert.Equal(t, "Categories", plural("Category"))
+	assert.Equal(t, "Keys", plural("Key"))
+	assert.Equal(t, "Statuses", plural("Status"))
+}
+
+func TestColumnType(t *testing.T) {
+	testCases := []struct {
+		adapter string
+		typ     string
+		out     string
+	}{
+		{"postgresql", "integer", "int"},
+		{"postgresql", "bigint", "int64"},
+		{"postgresql", "character varying(60)", "string"},
+		{"postgresql", "timestamp with time zone", "time.Time"},
+		{"postgresql", "jsonb", "postgresql.JSONB"},
+		{"postgresql", "text[]", "postgresql.StringArray"},
+		{"postgresql", "character varying(20)[]", "postgresql.StringArray"},
+		{"postgresql", "integer[]", "postgresql.Int64Array"},
+		{"postgresql", "double precision[]", "postgresql.Float64Array"},
+		{"postgresql", "bytea", "[]byte"},
+		{"cockroachdb", "STRING[]", "cockroachdb.StringArray"},
+		{"cockroachdb", "JSONB", "cockroachdb.JSONB"},
+		{"cockroachdb", "INT8", "int64"},
+		{"mysql", "tinyint(1)", "bool"},
+		{"mysql", "int(10) unsigned", "uint"},
+		{"mysql", "json", "mysql.JSON"},
+		{"mysql", "datetime", "time.Time"},
+		{"mssql", "nvarchar", "string"},
+		{"mssql", "bit", "bool"},
+		{"sqlite", "VARCHAR(60)", "string"},
+		{"sqlite", "json", "string"},
+		{"ql", "int64", "int64"},
+		{"ql", "blob", "[]byte"},
+		{"ql", "duration", "interface{}"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.out, columnType(tc.adapter, &db.Column{Type: tc.typ}).Name, tc.typ)
+	}
+}
+
+func TestRender(t *testing.T) {
+	defaultNow := "now()"
+	tables := []*db.Table{
+		{
+			Name: "accounts",
+			Columns: []*db.Column{
+				{Name: "id", Type: "integer", AutoIncrement: true},
+				{Name: "name", Type: "character varying(60)"},
+				{Name: "tags", Type: "text[]", Nullable: true},
+				{Name: "settings", Type: "jsonb", Nullable: true},
+				{Name: "created_at", Type: "timestamp with time zone", Default: &defaultNow},
+				{Name: "deleted_at", Type: "timestamp with time zone", Nullable: true},
+			},
+			PrimaryKey: []string{"id"},
+		},
+	}
+
+	src, err := Render(tables, Options{Adapter: "postgresql"})
+	assert.NoError(t, err)
+
+	expected := `// Code generated by upper generate. DO NOT EDIT.
+
+package models
+
+import (
+	"time"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/adapter/postgresql"
+)
+
+// Account represents a row of the "accounts" table.
+type Account struct {
+	ID        int                    ` + "`" + `db:"id,omitempty"` + "`" + `
+	Name      string                 ` + "`" + `db:"name"` + "`" + `
+	Tags      postgresql.StringArray ` + "`" + `db:"tags"` + "`" + `
+	Settings  postgresql.JSONB       ` + "`" + `db:"settings"` + "`" + `
+	CreatedAt time.Time              ` + "`" + `db:"created_at,omitempty"` + "`" + `
+	DeletedAt *time.Time             ` + "`" + `db:"deleted_at"` + "`" + `
+}
+
+// Store returns the store of the "accounts" table.
+func (*Account) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+// AccountStore provides typed access to the "accounts" table.
+type AccountStore struct {
+	db.Collection
+}
+
+// Accounts returns the store of the "accounts" table.
+func Accounts(sess db.Session) *AccountStore {
+	return &AccountStore{sess.Collection("accounts")}
+}
+
+// FindOne returns the first Account that matches the given conditions.
+func (store *AccountStore) FindOne(conds ...interface{}) (*Account, error) {
+	var record Account
+	if err := store.Find(conds...).One(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindAll returns all the Account records that match the given conditions.
+func (store *AccountStore) FindAll(conds ...interface{}) ([]*Account, error) {
+	var records []*Account
+	if err := store.Find(conds...).All(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// FindByID returns the Account with the given primary key.
+func (store *AccountStore) FindByID(id int) (*Account, error) {
+	return store.FindOne(id)
+}
+`
+	assert.Equal(t, expected, string(src))
+}
+
+func TestRenderNameConflict(t *testing.T) {
+	tables := []*db.Table{
+		{Name: "account", Columns: []*db.Column{{Name: "id", Type: "integer"}}},
+		{Name: "accounts", Columns: []*db.Column{{Name: "id", Type: "integer"}}},
+	}
+	_, err := Render(tables, Options{})
+	assert.Error(t, err)
+}
+
+type diffAccount struct {
+	ID        int64      `db:"id,omitempty"`
+	Name      string     `db:"name"`
+	Balance   int        `db:"balance"`
+	Email     string     `db:"email"`
+	CreatedAt *time.Time `db:"created_at,omitempty"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+func TestDiffTable(t *testing.T) {
+	now := "now()"
+	table := &db.Table{
+		Name: "accounts",
+		Columns: []*db.Column{
+			{Name: "id", Type: "integer", AutoIncrement: true},
+			{Name: "name", Type: "character varying(60)", Nullable: true},
+			{Name: "balance", Type: "numeric(10,2)"},
+			{Name: "created_at", Type: "timestamp with time zone", Default: &now},
+			{Name: "deleted_at", Type: "timestamp with time zone"},
+			{Name: "legacy", Type: "text", Nullable: true},
+		},
+	}
+
+	diffs := diffTable("postgresql", table, mappedFields(sqlbuilder.Mapper, reflect.TypeOf(diffAccount{})))
+
+	out := []string{}
+	for _, diff := range diffs {
+		out = append(out, diff.Kind.String()+" "+diff.Column)
+	}
+	assert.Equal(t, []string{
+		"nullability mismatch name",
+		"type mismatch balance",
+		"missing column email",
+		"nullability mismatch deleted_at",
+		"extra column legacy",
+	}, out)
+
+	assert.Equal(t, "accounts.balance: field Balance has type int, column has type numeric(10,2)", diffs[1].String())
+}
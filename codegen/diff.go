@@ -0,0 +1,419 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package codegen
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// DifferenceKind identifies the kind of mismatch between a record and its
+// table.
+type DifferenceKind uint8
+
+// Kinds of differences between records and tables.
+const (
+	// MissingTable means the table of the record does not exist.
+	MissingTable DifferenceKind = iota + 1
+
+	// MissingColumn means the record has a field for a column the table
+	// does not have.
+	MissingColumn
+
+	// ExtraColumn means the table has a column no field of the record is
+	// mapped to.
+	ExtraColumn
+
+	// TypeMismatch means the type of the field can't hold the values of the
+	// column.
+	TypeMismatch
+
+	// NullabilityMismatch means the column is nullable and the field can't
+	// hold NULL, or the field is a pointer to a NOT NULL column.
+	NullabilityMismatch
+)
+
+var differenceKinds = map[DifferenceKind]string{
+	MissingTable:        "missing table",
+	MissingColumn:       "missing column",
+	ExtraColumn:         "extra column",
+	TypeMismatch:        "type mismatch",
+	NullabilityMismatch: "nullability mismatch",
+}
+
+func (k DifferenceKind) String() string {
+	if s, ok := differenceKinds[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("DifferenceKind(%d)", k)
+}
+
+// Difference describes a mismatch between a Go struct and the table it's
+// stored in.
+type Difference struct {
+	Kind DifferenceKind
+
+	Table  string
+	Column string
+
+	// Field is the name of the struct field mapped to the column, like
+	// "CreatedAt". It's empty for ExtraColumn differences.
+	Field     string
+	FieldType string
+
+	// ColumnType is the type of the column as reported by the database. It's
+	// empty for MissingTable and MissingColumn differences.
+	ColumnType string
+
+	fields []*reflectx.FieldInfo
+}
+
+func (d *Difference) String() string {
+	switch d.Kind {
+	case MissingTable:
+		return fmt.Sprintf("%s: table does not exist", d.Table)
+	case MissingColumn:
+		return fmt.Sprintf("%s.%s: column does not exist (field %s %s)", d.Table, d.Column, d.Field, d.FieldType)
+	case ExtraColumn:
+		return fmt.Sprintf("%s.%s: column (%s) is not mapped to any field", d.Table, d.Column, d.ColumnType)
+	case TypeMismatch:
+		return fmt.Sprintf("%s.%s: field %s has type %s, column has type %s", d.Table, d.Column, d.Field, d.FieldType, d.ColumnType)
+	case NullabilityMismatch:
+		if strings.HasPrefix(d.FieldType, "*") {
+			return fmt.Sprintf("%s.%s: field %s is a pointer but the column is NOT NULL", d.Table, d.Column, d.Field)
+		}
+		return fmt.Sprintf("%s.%s: column is nullable but field %s (%s) can't hold NULL", d.Table, d.Column, d.Field, d.FieldType)
+	}
+	return fmt.Sprintf("%s.%s: %s", d.Table, d.Column, d.Kind)
+}
+
+// Diff compares the given records with the tables they're stored in and
+// returns their differences. The adapter name is used to interpret the
+// column types reported by the database.
+//
+// Example:
+//
+//  diffs, err := codegen.Diff(sess, "postgresql", &Account{}, &User{})
+//  ...
+//  for _, diff := range diffs {
+//    log.Println(diff)
+//  }
+func Diff(sess db.Session, adapter string, records ...db.Record) ([]*Difference, error) {
+	diffs := []*Difference{}
+
+	for _, record := range records {
+		recordT := reflectx.Deref(reflect.TypeOf(record))
+		if recordT.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("upper: expecting a pointer to struct, got %T", record)
+		}
+
+		tableName := record.Store(sess).Name()
+		fields := mappedFields(sqlbuilder.MapperOf(sess), recordT)
+
+		table, err := sess.Schema().Table(tableName)
+		if err != nil {
+			if errors.Is(err, db.ErrCollectionDoesNotExist) {
+				diffs = append(diffs, &Difference{
+					Kind:   MissingTable,
+					Table:  tableName,
+					fields: fields,
+				})
+				continue
+			}
+			return nil, err
+		}
+
+		diffs = append(diffs, diffTable(adapter, table, fields)...)
+	}
+
+	return diffs, nil
+}
+
+// mappedFields returns the fields of the struct that are mapped to columns by
+// the given mapper, sorted by their position in the struct.
+func mappedFields(mapper *reflectx.Mapper, t reflect.Type) []*reflectx.FieldInfo {
+	fields := []*reflectx.FieldInfo{}
+	for name, fi := range mapper.TypeMap(t).Names {
+		// Children of nested structs are not columns by themselves.
+		if strings.Contains(name, ".") {
+			continue
+		}
+		fields = append(fields, fi)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].Index, fields[j].Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+func diffTable(adapter string, table *db.Table, fields []*reflectx.FieldInfo) []*Difference {
+	diffs := []*Difference{}
+
+	mapped := map[string]bool{}
+	for _, fi := range fields {
+		mapped[fi.Name] = true
+
+		column := table.Column(fi.Name)
+		if column == nil && fi.Name == "id" {
+			// QL exposes its implicit primary key as "id()".
+			column = table.Column("id()")
+		}
+		if column == nil {
+			diffs = append(diffs, &Difference{
+				Kind:      MissingColumn,
+				Table:     table.Name,
+				Column:    fi.Name,
+				Field:     fi.Field.Name,
+				FieldType: fi.Field.Type.String(),
+				fields:    []*reflectx.FieldInfo{fi},
+			})
+			continue
+		}
+		mapped[column.Name] = true
+
+		diff := &Difference{
+			Table:      table.Name,
+			Column:     column.Name,
+			Field:      fi.Field.Name,
+			FieldType:  fi.Field.Type.String(),
+			ColumnType: column.Type,
+		}
+
+		fieldKind, columnKind := kindOfField(fi.Field.Type), kindOfType(columnType(adapter, column))
+		if fieldKind != kindUnknown && columnKind != kindUnknown && fieldKind != columnKind {
+			diff.Kind = TypeMismatch
+			diffs = append(diffs, diff)
+			continue
+		}
+
+		_, omitEmpty := fi.Options["omitempty"]
+		switch {
+		case column.Nullable && !canBeNull(fi.Field.Type):
+			diff.Kind = NullabilityMismatch
+		case !column.Nullable && fi.Field.Type.Kind() == reflect.Ptr:
+			// Pointers to columns the database fills in, like
+			// created_at *time.Time `db:"created_at,omitempty"`, are fine.
+			if omitEmpty && (column.Default != nil || column.AutoIncrement) {
+				continue
+			}
+			diff.Kind = NullabilityMismatch
+		default:
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+
+	for _, column := range table.Columns {
+		if mapped[column.Name] || column.Name == "id()" {
+			continue
+		}
+		diffs = append(diffs, &Difference{
+			Kind:       ExtraColumn,
+			Table:      table.Name,
+			Column:     column.Name,
+			ColumnType: column.Type,
+		})
+	}
+
+	return diffs
+}
+
+// DDL returns the statements that create the missing tables and columns
+// found by Diff, compiled with the given SQL builder. Destructive or lossy
+// changes, like dropping extra columns or changing column types, are left
+// out and should be reviewed by hand. New NOT NULL columns of basic types
+// get their zero value as default, so they can be added to tables that
+// already have rows.
+//
+// Example:
+//
+//  statements := codegen.DDL(sess.SQL(), diffs)
+func DDL(sqlb db.SQL, diffs []*Difference) []string {
+	statements := []string{}
+	for _, diff := range diffs {
+		switch diff.Kind {
+		case MissingTable:
+			q := sqlb.CreateTable(diff.Table)
+			for _, fi := range diff.fields {
+				columnType, options := columnDefinition(fi)
+				if isSerial(fi) {
+					columnType, options = db.TypeSerial, nil
+					q = q.PrimaryKey(fi.Name)
+				}
+				q = q.Column(fi.Name, columnType, options...)
+			}
+			statements = append(statements, q.String())
+		case MissingColumn:
+			columnType, options := columnDefinition(diff.fields[0])
+			if zero, ok := zeroDefault(diff.fields[0]); ok {
+				// Existing rows need a value for the new NOT NULL column.
+				options = append(options, db.Default(zero))
+			}
+			q := sqlb.AlterTable(diff.Table).AddColumn(diff.Column, columnType, options...)
+			statements = append(statements, q.String())
+		}
+	}
+	return statements
+}
+
+// isSerial reports whether the field looks like an auto incremented primary
+// key: an integer "id" field tagged with omitempty.
+func isSerial(fi *reflectx.FieldInfo) bool {
+	_, omitEmpty := fi.Options["omitempty"]
+	return fi.Name == "id" && omitEmpty && kindOfField(fi.Field.Type) == kindInt
+}
+
+// columnDefinition chooses a column type for a struct field.
+func columnDefinition(fi *reflectx.FieldInfo) (db.ColumnType, []db.ColumnOption) {
+	var options []db.ColumnOption
+	if !canBeNull(fi.Field.Type) {
+		options = append(options, db.NotNull())
+	}
+
+	t := reflectx.Deref(fi.Field.Type)
+	switch kindOfField(t) {
+	case kindBool:
+		return db.TypeBoolean, options
+	case kindInt:
+		switch t.Kind() {
+		case reflect.Int64, reflect.Uint64, reflect.Int, reflect.Uint:
+			return db.TypeBigInteger, options
+		}
+		return db.TypeInteger, options
+	case kindFloat:
+		return db.TypeFloat, options
+	case kindString:
+		return db.TypeString, options
+	case kindTime:
+		return db.TypeTimestamp, options
+	case kindBytes:
+		return db.TypeBinary, options
+	}
+	return db.TypeJSON, options
+}
+
+// zeroDefault returns the zero value of NOT NULL fields of basic types, so
+// it can be used as the default value of a new column.
+func zeroDefault(fi *reflectx.FieldInfo) (interface{}, bool) {
+	if canBeNull(fi.Field.Type) {
+		return nil, false
+	}
+	switch kindOfField(fi.Field.Type) {
+	case kindBool:
+		return false, true
+	case kindInt:
+		return 0, true
+	case kindFloat:
+		return 0.0, true
+	case kindString:
+		return "", true
+	}
+	return nil, false
+}
+
+// kind is a broad classification of Go and column types used to tell whether
+// a field can hold the values of a column.
+type kind uint8
+
+const (
+	kindUnknown kind = iota
+	kindBool
+	kindInt
+	kindFloat
+	kindString
+	kindTime
+	kindBytes
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+func kindOfField(t reflect.Type) kind {
+	t = reflectx.Deref(t)
+	if t == timeType {
+		return kindTime
+	}
+	if reflect.PtrTo(t).Implements(scannerType) {
+		// Custom types decide themselves what they can be scanned from.
+		return kindUnknown
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return kindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kindInt
+	case reflect.Float32, reflect.Float64:
+		return kindFloat
+	case reflect.String:
+		return kindString
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return kindBytes
+		}
+	}
+	return kindUnknown
+}
+
+func kindOfType(t goType) kind {
+	switch t {
+	case typeBool:
+		return kindBool
+	case typeInt, typeInt16, typeInt64, typeUint, typeUint16, typeUint64:
+		return kindInt
+	case typeFloat32, typeFloat64:
+		return kindFloat
+	case typeString:
+		return kindString
+	case typeTime:
+		return kindTime
+	case typeBytes:
+		return kindBytes
+	}
+	return kindUnknown
+}
+
+// canBeNull reports whether a field of the given type can hold NULL values.
+func canBeNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return reflect.PtrTo(t).Implements(scannerType)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package codegen
+
+import (
+	"strings"
+
+	db "github.com/upper/db/v4"
+)
+
+const adapterImportPrefix = `github.com/upper/db/v4/adapter/`
+
+// goType describes the Go type of a struct field.
+type goType struct {
+	Name string
+
+	// Import is the package that needs to be imported in order to use the
+	// type, if any.
+	Import string
+
+	// Nillable is true for types that can already represent NULL values,
+	// like slices, and must not be turned into pointers.
+	Nillable bool
+}
+
+var (
+	typeBool      = goType{Name: "bool"}
+	typeInt       = goType{Name: "int"}
+	typeInt16     = goType{Name: "int16"}
+	typeInt64     = goType{Name: "int64"}
+	typeUint      = goType{Name: "uint"}
+	typeUint16    = goType{Name: "uint16"}
+	typeUint64    = goType{Name: "uint64"}
+	typeFloat32   = goType{Name: "float32"}
+	typeFloat64   = goType{Name: "float64"}
+	typeString    = goType{Name: "string"}
+	typeBytes     = goType{Name: "[]byte", Nillable: true}
+	typeTime      = goType{Name: "time.Time", Import: "time"}
+	typeInterface = goType{Name: "interface{}", Nillable: true}
+)
+
+// adapterType returns a type defined by the given adapter package.
+func adapterType(adapter string, name string) goType {
+	return goType{
+		Name:     adapter + "." + name,
+		Import:   adapterImportPrefix + adapter,
+		Nillable: true,
+	}
+}
+
+// baseType strips size, precision and modifiers from a column type, so that
+// "character varying(60)" becomes "character varying" and "int(10) unsigned"
+// becomes "int".
+func baseType(columnType string) string {
+	t := strings.ToLower(strings.TrimSpace(columnType))
+	if i := strings.IndexByte(t, '('); i >= 0 {
+		t = t[:i]
+	}
+	t = strings.TrimSuffix(t, " unsigned")
+	t = strings.TrimSuffix(t, " zerofill")
+	return strings.TrimSpace(t)
+}
+
+// arrayElementType returns the element type of a PostgreSQL or CockroachDB
+// array type like "text[]" or "STRING[]".
+func arrayElementType(columnType string) (string, bool) {
+	t := strings.TrimSpace(columnType)
+	if !strings.HasSuffix(t, "[]") {
+		return "", false
+	}
+	return strings.TrimSuffix(t, "[]"), true
+}
+
+// columnType maps the type of a column, as reported by the adapter, into a
+// Go type. Adapter specific types are used for JSON and array columns when
+// the adapter provides them.
+func columnType(adapter string, column *db.Column) goType {
+	t := strings.ToLower(column.Type)
+	unsigned := strings.Contains(t, "unsigned")
+
+	switch adapter {
+	case "postgresql", "cockroachdb":
+		if elem, ok := arrayElementType(t); ok {
+			switch columnType(adapter, &db.Column{Type: elem}) {
+			case typeString:
+				return adapterType(adapter, "StringArray")
+			case typeInt, typeInt16, typeInt64:
+				return adapterType(adapter, "Int64Array")
+			case typeFloat32, typeFloat64:
+				return adapterType(adapter, "Float64Array")
+			case typeBool:
+				return adapterType(adapter, "BoolArray")
+			}
+			return typeInterface
+		}
+		switch baseType(t) {
+		case "json", "jsonb":
+			return adapterType(adapter, "JSONB")
+		}
+	case "mysql":
+		switch {
+		case t == "tinyint(1)":
+			return typeBool
+		case baseType(t) == "json":
+			return adapterType(adapter, "JSON")
+		}
+	}
+
+	switch baseType(t) {
+	case "bool", "boolean", "bit":
+		return typeBool
+	case "smallint", "int2", "tinyint", "year":
+		if unsigned {
+			return typeUint16
+		}
+		return typeInt16
+	case "int", "integer", "int4", "mediumint", "serial", "serial4", "int32":
+		if unsigned {
+			return typeUint
+		}
+		return typeInt
+	case "bigint", "int8", "bigserial", "serial8", "int64":
+		if unsigned {
+			return typeUint64
+		}
+		return typeInt64
+	case "uint", "uint32":
+		return typeUint
+	case "uint64":
+		return typeUint64
+	case "real", "float4", "float32":
+		return typeFloat32
+	case "float", "float8", "double", "double precision", "float64",
+		"decimal", "numeric", "money", "smallmoney":
+		return typeFloat64
+	case "char", "character", "varchar", "character varying", "nchar",
+		"nvarchar", "text", "tinytext", "mediumtext", "longtext", "ntext",
+		"string", "citext", "uuid", "uniqueidentifier", "enum", "set",
+		"json", "jsonb", "xml", "inet", "cidr", "macaddr":
+		return typeString
+	case "date", "time", "datetime", "datetime2", "smalldatetime",
+		"datetimeoffset", "timestamp", "timestamptz",
+		"timestamp with time zone", "timestamp without time zone":
+		return typeTime
+	case "bytea", "bytes", "blob", "tinyblob", "mediumblob", "longblob",
+		"binary", "varbinary", "image":
+		return typeBytes
+	}
+
+	return typeInterface
+}
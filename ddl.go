@@ -0,0 +1,237 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"fmt"
+)
+
+// ColumnType represents a portable column type. Adapters map portable types
+// into their native equivalents, any other value is passed to the database
+// as is.
+type ColumnType string
+
+// Portable column types.
+const (
+	// TypeSerial and TypeBigSerial are auto-incrementing integers, they're
+	// meant to be used as primary keys.
+	TypeSerial    ColumnType = "serial"
+	TypeBigSerial ColumnType = "bigserial"
+
+	TypeInteger    ColumnType = "integer"
+	TypeBigInteger ColumnType = "bigint"
+	TypeFloat      ColumnType = "float"
+	TypeDecimal    ColumnType = "decimal"
+	TypeBoolean    ColumnType = "boolean"
+
+	// TypeString is a variable length string, its length is set with Size
+	// and defaults to 255.
+	TypeString ColumnType = "string"
+	TypeText   ColumnType = "text"
+
+	TypeTimestamp ColumnType = "timestamp"
+	TypeDate      ColumnType = "date"
+	TypeBinary    ColumnType = "binary"
+	TypeJSON      ColumnType = "json"
+	TypeUUID      ColumnType = "uuid"
+)
+
+// ColumnOptions represents the optional attributes of a column definition.
+type ColumnOptions struct {
+	Size      int
+	Precision int
+	Scale     int
+	NotNull   bool
+	Unique    bool
+
+	// Default is the default value of the column, use Raw for expressions.
+	Default interface{}
+}
+
+// ColumnOption sets an optional attribute of a column definition.
+type ColumnOption func(*ColumnOptions)
+
+// Size sets the length of a TypeString column.
+func Size(size int) ColumnOption {
+	return func(o *ColumnOptions) {
+		o.Size = size
+	}
+}
+
+// Precision sets the precision and scale of a TypeDecimal column.
+func Precision(precision, scale int) ColumnOption {
+	return func(o *ColumnOptions) {
+		o.Precision, o.Scale = precision, scale
+	}
+}
+
+// NotNull adds a NOT NULL constraint to the column.
+func NotNull() ColumnOption {
+	return func(o *ColumnOptions) {
+		o.NotNull = true
+	}
+}
+
+// Unique adds a UNIQUE constraint to the column.
+func Unique() ColumnOption {
+	return func(o *ColumnOptions) {
+		o.Unique = true
+	}
+}
+
+// Default sets the default value of the column. Values are written into the
+// statement as literals, use Raw to set an expression, like
+// db.Raw("CURRENT_TIMESTAMP").
+func Default(value interface{}) ColumnOption {
+	return func(o *ColumnOptions) {
+		o.Default = value
+	}
+}
+
+// TableCreator represents a CREATE TABLE statement.
+//
+// Example:
+//
+//  sess.SQL().
+//    CreateTable("accounts").
+//    Column("id", db.TypeSerial).
+//    Column("name", db.TypeString, db.Size(60), db.NotNull()).
+//    Column("owner_id", db.TypeInteger).
+//    PrimaryKey("id").
+//    ForeignKey(db.ForeignKey{
+//      Columns:           []string{"owner_id"},
+//      ReferencedTable:   "users",
+//      ReferencedColumns: []string{"id"},
+//      OnDelete:          "CASCADE",
+//    }).
+//    Exec()
+type TableCreator interface {
+	// Column adds a column to the table.
+	Column(name string, columnType ColumnType, options ...ColumnOption) TableCreator
+
+	// PrimaryKey sets the columns of the primary key.
+	PrimaryKey(columns ...string) TableCreator
+
+	// Unique adds a named UNIQUE constraint over the given columns.
+	Unique(name string, columns ...string) TableCreator
+
+	// ForeignKey adds a FOREIGN KEY constraint.
+	ForeignKey(fk ForeignKey) TableCreator
+
+	// IfNotExists makes the statement a no-op when the table already exists.
+	IfNotExists() TableCreator
+
+	// SQLExecer provides the Exec method.
+	SQLExecer
+
+	// fmt.Stringer provides `String() string`, you can use `String()` to compile
+	// the `TableCreator` into a string.
+	fmt.Stringer
+}
+
+// TableAlterer represents one or more ALTER TABLE statements. Since not all
+// databases accept multiple changes within the same statement, each change is
+// compiled into its own statement and Exec runs them in order.
+type TableAlterer interface {
+	// AddColumn adds a column to the table.
+	AddColumn(name string, columnType ColumnType, options ...ColumnOption) TableAlterer
+
+	// DropColumn removes a column from the table.
+	DropColumn(name string) TableAlterer
+
+	// RenameColumn renames a column.
+	RenameColumn(from string, to string) TableAlterer
+
+	// RenameTo renames the table.
+	RenameTo(name string) TableAlterer
+
+	// AddUnique adds a named UNIQUE constraint over the given columns.
+	AddUnique(name string, columns ...string) TableAlterer
+
+	// AddForeignKey adds a FOREIGN KEY constraint.
+	AddForeignKey(fk ForeignKey) TableAlterer
+
+	// DropConstraint removes a named constraint.
+	DropConstraint(name string) TableAlterer
+
+	// SQLExecer provides the Exec method.
+	SQLExecer
+
+	// fmt.Stringer provides `String() string`, statements are separated by
+	// semicolons.
+	fmt.Stringer
+}
+
+// TableDropper represents a DROP TABLE statement.
+type TableDropper interface {
+	// IfExists makes the statement a no-op when the table does not exist.
+	IfExists() TableDropper
+
+	// SQLExecer provides the Exec method.
+	SQLExecer
+
+	// fmt.Stringer provides `String() string`, you can use `String()` to compile
+	// the `TableDropper` into a string.
+	fmt.Stringer
+}
+
+// IndexCreator represents a CREATE INDEX statement.
+//
+// Example:
+//
+//  sess.SQL().CreateIndex("accounts_name_idx").On("accounts", "name").Exec()
+type IndexCreator interface {
+	// On sets the table and the columns the index is created on.
+	On(table string, columns ...string) IndexCreator
+
+	// Unique makes the index unique.
+	Unique() IndexCreator
+
+	// IfNotExists makes the statement a no-op when the index already exists,
+	// on databases that support it.
+	IfNotExists() IndexCreator
+
+	// SQLExecer provides the Exec method.
+	SQLExecer
+
+	// fmt.Stringer provides `String() string`, you can use `String()` to compile
+	// the `IndexCreator` into a string.
+	fmt.Stringer
+}
+
+// IndexDropper represents a DROP INDEX statement.
+type IndexDropper interface {
+	// On sets the table the index belongs to, this is required by MySQL and
+	// SQL Server.
+	On(table string) IndexDropper
+
+	// IfExists makes the statement a no-op when the index does not exist, on
+	// databases that support it.
+	IfExists() IndexDropper
+
+	// SQLExecer provides the Exec method.
+	SQLExecer
+
+	// fmt.Stringer provides `String() string`, you can use `String()` to compile
+	// the `IndexDropper` into a string.
+	fmt.Stringer
+}
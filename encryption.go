@@ -0,0 +1,255 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// KeyProvider provides the AES keys that encrypt the fields tagged with the
+// encrypted option, see Codecs.SetKeyProvider. Keys must be 16, 24 or 32 bytes
+// long to select AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	// CurrentKey returns the key new values are encrypted with and its id.
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the given id, values encrypted with keys that
+	// were rotated are decrypted with it.
+	Key(id string) ([]byte, error)
+
+	// KeyIDs returns the ids of the keys values may be encrypted with.
+	// Conditions on deterministically encrypted columns match the values
+	// encrypted with any of them, so rows don't need to be encrypted again
+	// after a rotation to be found.
+	KeyIDs() ([]string, error)
+}
+
+// EncryptColumns declares columns of the given table that hold values
+// encrypted with a random nonce, like the fields tagged with the encrypted
+// option. Collections, result sets and SQL builders of sessions using c
+// encrypt the values of these columns that are given in maps and refuse
+// conditions on them given as db.Cond, db.And or db.Or. Values given one by
+// one after the columns of an insert and raw conditions are not inspected.
+// Records and structs stored in or fetched from the table must tag the fields
+// of these columns, and only these columns, with the encrypted option, or
+// ErrUndeclaredEncryption is returned.
+//
+// Example:
+//
+//  codecs := &db.Codecs{}
+//  codecs.SetKeyProvider(keys)
+//  codecs.EncryptColumns("people", "ssn")
+//  codecs.EncryptColumnsDeterministically("people", "email")
+//  sess, err = db.WithCodecs(sess, codecs)
+func (c *Codecs) EncryptColumns(table string, columns ...string) {
+	c.encryptColumns(table, false, columns)
+}
+
+// EncryptColumnsDeterministically declares columns of the given table that
+// hold values encrypted deterministically, like the fields tagged with the
+// encrypted=deterministic option. Their values can be compared for equality in
+// conditions, see EncryptColumns.
+func (c *Codecs) EncryptColumnsDeterministically(table string, columns ...string) {
+	c.encryptColumns(table, true, columns)
+}
+
+func (c *Codecs) encryptColumns(table string, deterministic bool, columns []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.encrypted == nil {
+		c.encrypted = map[string]map[string]bool{}
+	}
+	if c.encrypted[table] == nil {
+		c.encrypted[table] = map[string]bool{}
+	}
+	for _, column := range columns {
+		c.encrypted[table][column] = deterministic
+	}
+}
+
+// EncryptedColumns returns the encrypted columns declared for the given table,
+// the value of each column is true if it's encrypted deterministically.
+func (c *Codecs) EncryptedColumns(table string) map[string]bool {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.encrypted[table]) == 0 {
+		return nil
+	}
+	columns := make(map[string]bool, len(c.encrypted[table]))
+	for column, deterministic := range c.encrypted[table] {
+		columns[column] = deterministic
+	}
+	return columns
+}
+
+// NewEncryptedCodec returns a codec that encrypts values with AES-GCM and the
+// keys of the given provider, see Codecs.SetKeyProvider. Strings and byte slices
+// are encrypted as they are, other values are encoded as JSON first.
+func NewEncryptedCodec(keys KeyProvider, deterministic bool) Codec {
+	return &encryptedCodec{keys: keys, deterministic: deterministic}
+}
+
+type encryptedCodec struct {
+	keys          KeyProvider
+	deterministic bool
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// nonceKey derives the key of the HMAC that computes deterministic nonces from
+// an AES key, so the AES key is never used for anything else.
+func nonceKey(key []byte) ([]byte, error) {
+	macKey := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, nil, []byte("upper/db deterministic nonce")), macKey); err != nil {
+		return nil, err
+	}
+	return macKey, nil
+}
+
+func (c *encryptedCodec) Encode(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	var plaintext []byte
+	switch value := rv.Interface().(type) {
+	case string:
+		plaintext = []byte(value)
+	case []byte:
+		plaintext = value
+	default:
+		var err error
+		if plaintext, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if c.deterministic {
+		macKey, err := nonceKey(key)
+		if err != nil {
+			return nil, err
+		}
+		mac := hmac.New(sha256.New, macKey)
+		_, _ = mac.Write(plaintext)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, []byte(id))
+	return id + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *encryptedCodec) Decode(src interface{}, dst interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("%w: unexpected %T", ErrDecryptionFailed, src)
+	}
+
+	i := strings.LastIndexByte(text, ':')
+	if i < 0 {
+		return fmt.Errorf("%w: missing key id", ErrDecryptionFailed)
+	}
+	id := text[:i]
+	sealed, err := base64.RawStdEncoding.DecodeString(text[i+1:])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+
+	key, err := c.keys.Key(id)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return fmt.Errorf("%w: value is too short", ErrDecryptionFailed)
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+
+	dv := reflect.ValueOf(dst).Elem()
+	if dv.Kind() == reflect.Ptr {
+		dv.Set(reflect.New(dv.Type().Elem()))
+		dv = dv.Elem()
+	}
+	switch dv.Interface().(type) {
+	case string:
+		dv.SetString(string(plaintext))
+		return nil
+	case []byte:
+		dv.SetBytes(plaintext)
+		return nil
+	}
+	return json.Unmarshal(plaintext, dv.Addr().Interface())
+}
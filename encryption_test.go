@@ -0,0 +1,158 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testKeys struct {
+	current string
+	keys    map[string][]byte
+}
+
+func (k *testKeys) CurrentKey() (string, []byte, error) {
+	return k.current, k.keys[k.current], nil
+}
+
+func (k *testKeys) Key(id string) ([]byte, error) {
+	if key, ok := k.keys[id]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", id)
+}
+
+func (k *testKeys) KeyIDs() ([]string, error) {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func TestEncryptedCodec(t *testing.T) {
+	keys := &testKeys{
+		current: "k1",
+		keys: map[string][]byte{
+			"k1": bytes.Repeat([]byte{1}, 32),
+			"k2": bytes.Repeat([]byte{2}, 16),
+		},
+	}
+	codec := NewEncryptedCodec(keys, false)
+
+	a, err := codec.Encode("secret")
+	assert.NoError(t, err)
+	b, err := codec.Encode("secret")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(a.(string), "k1:"))
+	assert.NotContains(t, a, "secret")
+	assert.NotEqual(t, a, b)
+
+	var s string
+	assert.NoError(t, codec.Decode(a, &s))
+	assert.Equal(t, "secret", s)
+
+	keys.current = "k2"
+
+	c, err := codec.Encode(map[string]int{"a": 1})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(c.(string), "k2:"))
+
+	var m map[string]int
+	assert.NoError(t, codec.Decode([]byte(c.(string)), &m))
+	assert.Equal(t, map[string]int{"a": 1}, m)
+
+	s = ""
+	assert.NoError(t, codec.Decode(a, &s))
+	assert.Equal(t, "secret", s)
+
+	var p *string
+	assert.NoError(t, codec.Decode(a, &p))
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "secret", *p)
+	}
+
+	value, err := codec.Encode((*string)(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	tampered := a.(string)[:len(a.(string))-2] + "AA"
+	assert.True(t, errors.Is(codec.Decode(tampered, &s), ErrDecryptionFailed))
+	assert.True(t, errors.Is(codec.Decode("plaintext", &s), ErrDecryptionFailed))
+	assert.Error(t, codec.Decode("k3:"+a.(string)[3:], &s))
+}
+
+func TestEncryptedCodecDeterministic(t *testing.T) {
+	keys := &testKeys{
+		current: "k1",
+		keys: map[string][]byte{
+			"k1": bytes.Repeat([]byte{1}, 32),
+			"k2": bytes.Repeat([]byte{2}, 32),
+		},
+	}
+	codec := NewEncryptedCodec(keys, true)
+
+	a, err := codec.Encode("a@example.com")
+	assert.NoError(t, err)
+	b, err := codec.Encode("a@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := codec.Encode("b@example.com")
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, c)
+
+	// The nonce is not derived with the encryption key itself.
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(a.(string), "k1:"))
+	assert.NoError(t, err)
+	mac := hmac.New(sha256.New, keys.keys["k1"])
+	_, _ = mac.Write([]byte("a@example.com"))
+	assert.NotEqual(t, mac.Sum(nil)[:12], sealed[:12])
+
+	keys.current = "k2"
+	d, err := codec.Encode("a@example.com")
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, d)
+
+	var s string
+	assert.NoError(t, codec.Decode(a, &s))
+	assert.Equal(t, "a@example.com", s)
+}
+
+func TestCodecsKeyProvider(t *testing.T) {
+	codecs := &Codecs{}
+	assert.Nil(t, codecs.KeyProvider())
+
+	keys := &testKeys{current: "k1", keys: map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}}
+	codecs.SetKeyProvider(keys)
+	assert.Equal(t, keys, codecs.KeyProvider())
+
+	assert.Nil(t, (*Codecs)(nil).KeyProvider())
+}
@@ -60,4 +60,25 @@ var (
 	ErrTransactionAborted       = errors.New(`upper: transaction was aborted`)
 	ErrNotWithinTransaction     = errors.New(`upper: not within transaction`)
 	ErrNotSupportedByAdapter    = errors.New(`upper: not supported by adapter`)
+	ErrUnknownRelation          = errors.New(`upper: unknown relation`)
+	ErrInvalidRelation          = errors.New(`upper: invalid relation`)
+	ErrNotSoftDeletable         = errors.New(`upper: collection is not soft-deletable`)
+	ErrStaleRecord              = errors.New(`upper: record was modified or deleted since it was read`)
+	ErrUnknownScope             = errors.New(`upper: unknown scope`)
+	ErrInvalidTenancy           = errors.New(`upper: tenancy requires a column and at least one table`)
+	ErrMissingTenant            = errors.New(`upper: missing tenant`)
+	ErrTenantIsolation          = errors.New(`upper: statement would bypass tenant isolation`)
+	ErrUnknownKeyGenerator      = errors.New(`upper: unknown key generator`)
+	ErrInvalidKeyField          = errors.New(`upper: generated key can't be assigned to field`)
+	ErrInvalidSnowflakeNode     = errors.New(`upper: snowflake node must be between 0 and 1023`)
+	ErrExpectingSingleColumn    = errors.New(`upper: scanning into a single value requires exactly one column`)
+	ErrInvalidCallback          = errors.New(`upper: expecting a function that takes a result and returns an error`)
+	ErrInvalidChunkSize         = errors.New(`upper: chunk size must be greater than zero`)
+	ErrUnknownCodec             = errors.New(`upper: unknown codec`)
+	ErrMissingKeyProvider       = errors.New(`upper: encrypted fields require a key provider`)
+	ErrDecryptionFailed         = errors.New(`upper: can't decrypt value`)
+	ErrEncryptedCondition       = errors.New(`upper: encrypted columns can only be compared for equality if they're encrypted deterministically`)
+	ErrUndeclaredEncryption     = errors.New(`upper: encrypted fields must match the encrypted columns declared for their table`)
+	ErrInvalidImportValue       = errors.New(`upper: can't convert value`)
+	ErrStrictScan               = errors.New(`upper: result columns don't match the destination fields`)
 )
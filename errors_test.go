@@ -33,3 +33,16 @@ func TestErrorWrap(t *testing.T) {
 	adapterFakeErr := fmt.Errorf("could not find item in %q: %w", "users", ErrCollectionDoesNotExist)
 	assert.True(t, errors.Is(adapterFakeErr, ErrCollectionDoesNotExist))
 }
+
+func TestScanError(t *testing.T) {
+	err := error(&ScanError{
+		Type:            "main.Artist",
+		UnmappedColumns: []string{"genre"},
+		MissingFields:   []string{"name", "born_at"},
+	})
+	assert.True(t, errors.Is(err, ErrStrictScan))
+	assert.Equal(t, `upper: can't scan into main.Artist: unmapped columns "genre"; no column for fields "name", "born_at"`, err.Error())
+
+	err = &ScanError{Type: "main.Artist", MissingFields: []string{"name"}}
+	assert.Equal(t, `upper: can't scan into main.Artist: no column for fields "name"`, err.Error())
+}
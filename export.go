@@ -0,0 +1,329 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ResultColumn describes a column of the results of a query.
+type ResultColumn struct {
+	// Name is the name of the column.
+	Name string
+
+	// DatabaseType is the type of the column as reported by the driver, like
+	// "VARCHAR" or "BIGINT". It's empty if the driver doesn't report types.
+	DatabaseType string
+
+	// Type is the Go type the values of the column are normalized to, like
+	// int64 or time.Time, see ResultMapper.One. It's nil if the type of the
+	// column is unknown.
+	Type reflect.Type
+}
+
+// ColumnBatch is a batch of results stored by column, see ExportColumnar.
+type ColumnBatch struct {
+	// Columns are the columns of the results.
+	Columns []ResultColumn
+
+	// Values holds the values of each column in a slice of the Go type of the
+	// column, like a []int64 or a []time.Time. Values of columns of unknown
+	// type, or with values of some other type, are held in a []interface{}.
+	Values []interface{}
+
+	// Nulls holds whether each value of each column is NULL, NULL values are
+	// stored as zero values in typed slices.
+	Nulls [][]bool
+
+	// Len is the number of results in the batch.
+	Len int
+
+	vectors []reflect.Value
+}
+
+// ExportCSV writes the results of src, an Iterator or a Result, to w as CSV,
+// with a header with the names of the columns. Results are read and written
+// one at a time and src is closed once all of them were written.
+//
+// Times are formatted as RFC 3339 timestamps, binary data is encoded as
+// standard base64 and NULL values are written as empty fields.
+func ExportCSV(w io.Writer, src interface{}) error {
+	iter, columns, err := exportIterator(src)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(columns))
+	for i := range columns {
+		record[i] = columns[i].Name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for iter.Next() {
+		values, err := iter.Values()
+		if err != nil {
+			return err
+		}
+		for i := range values {
+			record[i] = formatValue(values[i])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportJSONLines writes the results of src, an Iterator or a Result, to w as
+// JSON Lines: one JSON object per result, with its columns as keys in the
+// order of the query. Results are read and written one at a time and src is
+// closed once all of them were written.
+//
+// Times are formatted as RFC 3339 timestamps, binary data is encoded as
+// standard base64 strings and JSON columns are written as they are.
+func ExportJSONLines(w io.Writer, src interface{}) error {
+	iter, columns, err := exportIterator(src)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	keys := make([][]byte, len(columns))
+	for i := range columns {
+		if keys[i], err = json.Marshal(columns[i].Name); err != nil {
+			return err
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for iter.Next() {
+		values, err := iter.Values()
+		if err != nil {
+			return err
+		}
+		_ = bw.WriteByte('{')
+		for i := range values {
+			if i > 0 {
+				_ = bw.WriteByte(',')
+			}
+			_, _ = bw.Write(keys[i])
+			_ = bw.WriteByte(':')
+			value, err := jsonValue(values[i])
+			if err != nil {
+				return fmt.Errorf("column %q: %w", columns[i].Name, err)
+			}
+			_, _ = bw.Write(value)
+		}
+		_, _ = bw.WriteString("}\n")
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// ExportColumnar reads the results of src, an Iterator or a Result, into
+// batches of up to batchSize results stored by column and calls fn with each
+// one of them. Only one batch is held in memory at a time and src is closed
+// once all of them were passed to fn.
+//
+// Example:
+//
+//   err := db.ExportColumnar(res, 1000, func(batch *db.ColumnBatch) error {
+//     ids := batch.Values[0].([]int64)
+//     ...
+//   })
+func ExportColumnar(src interface{}, batchSize int, fn func(*ColumnBatch) error) error {
+	if batchSize <= 0 {
+		return ErrInvalidChunkSize
+	}
+
+	iter, columns, err := exportIterator(src)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	batch := newColumnBatch(columns, batchSize)
+	for iter.Next() {
+		values, err := iter.Values()
+		if err != nil {
+			return err
+		}
+		batch.append(values)
+		if batch.Len == batchSize {
+			if err := fn(batch.flush()); err != nil {
+				return err
+			}
+			batch = newColumnBatch(columns, batchSize)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if batch.Len > 0 {
+		return fn(batch.flush())
+	}
+	return nil
+}
+
+// exportIterator returns the iterator of src, an Iterator or a Result that
+// provides one, and the types of its columns.
+func exportIterator(src interface{}) (Iterator, []ResultColumn, error) {
+	var iter Iterator
+	switch src := src.(type) {
+	case Iterator:
+		iter = src
+	case interface {
+		Iterator() (Iterator, error)
+	}:
+		var err error
+		if iter, err = src.Iterator(); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, ErrNotSupportedByAdapter
+	}
+
+	columns, err := iter.Columns()
+	if err != nil {
+		iter.Close()
+		return nil, nil, err
+	}
+	return iter, columns, nil
+}
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+func newColumnBatch(columns []ResultColumn, size int) *ColumnBatch {
+	batch := &ColumnBatch{
+		Columns: columns,
+		Values:  make([]interface{}, len(columns)),
+		Nulls:   make([][]bool, len(columns)),
+		vectors: make([]reflect.Value, len(columns)),
+	}
+	for i := range columns {
+		t := columns[i].Type
+		if t == nil {
+			t = interfaceType
+		}
+		batch.vectors[i] = reflect.MakeSlice(reflect.SliceOf(t), 0, size)
+		batch.Nulls[i] = make([]bool, 0, size)
+	}
+	return batch
+}
+
+func (b *ColumnBatch) append(values []interface{}) {
+	for i := range values {
+		vector := b.vectors[i]
+		elemT := vector.Type().Elem()
+
+		b.Nulls[i] = append(b.Nulls[i], values[i] == nil)
+		if values[i] == nil {
+			vector = reflect.Append(vector, reflect.Zero(elemT))
+		} else {
+			v := reflect.ValueOf(values[i])
+			if elemT != interfaceType && v.Type() != elemT {
+				vector = untypedVector(vector, b.Nulls[i])
+			}
+			vector = reflect.Append(vector, v)
+		}
+		b.vectors[i] = vector
+	}
+	b.Len++
+}
+
+func (b *ColumnBatch) flush() *ColumnBatch {
+	for i := range b.vectors {
+		b.Values[i] = b.vectors[i].Interface()
+	}
+	return b
+}
+
+// untypedVector copies the values of a typed vector into a []interface{},
+// NULL values are copied as nil.
+func untypedVector(vector reflect.Value, nulls []bool) reflect.Value {
+	values := make([]interface{}, vector.Len(), vector.Cap())
+	for i := range values {
+		if !nulls[i] {
+			values[i] = vector.Index(i).Interface()
+		}
+	}
+	return reflect.ValueOf(values)
+}
+
+// formatValue formats a normalized value as text.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.RawMessage:
+		return string(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// jsonValue encodes a normalized value as JSON.
+func jsonValue(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case json.RawMessage:
+		if !json.Valid(v) {
+			return json.Marshal(string(v))
+		}
+		return v, nil
+	case time.Time:
+		return json.Marshal(v.Format(time.RFC3339Nano))
+	}
+	return json.Marshal(v)
+}
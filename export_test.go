@@ -0,0 +1,132 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rowsIterator iterates over fixed rows.
+type rowsIterator struct {
+	columns []ResultColumn
+	rows    [][]interface{}
+	current int
+	closed  bool
+}
+
+func (it *rowsIterator) All(interface{}) error            { return ErrNotImplemented }
+func (it *rowsIterator) One(interface{}) error            { return ErrNotImplemented }
+func (it *rowsIterator) Scan(...interface{}) error        { return ErrNotImplemented }
+func (it *rowsIterator) NextScan(...interface{}) error    { return ErrNotImplemented }
+func (it *rowsIterator) ScanOne(...interface{}) error     { return ErrNotImplemented }
+func (it *rowsIterator) Columns() ([]ResultColumn, error) { return it.columns, nil }
+func (it *rowsIterator) Values() ([]interface{}, error)   { return it.rows[it.current-1], nil }
+func (it *rowsIterator) Err() error                       { return nil }
+func (it *rowsIterator) Close() error                     { it.closed = true; return nil }
+func (it *rowsIterator) Stream(context.Context, interface{}) (<-chan interface{}, <-chan error) {
+	return nil, nil
+}
+
+func (it *rowsIterator) Next(...interface{}) bool {
+	if it.current >= len(it.rows) {
+		return false
+	}
+	it.current++
+	return true
+}
+
+func newRowsIterator() *rowsIterator {
+	return &rowsIterator{
+		columns: []ResultColumn{
+			{Name: "id", Type: reflect.TypeOf(int64(0))},
+			{Name: "name", Type: reflect.TypeOf("")},
+			{Name: "born", Type: reflect.TypeOf(time.Time{})},
+			{Name: "avatar", Type: reflect.TypeOf([]byte{})},
+			{Name: "settings", Type: reflect.TypeOf(json.RawMessage{})},
+			{Name: "extra"},
+		},
+		rows: [][]interface{}{
+			{int64(1), "Ozzie, \"the\" Osbourne", time.Date(1948, 12, 3, 0, 0, 0, 0, time.UTC), []byte{1, 2}, json.RawMessage(`{"a":1}`), 1.5},
+			{int64(2), nil, "unknown", nil, nil, "x"},
+		},
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	iter := newRowsIterator()
+
+	var buf bytes.Buffer
+	assert.NoError(t, ExportCSV(&buf, iter))
+	assert.Equal(t, "id,name,born,avatar,settings,extra\n"+
+		"1,\"Ozzie, \"\"the\"\" Osbourne\",1948-12-03T00:00:00Z,AQI=,\"{\"\"a\"\":1}\",1.5\n"+
+		"2,,unknown,,,x\n", buf.String())
+	assert.True(t, iter.closed)
+
+	assert.Equal(t, ErrNotSupportedByAdapter, ExportCSV(&buf, "nothing"))
+}
+
+func TestExportJSONLines(t *testing.T) {
+	iter := newRowsIterator()
+
+	var buf bytes.Buffer
+	assert.NoError(t, ExportJSONLines(&buf, iter))
+	assert.Equal(t, `{"id":1,"name":"Ozzie, \"the\" Osbourne","born":"1948-12-03T00:00:00Z","avatar":"AQI=","settings":{"a":1},"extra":1.5}`+"\n"+
+		`{"id":2,"name":null,"born":"unknown","avatar":null,"settings":null,"extra":"x"}`+"\n", buf.String())
+	assert.True(t, iter.closed)
+}
+
+func TestExportColumnar(t *testing.T) {
+	iter := newRowsIterator()
+	iter.rows = append(iter.rows, iter.rows[0])
+
+	var batches []*ColumnBatch
+	err := ExportColumnar(iter, 2, func(batch *ColumnBatch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, iter.closed)
+
+	if assert.Len(t, batches, 2) {
+		first := batches[0]
+		assert.Equal(t, 2, first.Len)
+		assert.Equal(t, []int64{1, 2}, first.Values[0])
+		assert.Equal(t, []string{"Ozzie, \"the\" Osbourne", ""}, first.Values[1])
+		assert.Equal(t, []bool{false, true}, first.Nulls[1])
+		assert.Equal(t, []interface{}{time.Date(1948, 12, 3, 0, 0, 0, 0, time.UTC), "unknown"}, first.Values[2])
+		assert.Equal(t, [][]byte{{1, 2}, nil}, first.Values[3])
+		assert.Equal(t, []interface{}{1.5, "x"}, first.Values[5])
+
+		second := batches[1]
+		assert.Equal(t, 1, second.Len)
+		assert.Equal(t, []int64{1}, second.Values[0])
+		assert.Equal(t, []time.Time{time.Date(1948, 12, 3, 0, 0, 0, 0, time.UTC)}, second.Values[2])
+	}
+
+	assert.Equal(t, ErrInvalidChunkSize, ExportColumnar(iter, 0, nil))
+}
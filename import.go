@@ -0,0 +1,559 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportFormat is the format of the input of Import.
+type ImportFormat uint8
+
+// Import formats.
+const (
+	ImportCSV ImportFormat = iota
+	ImportJSONLines
+)
+
+// defaultImportBatchSize is the number of rows inserted by each statement of
+// Import if ImportOptions.BatchSize is not set.
+const defaultImportBatchSize = 100
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Format is the format of the input, CSV with a header by default.
+	Format ImportFormat
+
+	// Columns maps the CSV headers or JSON keys of the input to the columns of
+	// the collection. Headers that are not in it are imported into the column
+	// with the same name, headers mapped to "-" are skipped.
+	Columns map[string]string
+
+	// Types maps columns to the Go types their values are converted to before
+	// being inserted: string, int64, float64, bool, time.Time, json.RawMessage
+	// or []byte, the types of ResultColumn. Empty CSV fields of columns with a
+	// type other than string are imported as NULL.
+	Types map[string]reflect.Type
+
+	// BatchSize is the maximum number of rows inserted by each statement, it
+	// defaults to 100.
+	BatchSize int
+
+	// ConflictColumns turns inserts into upserts, see
+	// Inserter.OnConflictUpdate: rows that conflict with existing rows on
+	// these columns update the UpdateColumns of those rows, or all their
+	// imported columns but the conflict ones if UpdateColumns is empty.
+	ConflictColumns []string
+	UpdateColumns   []string
+
+	// Rejects receives the rows that can't be imported, in the format of the
+	// input: CSV rows with an additional error field, after a header, or JSON
+	// objects with the line, the error and the row. Import stops at the first
+	// such row if Rejects is nil.
+	Rejects io.Writer
+
+	// Progress is called after each batch is inserted.
+	Progress func(ImportStats)
+}
+
+// ImportStats counts the rows processed by Import.
+type ImportStats struct {
+	// Read is the number of rows read from the input.
+	Read int
+
+	// Imported is the number of rows that were inserted or upserted.
+	Imported int
+
+	// Rejected is the number of rows that were written to Rejects.
+	Rejected int
+}
+
+// Import reads rows from r and inserts them into the given collection in
+// batches, see ImportOptions. Rows are read and inserted one batch at a time.
+// Rows of a batch that fails are inserted one by one, so only the ones that
+// can't be inserted are rejected. The values of the encrypted columns declared
+// for the collection are encrypted before being inserted, see
+// Codecs.EncryptColumns.
+//
+// Example:
+//
+//   stats, err := db.Import(sess.Collection("people"), file, db.ImportOptions{
+//     Columns: map[string]string{"Full Name": "name"},
+//     Types:   map[string]reflect.Type{"born": reflect.TypeOf(time.Time{})},
+//     Rejects: rejects,
+//   })
+func Import(col Collection, r io.Reader, opts ImportOptions) (ImportStats, error) {
+	sqlCol, ok := col.(interface {
+		SQL() SQL
+	})
+	if !ok {
+		return ImportStats{}, ErrNotSupportedByAdapter
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+
+	im := &importer{sql: sqlCol.SQL(), table: col.Name(), opts: opts}
+	if err := im.encryptColumns(col.Session()); err != nil {
+		return ImportStats{}, err
+	}
+
+	var err error
+	switch opts.Format {
+	case ImportCSV:
+		err = im.readCSV(r)
+	case ImportJSONLines:
+		err = im.readJSONLines(r)
+	default:
+		err = fmt.Errorf("upper: unknown import format %d", opts.Format)
+	}
+	if err == nil {
+		err = im.flush()
+	}
+	return im.stats, err
+}
+
+// importRow is a row read by Import.
+type importRow struct {
+	line    int
+	columns []string
+	values  []interface{}
+
+	// fields or object are the row as read from the input.
+	fields []string
+	object []byte
+}
+
+type importer struct {
+	sql   SQL
+	table string
+	opts  ImportOptions
+	stats ImportStats
+
+	// encrypted holds the codecs of the encrypted columns of the table.
+	encrypted map[string]Codec
+
+	pending []*importRow
+
+	header  []string
+	rejects *csv.Writer
+}
+
+// column returns the column the given header is imported into, or an empty
+// string if it's skipped.
+func (im *importer) column(header string) string {
+	if column, ok := im.opts.Columns[header]; ok {
+		if column == "-" {
+			return ""
+		}
+		return column
+	}
+	return header
+}
+
+func (im *importer) readCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	im.header = header
+
+	var columns []string
+	var indexes []int
+	for i := range header {
+		if column := im.column(header[i]); column != "" {
+			columns = append(columns, column)
+			indexes = append(indexes, i)
+		}
+	}
+
+	for line := 2; ; line++ {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		row := &importRow{line: line, fields: fields, columns: columns}
+		if err != nil {
+			if !errors.Is(err, csv.ErrFieldCount) {
+				return err
+			}
+			if err := im.add(row, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		row.values = make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			field := fields[index]
+			t := im.opts.Types[columns[i]]
+			if field == "" && t != nil && t.Kind() != reflect.String {
+				continue
+			}
+			if row.values[i], err = coerceValue(field, t); err != nil {
+				err = fmt.Errorf("column %q: %w", columns[i], err)
+				break
+			}
+		}
+		if err := im.add(row, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (im *importer) readJSONLines(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		object := bytes.TrimSpace(scanner.Bytes())
+		if len(object) == 0 {
+			continue
+		}
+		row := &importRow{line: line, object: append([]byte(nil), object...)}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(object, &fields); err != nil {
+			if err := im.add(row, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		values := map[string]interface{}{}
+		var err error
+		for key, raw := range fields {
+			column := im.column(key)
+			if column == "" {
+				continue
+			}
+			if values[column], err = coerceValue(jsonImportValue(raw), im.opts.Types[column]); err != nil {
+				err = fmt.Errorf("column %q: %w", column, err)
+				break
+			}
+			row.columns = append(row.columns, column)
+		}
+		if err == nil {
+			sort.Strings(row.columns)
+			row.values = make([]interface{}, len(row.columns))
+			for i, column := range row.columns {
+				row.values[i] = values[column]
+			}
+		}
+		if err := im.add(row, err); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// encryptColumns sets up the encryption of the encrypted columns declared for
+// the table by the codecs of sess, see Codecs.EncryptColumns.
+func (im *importer) encryptColumns(sess Session) error {
+	s, ok := sess.(interface {
+		Codecs() *Codecs
+	})
+	if !ok {
+		return nil
+	}
+	codecs := s.Codecs()
+	columns := codecs.EncryptedColumns(im.table)
+	if len(columns) == 0 {
+		return nil
+	}
+	keys := codecs.KeyProvider()
+	if keys == nil {
+		return ErrMissingKeyProvider
+	}
+	im.encrypted = make(map[string]Codec, len(columns))
+	for column, deterministic := range columns {
+		im.encrypted[column] = NewEncryptedCodec(keys, deterministic)
+	}
+	return nil
+}
+
+// encrypt encrypts the values of the encrypted columns of a row.
+func (im *importer) encrypt(row *importRow) error {
+	for i, column := range row.columns {
+		codec, ok := im.encrypted[column]
+		if !ok {
+			continue
+		}
+		value, err := codec.Encode(row.values[i])
+		if err != nil {
+			return err
+		}
+		row.values[i] = value
+	}
+	return nil
+}
+
+// add queues a row to be inserted, or rejects it if it couldn't be read.
+func (im *importer) add(row *importRow, err error) error {
+	im.stats.Read++
+	if err == nil {
+		err = im.encrypt(row)
+	}
+	if err != nil {
+		return im.reject(row, err)
+	}
+	if len(im.pending) > 0 && !sameColumns(im.pending[0].columns, row.columns) {
+		if err := im.flush(); err != nil {
+			return err
+		}
+	}
+	im.pending = append(im.pending, row)
+	if len(im.pending) >= im.opts.BatchSize {
+		return im.flush()
+	}
+	return nil
+}
+
+// flush inserts the queued rows.
+func (im *importer) flush() error {
+	rows := im.pending
+	im.pending = nil
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := im.insert(rows); err == nil {
+		im.stats.Imported += len(rows)
+	} else if len(rows) == 1 {
+		if err := im.reject(rows[0], err); err != nil {
+			return err
+		}
+	} else {
+		for _, row := range rows {
+			if err := im.insert([]*importRow{row}); err != nil {
+				if err := im.reject(row, err); err != nil {
+					return err
+				}
+				continue
+			}
+			im.stats.Imported++
+		}
+	}
+
+	if im.opts.Progress != nil {
+		im.opts.Progress(im.stats)
+	}
+	return nil
+}
+
+func (im *importer) insert(rows []*importRow) error {
+	columns := rows[0].columns
+
+	ins := im.sql.InsertInto(im.table).Columns(columns...)
+	if len(im.opts.ConflictColumns) > 0 {
+		update := im.opts.UpdateColumns
+		if len(update) == 0 {
+			update = updateColumns(columns, im.opts.ConflictColumns)
+		}
+		ins = ins.OnConflictUpdate(im.opts.ConflictColumns, update...)
+	}
+
+	batch := ins.Batch(len(rows))
+	for _, row := range rows {
+		batch.Values(row.values...)
+	}
+	batch.Done()
+	return batch.Wait()
+}
+
+// reject writes a row that can't be imported to the rejects, or returns the
+// error that prevented importing it if there are none.
+func (im *importer) reject(row *importRow, err error) error {
+	if im.opts.Rejects == nil {
+		return fmt.Errorf("upper: can't import line %d: %w", row.line, err)
+	}
+	im.stats.Rejected++
+
+	if row.fields != nil {
+		if im.rejects == nil {
+			im.rejects = csv.NewWriter(im.opts.Rejects)
+			if err := im.rejects.Write(append(append([]string{}, im.header...), "error")); err != nil {
+				return err
+			}
+		}
+		if err := im.rejects.Write(append(append([]string{}, row.fields...), err.Error())); err != nil {
+			return err
+		}
+		im.rejects.Flush()
+		return im.rejects.Error()
+	}
+
+	var object interface{} = string(row.object)
+	if json.Valid(row.object) {
+		object = json.RawMessage(row.object)
+	}
+	rejected, jsonErr := json.Marshal(struct {
+		Line  int         `json:"line"`
+		Error string      `json:"error"`
+		Row   interface{} `json:"row"`
+	}{row.line, err.Error(), object})
+	if jsonErr != nil {
+		return jsonErr
+	}
+	_, err = im.opts.Rejects.Write(append(rejected, '\n'))
+	return err
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// updateColumns returns the columns that are not conflict columns.
+func updateColumns(columns []string, conflict []string) []string {
+	update := make([]string, 0, len(columns))
+	for _, column := range columns {
+		isConflict := false
+		for i := range conflict {
+			if conflict[i] == column {
+				isConflict = true
+				break
+			}
+		}
+		if !isConflict {
+			update = append(update, column)
+		}
+	}
+	return update
+}
+
+// jsonImportValue converts a JSON value into a string, a json.Number, a bool,
+// nil or, for objects and arrays, a json.RawMessage.
+func jsonImportValue(raw json.RawMessage) interface{} {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return raw
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return raw
+	}
+	return v
+}
+
+var importTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// coerceValue converts a value read by Import into a value of type t, values
+// are returned as they are if t is nil. JSON numbers are converted into int64
+// or float64 values.
+func coerceValue(v interface{}, t reflect.Type) (interface{}, error) {
+	if n, ok := v.(json.Number); ok && t == nil {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		return n.Float64()
+	}
+	if v == nil || t == nil {
+		if raw, ok := v.(json.RawMessage); ok {
+			return string(raw), nil
+		}
+		return v, nil
+	}
+
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case json.Number:
+		s = x.String()
+	case json.RawMessage:
+		s = string(x)
+	case bool:
+		s = strconv.FormatBool(x)
+	}
+	s = strings.TrimSpace(s)
+
+	var out interface{}
+	var err error
+	switch t {
+	case reflect.TypeOf(""):
+		out = s
+	case reflect.TypeOf(int64(0)):
+		out, err = strconv.ParseInt(s, 10, 64)
+	case reflect.TypeOf(float64(0)):
+		out, err = strconv.ParseFloat(s, 64)
+	case reflect.TypeOf(false):
+		out, err = strconv.ParseBool(s)
+	case reflect.TypeOf(time.Time{}):
+		for _, layout := range importTimeLayouts {
+			var tm time.Time
+			if tm, err = time.Parse(layout, s); err == nil {
+				out = tm
+				break
+			}
+		}
+	case reflect.TypeOf(json.RawMessage{}):
+		// Strings that are not JSON are imported as JSON strings.
+		if json.Valid([]byte(s)) {
+			out = json.RawMessage(s)
+		} else if _, ok := v.(string); ok {
+			quoted, _ := json.Marshal(s)
+			out = json.RawMessage(quoted)
+		} else {
+			err = errors.New("invalid JSON")
+		}
+	case reflect.TypeOf([]byte{}):
+		out, err = base64.StdEncoding.DecodeString(s)
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %v", ErrInvalidImportValue, t)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %q to %v: %v", ErrInvalidImportValue, s, t, err)
+	}
+	return out, nil
+}
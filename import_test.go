@@ -0,0 +1,87 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoerceValue(t *testing.T) {
+	samples := []struct {
+		in  interface{}
+		t   reflect.Type
+		out interface{}
+	}{
+		{"hello", nil, "hello"},
+		{json.Number("12"), nil, int64(12)},
+		{json.Number("1.5"), nil, 1.5},
+		{json.RawMessage(`{"a":1}`), nil, `{"a":1}`},
+		{nil, reflect.TypeOf(int64(0)), nil},
+		{" 42 ", reflect.TypeOf(int64(0)), int64(42)},
+		{json.Number("42"), reflect.TypeOf(""), "42"},
+		{"1.5", reflect.TypeOf(float64(0)), 1.5},
+		{"true", reflect.TypeOf(false), true},
+		{true, reflect.TypeOf(false), true},
+		{"2016-01-02 03:04:05", reflect.TypeOf(time.Time{}), time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2016-01-02T03:04:05Z", reflect.TypeOf(time.Time{}), time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{`{"a":1}`, reflect.TypeOf(json.RawMessage{}), json.RawMessage(`{"a":1}`)},
+		{"plain", reflect.TypeOf(json.RawMessage{}), json.RawMessage(`"plain"`)},
+		{"AQI=", reflect.TypeOf([]byte{}), []byte{1, 2}},
+	}
+
+	for _, sample := range samples {
+		out, err := coerceValue(sample.in, sample.t)
+		assert.NoError(t, err, "%v", sample.in)
+		assert.Equal(t, sample.out, out, "%v", sample.in)
+	}
+
+	for _, sample := range []struct {
+		in interface{}
+		t  reflect.Type
+	}{
+		{"two", reflect.TypeOf(int64(0))},
+		{"yesterday", reflect.TypeOf(time.Time{})},
+		{"!", reflect.TypeOf([]byte{})},
+		{"1", reflect.TypeOf(uint8(0))},
+	} {
+		_, err := coerceValue(sample.in, sample.t)
+		assert.True(t, errors.Is(err, ErrInvalidImportValue), "%v", sample.in)
+	}
+}
+
+func TestJSONImportValue(t *testing.T) {
+	assert.Equal(t, "a", jsonImportValue(json.RawMessage(`"a"`)))
+	assert.Equal(t, json.Number("1"), jsonImportValue(json.RawMessage(`1`)))
+	assert.Equal(t, true, jsonImportValue(json.RawMessage(`true`)))
+	assert.Nil(t, jsonImportValue(json.RawMessage(`null`)))
+	assert.Equal(t, json.RawMessage(`[1,2]`), jsonImportValue(json.RawMessage(`[1,2]`)))
+}
+
+func TestUpdateColumns(t *testing.T) {
+	assert.Equal(t, []string{"name", "born"}, updateColumns([]string{"id", "name", "born"}, []string{"id"}))
+	assert.Equal(t, []string{}, updateColumns([]string{"id"}, []string{"id"}))
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package records holds the helpers that both the SQL adapters and the
+// adapters of other databases use to work with records and callbacks.
+package records
+
+import (
+	"database/sql"
+	"reflect"
+
+	db "github.com/upper/db/v4"
+)
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	afterFindHookType = reflect.TypeOf((*db.AfterFindHook)(nil)).Elem()
+)
+
+// CallbackOf checks that fn is a function that takes a single argument and
+// returns an error, and returns the type of that argument.
+func CallbackOf(fn interface{}) (reflect.Value, reflect.Type, error) {
+	fnv := reflect.ValueOf(fn)
+	if fnv.Kind() != reflect.Func || fnv.IsNil() {
+		return fnv, nil, db.ErrInvalidCallback
+	}
+	t := fnv.Type()
+	if t.NumIn() != 1 || t.NumOut() != 1 || t.Out(0) != errorType {
+		return fnv, nil, db.ErrInvalidCallback
+	}
+	return fnv, t.In(0), nil
+}
+
+// Call calls fn with arg and returns its error.
+func Call(fn reflect.Value, arg reflect.Value) error {
+	if err := fn.Call([]reflect.Value{arg})[0].Interface(); err != nil {
+		return err.(error)
+	}
+	return nil
+}
+
+// AfterFind calls the AfterFind hook of the records dst points to, dst is
+// either a pointer to a record or a pointer to a slice of records.
+func AfterFind(sess db.Session, dst interface{}) error {
+	if hook, ok := dst.(db.AfterFindHook); ok {
+		return hook.AfterFind(sess)
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	v = v.Elem()
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() && v.Type().Implements(afterFindHookType) {
+			return v.Interface().(db.AfterFindHook).AfterFind(sess)
+		}
+		return nil
+	case reflect.Slice:
+	default:
+		return nil
+	}
+
+	t := v.Type().Elem()
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+	if !reflect.PtrTo(t).Implements(afterFindHookType) {
+		return nil
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		if isPtr {
+			if item.IsNil() {
+				continue
+			}
+		} else {
+			item = item.Addr()
+		}
+		if err := item.Interface().(db.AfterFindHook).AfterFind(sess); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetKeyField sets field to the given generated key. The key is converted to
+// the type of the field if needed, sql.Scanner fields are also supported.
+func SetKeyField(field reflect.Value, key interface{}) error {
+	v := reflect.ValueOf(key)
+	if !v.IsValid() {
+		return db.ErrInvalidKeyField
+	}
+	if field.Kind() == reflect.Ptr && v.Type().ConvertibleTo(field.Type().Elem()) {
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(v.Convert(field.Type().Elem()))
+		field.Set(ptr)
+		return nil
+	}
+	if field.CanAddr() {
+		if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(key)
+		}
+	}
+	if !v.Type().ConvertibleTo(field.Type()) {
+		return db.ErrInvalidKeyField
+	}
+	// Numbers convert to strings as runes, only same-kind conversions are
+	// allowed.
+	if (field.Kind() == reflect.String) != (v.Kind() == reflect.String) {
+		return db.ErrInvalidKeyField
+	}
+	field.Set(v.Convert(field.Type()))
+	return nil
+}
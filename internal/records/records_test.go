@@ -0,0 +1,73 @@
+package records
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+func TestSetKeyField(t *testing.T) {
+	type ID string
+
+	var item struct {
+		Str     string
+		Named   ID
+		Ptr     *string
+		Int     int64
+		Uint    uint64
+		Null    sql.NullString
+		Invalid bool
+	}
+	v := reflect.ValueOf(&item).Elem()
+
+	assert.NoError(t, SetKeyField(v.FieldByName("Str"), "a"))
+	assert.NoError(t, SetKeyField(v.FieldByName("Named"), "b"))
+	assert.NoError(t, SetKeyField(v.FieldByName("Ptr"), "c"))
+	assert.NoError(t, SetKeyField(v.FieldByName("Int"), int64(4)))
+	assert.NoError(t, SetKeyField(v.FieldByName("Uint"), int64(5)))
+	assert.NoError(t, SetKeyField(v.FieldByName("Null"), "f"))
+
+	assert.Equal(t, "a", item.Str)
+	assert.Equal(t, ID("b"), item.Named)
+	assert.Equal(t, "c", *item.Ptr)
+	assert.Equal(t, int64(4), item.Int)
+	assert.Equal(t, uint64(5), item.Uint)
+	assert.Equal(t, sql.NullString{String: "f", Valid: true}, item.Null)
+
+	assert.Equal(t, db.ErrInvalidKeyField, SetKeyField(v.FieldByName("Str"), int64(1)))
+	assert.Equal(t, db.ErrInvalidKeyField, SetKeyField(v.FieldByName("Int"), "1"))
+	assert.Equal(t, db.ErrInvalidKeyField, SetKeyField(v.FieldByName("Invalid"), "1"))
+	assert.Equal(t, db.ErrInvalidKeyField, SetKeyField(v.FieldByName("Str"), nil))
+}
+
+type afterFindItem struct {
+	found bool
+}
+
+func (*afterFindItem) Store(sess db.Session) db.Store {
+	return nil
+}
+
+func (item *afterFindItem) AfterFind(sess db.Session) error {
+	item.found = true
+	return nil
+}
+
+func TestAfterFind(t *testing.T) {
+	one := &afterFindItem{}
+	assert.NoError(t, AfterFind(nil, &one))
+	assert.True(t, one.found)
+
+	items := []afterFindItem{{}, {}}
+	assert.NoError(t, AfterFind(nil, &items))
+	assert.True(t, items[0].found && items[1].found)
+
+	ptrs := []*afterFindItem{{}, nil}
+	assert.NoError(t, AfterFind(nil, &ptrs))
+	assert.True(t, ptrs[0].found)
+
+	assert.NoError(t, AfterFind(nil, &[]int{1}))
+}
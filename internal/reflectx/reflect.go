@@ -62,7 +62,7 @@ func (f StructMap) GetByTraversal(index []int) *FieldInfo {
 // mapping and a function to provide a basic mapping of fields to names.
 type Mapper struct {
 	cache      map[reflect.Type]*StructMap
-	tagName    string
+	tagNames   []string
 	tagMapFunc func(string) string
 	mapFunc    func(string) string
 	mutex      sync.Mutex
@@ -72,8 +72,8 @@ type Mapper struct {
 // by tagName.  If tagName is the empty string, it is ignored.
 func NewMapper(tagName string) *Mapper {
 	return &Mapper{
-		cache:   make(map[reflect.Type]*StructMap),
-		tagName: tagName,
+		cache:    make(map[reflect.Type]*StructMap),
+		tagNames: []string{tagName},
 	}
 }
 
@@ -83,7 +83,7 @@ func NewMapper(tagName string) *Mapper {
 func NewMapperTagFunc(tagName string, mapFunc, tagMapFunc func(string) string) *Mapper {
 	return &Mapper{
 		cache:      make(map[reflect.Type]*StructMap),
-		tagName:    tagName,
+		tagNames:   []string{tagName},
 		mapFunc:    mapFunc,
 		tagMapFunc: tagMapFunc,
 	}
@@ -94,9 +94,19 @@ func NewMapperTagFunc(tagName string, mapFunc, tagMapFunc func(string) string) *
 // for any other field, the mapped name will be f(field.Name)
 func NewMapperFunc(tagName string, f func(string) string) *Mapper {
 	return &Mapper{
-		cache:   make(map[reflect.Type]*StructMap),
-		tagName: tagName,
-		mapFunc: f,
+		cache:    make(map[reflect.Type]*StructMap),
+		tagNames: []string{tagName},
+		mapFunc:  f,
+	}
+}
+
+// NewMapperTagsFunc returns a new mapper like NewMapperFunc that obeys the
+// first of the given field tags that a field has.
+func NewMapperTagsFunc(tagNames []string, f func(string) string) *Mapper {
+	return &Mapper{
+		cache:    make(map[reflect.Type]*StructMap),
+		tagNames: tagNames,
+		mapFunc:  f,
 	}
 }
 
@@ -106,7 +116,7 @@ func (m *Mapper) TypeMap(t reflect.Type) *StructMap {
 	m.mutex.Lock()
 	mapping, ok := m.cache[t]
 	if !ok {
-		mapping = getMapping(t, m.tagName, m.mapFunc, m.tagMapFunc)
+		mapping = getMapping(t, m.tagNames, m.mapFunc, m.tagMapFunc)
 		m.cache[t] = mapping
 	}
 	m.mutex.Unlock()
@@ -291,9 +301,9 @@ func apnd(is []int, i int) []int {
 	return x
 }
 
-// getMapping returns a mapping for the t type, using the tagName, mapFunc and
+// getMapping returns a mapping for the t type, using the tagNames, mapFunc and
 // tagMapFunc to determine the canonical names of fields.
-func getMapping(t reflect.Type, tagName string, mapFunc, tagMapFunc func(string) string) *StructMap {
+func getMapping(t reflect.Type, tagNames []string, mapFunc, tagMapFunc func(string) string) *StructMap {
 	m := []*FieldInfo{}
 
 	root := &FieldInfo{}
@@ -320,14 +330,18 @@ func getMapping(t reflect.Type, tagName string, mapFunc, tagMapFunc func(string)
 			fi.Options = map[string]string{}
 
 			var tag, name string
-			if tagName != "" && strings.Contains(string(f.Tag), tagName+":") {
-				tag = f.Tag.Get(tagName)
-				name = tag
-			} else {
-				if mapFunc != nil {
-					name = mapFunc(f.Name)
+			tagged := false
+			for _, tagName := range tagNames {
+				if tagName != "" && strings.Contains(string(f.Tag), tagName+":") {
+					tag = f.Tag.Get(tagName)
+					name = tag
+					tagged = true
+					break
 				}
 			}
+			if !tagged && mapFunc != nil {
+				name = mapFunc(f.Name)
+			}
 
 			parts := strings.Split(name, ",")
 			if len(parts) > 1 {
@@ -0,0 +1,710 @@
+package sqladapter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter/exql"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// recordBatchSize is the maximum number of rows that are inserted, updated or
+// deleted by a single statement of SaveAll and DeleteAll.
+const recordBatchSize = 100
+
+func (sess *sessionWithContext) SaveAll(records ...db.Record) error {
+	if err := checkRecords(records); err != nil {
+		return err
+	}
+	return sess.withTransaction(func(tx Session) error {
+		return saveRecords(tx, records)
+	})
+}
+
+func (sess *sessionWithContext) DeleteAll(records ...db.Record) error {
+	if err := checkRecords(records); err != nil {
+		return err
+	}
+	return sess.withTransaction(func(tx Session) error {
+		return deleteRecords(tx, records)
+	})
+}
+
+// withTransaction runs fn within the transaction of the session, or within a
+// new transaction if the session is not a transaction.
+func (sess *sessionWithContext) withTransaction(fn func(tx Session) error) error {
+	if sess.IsTransaction() {
+		return fn(sess)
+	}
+	return sess.TxContext(sess.Context(), func(tx db.Session) error {
+		return fn(tx.(Session))
+	}, nil)
+}
+
+func checkRecords(records []db.Record) error {
+	for _, record := range records {
+		if record == nil {
+			return db.ErrNilRecord
+		}
+		if reflect.TypeOf(record).Kind() != reflect.Ptr {
+			return db.ErrExpectingPointerToStruct
+		}
+	}
+	return nil
+}
+
+// recordKey returns the primary key of a record, or nil if any of its
+// primary key fields is not set.
+func recordKey(store db.Store, record db.Record) (db.Cond, error) {
+	keys, values, err := recordPrimaryKeyFieldValues(store, record)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 || len(values) != len(keys) {
+		return nil, nil
+	}
+	id := db.Cond{}
+	for i := range values {
+		if values[i] == reflect.Zero(reflect.TypeOf(values[i])).Interface() {
+			return nil, nil
+		}
+		id[keys[i]] = values[i]
+	}
+	return id, nil
+}
+
+// singleColumn returns the column of the given conditions if all of them are
+// made of the same single column.
+func singleColumn(ids []db.Cond) string {
+	column := ""
+	for _, id := range ids {
+		if len(id) != 1 {
+			return ""
+		}
+		for key := range id {
+			name, ok := key.(string)
+			if !ok || (column != "" && name != column) {
+				return ""
+			}
+			column = name
+		}
+	}
+	return column
+}
+
+// anyOf returns a condition that matches the rows of any of the given
+// primary keys.
+func anyOf(ids []db.Cond) interface{} {
+	if column := singleColumn(ids); column != "" {
+		values := make([]interface{}, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, id[column])
+		}
+		return db.Cond{column: db.In(values...)}
+	}
+	conds := make([]db.LogicalExpr, 0, len(ids))
+	for _, id := range ids {
+		conds = append(conds, id)
+	}
+	return db.Or(conds...)
+}
+
+// batches splits n items into batches of at most size items and calls fn
+// with the bounds of every batch.
+func batches(n int, size int, fn func(start, end int) error) error {
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		if err := fn(start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveRecords(sess Session, records []db.Record) error {
+	stores := make([]db.Store, len(records))
+	ids := make([]db.Cond, len(records))
+	exists := make([]bool, len(records))
+
+	tables := []string{}
+	byTable := map[string][]int{}
+	for i, record := range records {
+		store := record.Store(sess)
+		stores[i] = store
+		if _, ok := store.(db.StoreSaver); ok {
+			continue
+		}
+		id, err := recordKey(store, record)
+		if err != nil {
+			return err
+		}
+		if id == nil {
+			if _, ok := store.(db.StoreCreator); ok {
+				continue
+			}
+			// Records with generated keys are new, so they can be inserted in
+			// batches without looking them up.
+			generated, err := generateRecordKeys(store, record)
+			if err != nil {
+				return err
+			}
+			if generated {
+				if ids[i], err = recordKey(store, record); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		ids[i] = id
+		if _, ok := byTable[store.Name()]; !ok {
+			tables = append(tables, store.Name())
+		}
+		byTable[store.Name()] = append(byTable[store.Name()], i)
+	}
+
+	// Records with a primary key are looked up in batches to find out which
+	// ones must be updated.
+	for _, table := range tables {
+		indexes := byTable[table]
+		store := stores[indexes[0]]
+		keys := make([]db.Cond, 0, len(indexes))
+		for _, i := range indexes {
+			keys = append(keys, ids[i])
+		}
+		found, err := existingKeys(store, keys)
+		if err != nil {
+			return err
+		}
+		for j, i := range indexes {
+			exists[i] = found[j]
+		}
+	}
+
+	inserts := &recordInserts{}
+	updates := &recordUpdates{}
+	returning := supportsReturning(sess)
+	for i, record := range records {
+		store := stores[i]
+		_, isSaver := store.(db.StoreSaver)
+		_, isCreator := store.(db.StoreCreator)
+		_, isUpdater := store.(db.StoreUpdater)
+
+		if !isSaver && !exists[i] && !isCreator {
+			batched := ids[i] != nil
+			if !batched && returning {
+				// New records without a primary key are inserted in batches that
+				// return the keys the database gives them.
+				pks, err := sess.PrimaryKeys(store.Name())
+				if err != nil {
+					return err
+				}
+				batched = len(pks) > 0
+			}
+			if batched {
+				if err := recordBeforeCreate(store, record); err != nil {
+					return err
+				}
+				if err := inserts.add(store, record, ids[i]); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !isSaver && exists[i] && !isUpdater && singleColumn(ids[i:i+1]) != "" && versionFieldOf(mapperOf(sess), record) == nil {
+			// Existing records are updated in batches, unless they're updated
+			// with optimistic locking.
+			if err := recordBeforeUpdate(store, record); err != nil {
+				return err
+			}
+			if err := updates.add(store, record, ids[i]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Pending inserts and updates go first to keep the order of the
+		// records.
+		if err := inserts.flush(); err != nil {
+			return err
+		}
+		if err := updates.flush(); err != nil {
+			return err
+		}
+
+		if isSaver {
+			if err := store.(db.StoreSaver).Save(record); err != nil {
+				return err
+			}
+		} else if exists[i] {
+			if err := recordUpdate(store, record); err != nil {
+				return err
+			}
+		} else {
+			if err := recordCreate(store, record); err != nil {
+				return err
+			}
+		}
+		snapshotRecord(sess, record)
+	}
+
+	if err := inserts.flush(); err != nil {
+		return err
+	}
+	return updates.flush()
+}
+
+// supportsReturning reports whether the adapter of the session supports
+// RETURNING on statements that insert many rows.
+func supportsReturning(sess db.Session) bool {
+	s, ok := sess.(*sessionWithContext)
+	if !ok {
+		return false
+	}
+	r, ok := s.adapter.(rowsReturner)
+	return ok && r.SupportsReturning()
+}
+
+// quoteColumn returns the name of the given column as it is written in the
+// statements of the session.
+func quoteColumn(sess db.Session, column string) (string, error) {
+	s, ok := sess.(*sessionWithContext)
+	if !ok {
+		return column, nil
+	}
+	return exql.ColumnWithName(column).Compile(s.adapter.Template())
+}
+
+// existingKeys reports which of the given primary keys have a row.
+func existingKeys(store db.Store, ids []db.Cond) ([]bool, error) {
+	found := make([]bool, len(ids))
+
+	column := singleColumn(ids)
+	if column == "" {
+		for i := range ids {
+			count, err := store.Find(ids[i]).WithDeleted().Unscoped().Count()
+			if err != nil {
+				return nil, err
+			}
+			found[i] = count > 0
+		}
+		return found, nil
+	}
+
+	keys := map[string]bool{}
+	err := batches(len(ids), preloadBatchSize, func(start, end int) error {
+		var rows []map[string]interface{}
+		err := store.Find(anyOf(ids[start:end])).
+			WithDeleted().
+			Unscoped().
+			Select(column).
+			All(&rows)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			keys[keyOf(row[column])] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range ids {
+		found[i] = keys[keyOf(ids[i][column])]
+	}
+	return found, nil
+}
+
+// recordInserts holds new records that are inserted in batches, grouped by
+// table, type and the columns they set.
+type recordInserts struct {
+	groups []*recordInsertGroup
+	index  map[string]*recordInsertGroup
+}
+
+type recordInsertGroup struct {
+	store   db.Store
+	columns []string
+	records []db.Record
+	ids     []db.Cond
+	values  [][]interface{}
+
+	// returning is true if the keys of the records are returned by the
+	// database.
+	returning bool
+}
+
+// add adds a new record to the inserts, id is nil if the database gives the
+// record its key.
+func (ri *recordInserts) add(store db.Store, record db.Record, id db.Cond) error {
+	columns, values, err := sqlbuilder.Map(record, &sqlbuilder.MapOptions{
+		Mapper: mapperOf(store.Session()),
+		Codecs: codecsOf(store.Session()),
+	})
+	if err != nil {
+		return err
+	}
+
+	returning := id == nil
+	key := fmt.Sprintf("%s\x00%T\x00%s\x00%t", store.Name(), record, strings.Join(columns, ","), returning)
+	if ri.index == nil {
+		ri.index = map[string]*recordInsertGroup{}
+	}
+	group, ok := ri.index[key]
+	if !ok {
+		group = &recordInsertGroup{store: store, columns: columns, returning: returning}
+		ri.index[key] = group
+		ri.groups = append(ri.groups, group)
+	}
+
+	group.records = append(group.records, record)
+	group.ids = append(group.ids, id)
+	group.values = append(group.values, values)
+	return nil
+}
+
+func (ri *recordInserts) flush() error {
+	for _, group := range ri.groups {
+		if err := group.insert(); err != nil {
+			return err
+		}
+	}
+	ri.groups, ri.index = nil, nil
+	return nil
+}
+
+func (g *recordInsertGroup) insert() error {
+	sess := g.store.Session()
+
+	if g.returning && len(g.columns) == 0 {
+		// There are no values to insert many rows with.
+		for _, record := range g.records {
+			if err := recordInsert(g.store, record); err != nil {
+				return err
+			}
+			if err := recordAfterCreate(g.store, record); err != nil {
+				return err
+			}
+			snapshotRecord(sess, record)
+		}
+		return nil
+	}
+
+	err := batches(len(g.values), recordBatchSize, func(start, end int) error {
+		if g.returning {
+			return g.insertReturning(start, end)
+		}
+		batch := sess.SQL().InsertInto(g.store.Name()).Columns(g.columns...).Batch(end - start)
+		for _, row := range g.values[start:end] {
+			batch.Values(row...)
+		}
+		batch.Done()
+		return batch.Wait()
+	})
+	if err != nil {
+		return err
+	}
+
+	// Rows are read back to get the values set by the database.
+	if err := reloadRecords(g.store, g.records, g.ids); err != nil {
+		return err
+	}
+
+	for _, record := range g.records {
+		if err := recordAfterCreate(g.store, record); err != nil {
+			return err
+		}
+		snapshotRecord(g.store.Session(), record)
+	}
+	return nil
+}
+
+// insertReturning inserts the rows of the records between start and end with
+// a single statement and sets their ids to the keys returned by the database.
+func (g *recordInsertGroup) insertReturning(start, end int) error {
+	sess := g.store.Session()
+
+	pks, err := sess.(Session).PrimaryKeys(g.store.Name())
+	if err != nil {
+		return err
+	}
+
+	q := sess.SQL().InsertInto(g.store.Name()).Columns(g.columns...)
+	for _, row := range g.values[start:end] {
+		q = q.Values(row...)
+	}
+
+	var keys []map[string]interface{}
+	if err := q.Returning(pks...).Iterator().All(&keys); err != nil {
+		return err
+	}
+	if len(keys) != end-start {
+		return fmt.Errorf("upper: expecting %d keys after inserting into %q, got %d", end-start, g.store.Name(), len(keys))
+	}
+
+	for i := range keys {
+		id := db.Cond{}
+		for _, pk := range pks {
+			id[pk] = keys[i][pk]
+		}
+		g.ids[start+i] = id
+	}
+	return nil
+}
+
+// recordUpdates holds existing records that are updated in batches, grouped
+// by table, type and the columns they set.
+type recordUpdates struct {
+	groups []*recordUpdateGroup
+	index  map[string]*recordUpdateGroup
+}
+
+type recordUpdateGroup struct {
+	store   db.Store
+	column  string
+	columns []string
+	records []db.Record
+	ids     []db.Cond
+	values  [][]interface{}
+}
+
+func (ru *recordUpdates) add(store db.Store, record db.Record, id db.Cond) error {
+	sess := store.Session()
+
+	var item interface{} = record
+	if changes := db.Changed(record); changes != nil {
+		// Tracked records only send the columns that changed.
+		values, err := changedValues(store, record, changes)
+		if err != nil {
+			return err
+		}
+		item = values
+	}
+
+	column := singleColumn([]db.Cond{id})
+	columns, values, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{
+		Mapper: mapperOf(sess),
+		Codecs: codecsOf(sess),
+		Table:  store.Name(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The key doesn't change, so it's left out.
+	for i := range columns {
+		if columns[i] == column {
+			columns = append(columns[:i:i], columns[i+1:]...)
+			values = append(values[:i:i], values[i+1:]...)
+			break
+		}
+	}
+
+	key := fmt.Sprintf("%s\x00%T\x00%s\x00%s", store.Name(), record, column, strings.Join(columns, ","))
+	if ru.index == nil {
+		ru.index = map[string]*recordUpdateGroup{}
+	}
+	group, ok := ru.index[key]
+	if !ok {
+		group = &recordUpdateGroup{store: store, column: column, columns: columns}
+		ru.index[key] = group
+		ru.groups = append(ru.groups, group)
+	}
+
+	group.records = append(group.records, record)
+	group.ids = append(group.ids, id)
+	group.values = append(group.values, values)
+	return nil
+}
+
+func (ru *recordUpdates) flush() error {
+	for _, group := range ru.groups {
+		if err := group.update(); err != nil {
+			return err
+		}
+	}
+	ru.groups, ru.index = nil, nil
+	return nil
+}
+
+// update sets the values of every column of the records of the group with a
+// single statement per batch, like:
+//
+//  UPDATE "artist" SET "name" = CASE WHEN "id" = ? THEN ? ... ELSE "name" END
+//  WHERE "id" IN (...)
+func (g *recordUpdateGroup) update() error {
+	sess := g.store.Session()
+
+	if len(g.columns) > 0 {
+		key, err := quoteColumn(sess, g.column)
+		if err != nil {
+			return err
+		}
+		err = batches(len(g.records), recordBatchSize, func(start, end int) error {
+			set := make(map[string]interface{}, len(g.columns))
+			for j, column := range g.columns {
+				name, err := quoteColumn(sess, column)
+				if err != nil {
+					return err
+				}
+				expr := "CASE"
+				args := make([]interface{}, 0, 2*(end-start))
+				for i := start; i < end; i++ {
+					expr += " WHEN " + key + " = ? THEN ?"
+					args = append(args, g.ids[i][g.column], g.values[i][j])
+				}
+				set[column] = db.Raw(expr+" ELSE "+name+" END", args...)
+			}
+			_, err := sess.SQL().
+				Update(g.store.Name()).
+				Set(set).
+				Where(anyOf(g.ids[start:end])).
+				Exec()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		// Rows are read back to get the values set by the database.
+		if err := reloadRecords(g.store, g.records, g.ids); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range g.records {
+		if err := recordAfterUpdate(g.store, record); err != nil {
+			return err
+		}
+		snapshotRecord(sess, record)
+	}
+	return nil
+}
+
+// reloadRecords replaces the values of records of the same type and table
+// with the ones of their rows.
+func reloadRecords(store db.Store, records []db.Record, ids []db.Cond) error {
+	column := singleColumn(ids)
+	if column == "" {
+		for i := range records {
+			if err := store.Find(ids[i]).WithDeleted().Unscoped().One(records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	byKey := make(map[string]reflect.Value, len(records))
+	for i := range records {
+		byKey[keyOf(ids[i][column])] = reflect.ValueOf(records[i])
+	}
+
+	mapper := mapperOf(store.Session())
+	recordType := reflect.TypeOf(records[0])
+	return batches(len(records), preloadBatchSize, func(start, end int) error {
+		rows := reflect.New(reflect.SliceOf(recordType))
+		err := store.Find(anyOf(ids[start:end])).WithDeleted().Unscoped().All(rows.Interface())
+		if err != nil {
+			return err
+		}
+		for i := 0; i < rows.Elem().Len(); i++ {
+			row := rows.Elem().Index(i)
+			key, err := columnValue(mapper, row.Elem(), column)
+			if err != nil {
+				return err
+			}
+			record, ok := byKey[keyOf(key)]
+			if !ok {
+				continue
+			}
+			for name, value := range mapper.ValidFieldMap(row) {
+				mapper.FieldByName(record, name).Set(value)
+			}
+		}
+		return nil
+	})
+}
+
+type recordDeleteGroup struct {
+	store   db.Store
+	column  string
+	records []db.Record
+	ids     []db.Cond
+}
+
+func deleteRecords(sess Session, records []db.Record) error {
+	for _, record := range records {
+		if hook, ok := record.(db.BeforeDeleteHook); ok {
+			if err := hook.BeforeDelete(sess); err != nil {
+				return err
+			}
+		}
+	}
+
+	groups := []*recordDeleteGroup{}
+	index := map[string]*recordDeleteGroup{}
+	for _, record := range records {
+		store := record.Store(sess)
+		if deleter, ok := store.(db.StoreDeleter); ok {
+			if err := deleter.Delete(record); err != nil {
+				return err
+			}
+			continue
+		}
+
+		id, err := recordID(store, record)
+		if err != nil {
+			return err
+		}
+
+		column := ""
+		if softDeletable, ok := record.(db.SoftDeletable); ok {
+			column = softDeletable.SoftDeleteColumn()
+		}
+
+		key := store.Name() + "\x00" + column
+		group, ok := index[key]
+		if !ok {
+			group = &recordDeleteGroup{store: store, column: column}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.records = append(group.records, record)
+		group.ids = append(group.ids, id)
+	}
+
+	for _, group := range groups {
+		err := batches(len(group.ids), recordBatchSize, func(start, end int) error {
+			res := group.store.Find(anyOf(group.ids[start:end])).Unscoped()
+			if group.column == "" {
+				return res.Delete()
+			}
+			now := sess.Now()
+			if err := res.Update(map[string]interface{}{group.column: now}); err != nil {
+				return err
+			}
+			for _, record := range group.records[start:end] {
+				setSoftDeleteField(mapperOf(sess), record, group.column, now)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, record := range records {
+		if hook, ok := record.(db.AfterDeleteHook); ok {
+			if err := hook.AfterDelete(sess); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
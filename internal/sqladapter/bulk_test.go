@@ -0,0 +1,27 @@
+package sqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+func TestSingleColumn(t *testing.T) {
+	assert.Equal(t, "id", singleColumn([]db.Cond{{"id": 1}, {"id": 2}}))
+	assert.Equal(t, "", singleColumn([]db.Cond{{"id": 1}, {"code": 2}}))
+	assert.Equal(t, "", singleColumn([]db.Cond{{"id": 1, "code": 2}}))
+
+	assert.Equal(t, db.Cond{"id": db.In(1, 2)}, anyOf([]db.Cond{{"id": 1}, {"id": 2}}))
+	assert.IsType(t, &db.OrExpr{}, anyOf([]db.Cond{{"a": 1, "b": 2}}))
+}
+
+func TestBatches(t *testing.T) {
+	bounds := [][2]int{}
+	err := batches(5, 2, func(start, end int) error {
+		bounds = append(bounds, [2]int{start, end})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]int{{0, 2}, {2, 4}, {4, 5}}, bounds)
+}
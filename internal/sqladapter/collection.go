@@ -6,7 +6,6 @@ import (
 
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
-	"github.com/upper/db/v4/internal/sqlbuilder"
 )
 
 // CollectionAdapter defines methods to be implemented by SQL adapters.
@@ -104,6 +103,9 @@ func (c *collectionWithSession) Count() (uint64, error) {
 }
 
 func (c *collectionWithSession) Insert(item interface{}) (db.InsertResult, error) {
+	if err := checkEncrypted(c.session, c.Name(), item); err != nil {
+		return nil, err
+	}
 	id, err := c.adapter.Insert(c, item)
 	if err != nil {
 		return nil, err
@@ -141,7 +143,7 @@ func (c *collectionWithSession) Find(conds ...interface{}) db.Result {
 	}
 
 	res := NewResult(
-		c.session.SQL(),
+		c.session,
 		c.Name(),
 		filteredConds,
 	)
@@ -209,11 +211,11 @@ func (c *collectionWithSession) InsertReturning(item interface{}) error {
 	}
 
 	if len(pks) > 1 {
-		newItemRes = col.Find(id)
+		newItemRes = col.Find(id).WithDeleted().Unscoped()
 	} else {
 		// We have one primary key, build a explicit db.Cond with it to prevent
 		// string keys to be considered as raw conditions.
-		newItemRes = col.Find(db.Cond{pks[0]: id}) // We already checked that pks is not empty, so pks[0] is defined.
+		newItemRes = col.Find(db.Cond{pks[0]: id}).WithDeleted().Unscoped() // We already checked that pks is not empty, so pks[0] is defined.
 	}
 
 	// Fetch the row that was just interted into newItem
@@ -225,9 +227,9 @@ func (c *collectionWithSession) InsertReturning(item interface{}) error {
 	switch reflect.ValueOf(newItem).Elem().Kind() {
 	case reflect.Struct:
 		// Get valid fields from newItem to overwrite those that are on item.
-		newItemFieldMap = sqlbuilder.Mapper.ValidFieldMap(reflect.ValueOf(newItem))
+		newItemFieldMap = mapperOf(c.session).ValidFieldMap(reflect.ValueOf(newItem))
 		for fieldName := range newItemFieldMap {
-			sqlbuilder.Mapper.FieldByName(itemValue, fieldName).Set(newItemFieldMap[fieldName])
+			mapperOf(c.session).FieldByName(itemValue, fieldName).Set(newItemFieldMap[fieldName])
 		}
 	case reflect.Map:
 		newItemV := reflect.ValueOf(newItem).Elem()
@@ -304,26 +306,26 @@ func (c *collectionWithSession) UpdateReturning(item interface{}) error {
 
 	conds := db.Cond{}
 	for _, pk := range pks {
-		conds[pk] = db.Eq(sqlbuilder.Mapper.FieldByName(itemValue, pk).Interface())
+		conds[pk] = db.Eq(mapperOf(c.session).FieldByName(itemValue, pk).Interface())
 	}
 
 	col := tx.(Session).Collection(c.Name())
 
-	err = col.Find(conds).Update(item)
+	err = col.Find(conds).WithDeleted().Unscoped().Update(item)
 	if err != nil {
 		goto cancel
 	}
 
-	if err = col.Find(conds).One(defaultItem); err != nil {
+	if err = col.Find(conds).WithDeleted().Unscoped().One(defaultItem); err != nil {
 		goto cancel
 	}
 
 	switch reflect.ValueOf(defaultItem).Elem().Kind() {
 	case reflect.Struct:
 		// Get valid fields from defaultItem to overwrite those that are on item.
-		defaultItemFieldMap = sqlbuilder.Mapper.ValidFieldMap(reflect.ValueOf(defaultItem))
+		defaultItemFieldMap = mapperOf(c.session).ValidFieldMap(reflect.ValueOf(defaultItem))
 		for fieldName := range defaultItemFieldMap {
-			sqlbuilder.Mapper.FieldByName(itemValue, fieldName).Set(defaultItemFieldMap[fieldName])
+			mapperOf(c.session).FieldByName(itemValue, fieldName).Set(defaultItemFieldMap[fieldName])
 		}
 	case reflect.Map:
 		defaultItemV := reflect.ValueOf(defaultItem).Elem()
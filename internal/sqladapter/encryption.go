@@ -0,0 +1,52 @@
+package sqladapter
+
+import (
+	"fmt"
+	"reflect"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// checkEncrypted makes sure that the fields of the records item is or points
+// to that are tagged with the encrypted option are the encrypted columns
+// declared for the given table, see db.Codecs.EncryptColumns.
+func checkEncrypted(sess db.Session, table string, item interface{}) error {
+	t := reflect.TypeOf(item)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	declared := codecsOf(sess).EncryptedColumns(table)
+	tagged := sqlbuilder.EncryptedColumns(mapperOf(sess), t)
+	for column, deterministic := range tagged {
+		if mode, ok := declared[column]; !ok || mode != deterministic {
+			return fmt.Errorf("%w: column %q of %q", db.ErrUndeclaredEncryption, column, table)
+		}
+	}
+	if len(declared) == 0 {
+		return nil
+	}
+	names := mapperOf(sess).TypeMap(t).Names
+	for column := range declared {
+		if _, ok := tagged[column]; !ok {
+			if fi, ok := names[column]; ok && !fi.Embedded {
+				return fmt.Errorf("%w: column %q of %q", db.ErrUndeclaredEncryption, column, table)
+			}
+		}
+	}
+	return nil
+}
+
+// checkEncrypted checks the encrypted fields of values if it's a struct, the
+// values of maps are encrypted by the SQL builder.
+func (r *Result) checkEncrypted(values interface{}) error {
+	res, err := r.fastForward()
+	if err != nil {
+		return err
+	}
+	return checkEncrypted(r.Session(), res.table, values)
+}
@@ -0,0 +1,348 @@
+package exql
+
+import (
+	"strings"
+
+	"github.com/upper/db/v4/internal/cache"
+)
+
+// AlterationType is the kind of change an ALTER TABLE statement applies.
+type AlterationType uint8
+
+// Values for AlterationType.
+const (
+	AddColumn AlterationType = iota + 1
+	DropColumn
+	RenameColumn
+	RenameTable
+	AddConstraint
+	DropConstraint
+)
+
+type columnDefinitionT struct {
+	Name    string
+	Type    string
+	Default string
+	NotNull bool
+	Unique  bool
+}
+
+type constraintT struct {
+	Name              string
+	Columns           string
+	ReferencedTable   string
+	ReferencedColumns string
+	OnUpdate          string
+	OnDelete          string
+}
+
+type alterationT struct {
+	Table      string
+	Column     string
+	Constraint string
+	Name       string
+	NewName    string
+
+	// Unquoted names, for adapters that rename things with stored procedures.
+	RawTable   string
+	RawName    string
+	RawNewName string
+}
+
+// ColumnDefinition represents a column in a CREATE TABLE or ALTER TABLE
+// statement. Type is looked up in the template's ColumnTypes, types that are
+// not there are used verbatim.
+type ColumnDefinition struct {
+	Name      string
+	Type      string
+	Size      int
+	Precision int
+	Scale     int
+	NotNull   bool
+	Unique    bool
+	Default   Fragment
+}
+
+var _ = Fragment(&ColumnDefinition{})
+
+// Hash returns a unique identifier for the struct.
+func (c *ColumnDefinition) Hash() uint64 {
+	if c == nil {
+		return cache.NewHash(FragmentType_ColumnDefinition, nil)
+	}
+	return cache.NewHash(FragmentType_ColumnDefinition, c.Name, c.Type, c.Size, c.Precision, c.Scale, c.NotNull, c.Unique, c.Default)
+}
+
+// Compile transforms the ColumnDefinition into its SQL representation.
+func (c *ColumnDefinition) Compile(layout *Template) (compiled string, err error) {
+	if z, ok := layout.Read(c); ok {
+		return z, nil
+	}
+
+	data := columnDefinitionT{
+		Name:    quotedIdentifier(layout, c.Name),
+		Type:    c.Type,
+		NotNull: c.NotNull,
+		Unique:  c.Unique,
+	}
+	if tpl, ok := layout.ColumnTypes[c.Type]; ok {
+		data.Type = trimString(layout.MustCompile(tpl, c))
+	}
+	if c.Default != nil {
+		if data.Default, err = c.Default.Compile(layout); err != nil {
+			return "", err
+		}
+	}
+
+	compiled = trimString(layout.MustCompile(layout.ColumnDefinitionLayout, data))
+
+	layout.Write(c, compiled)
+	return
+}
+
+// PrimaryKeyConstraint represents a PRIMARY KEY table constraint.
+type PrimaryKeyConstraint struct {
+	Columns []string
+}
+
+var _ = Fragment(&PrimaryKeyConstraint{})
+
+// Hash returns a unique identifier for the struct.
+func (pk *PrimaryKeyConstraint) Hash() uint64 {
+	if pk == nil {
+		return cache.NewHash(FragmentType_PrimaryKeyConstraint, nil)
+	}
+	return cache.NewHash(FragmentType_PrimaryKeyConstraint, strings.Join(pk.Columns, "\x00"))
+}
+
+// Compile transforms the PrimaryKeyConstraint into its SQL representation.
+func (pk *PrimaryKeyConstraint) Compile(layout *Template) (compiled string, err error) {
+	if z, ok := layout.Read(pk); ok {
+		return z, nil
+	}
+
+	data := constraintT{
+		Columns: quotedIdentifiers(layout, pk.Columns),
+	}
+	compiled = trimString(layout.MustCompile(layout.PrimaryKeyLayout, data))
+
+	layout.Write(pk, compiled)
+	return
+}
+
+// UniqueConstraint represents a UNIQUE table constraint.
+type UniqueConstraint struct {
+	Name    string
+	Columns []string
+}
+
+var _ = Fragment(&UniqueConstraint{})
+
+// Hash returns a unique identifier for the struct.
+func (u *UniqueConstraint) Hash() uint64 {
+	if u == nil {
+		return cache.NewHash(FragmentType_UniqueConstraint, nil)
+	}
+	return cache.NewHash(FragmentType_UniqueConstraint, u.Name, strings.Join(u.Columns, "\x00"))
+}
+
+// Compile transforms the UniqueConstraint into its SQL representation.
+func (u *UniqueConstraint) Compile(layout *Template) (compiled string, err error) {
+	if z, ok := layout.Read(u); ok {
+		return z, nil
+	}
+
+	data := constraintT{
+		Columns: quotedIdentifiers(layout, u.Columns),
+	}
+	if u.Name != "" {
+		data.Name = quotedIdentifier(layout, u.Name)
+	}
+	compiled = trimString(layout.MustCompile(layout.UniqueLayout, data))
+
+	layout.Write(u, compiled)
+	return
+}
+
+// ForeignKeyConstraint represents a FOREIGN KEY table constraint.
+type ForeignKeyConstraint struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	OnUpdate          string
+	OnDelete          string
+}
+
+var _ = Fragment(&ForeignKeyConstraint{})
+
+// Hash returns a unique identifier for the struct.
+func (fk *ForeignKeyConstraint) Hash() uint64 {
+	if fk == nil {
+		return cache.NewHash(FragmentType_ForeignKeyConstraint, nil)
+	}
+	return cache.NewHash(
+		FragmentType_ForeignKeyConstraint,
+		fk.Name,
+		strings.Join(fk.Columns, "\x00"),
+		fk.ReferencedTable,
+		strings.Join(fk.ReferencedColumns, "\x00"),
+		fk.OnUpdate,
+		fk.OnDelete,
+	)
+}
+
+// Compile transforms the ForeignKeyConstraint into its SQL representation.
+func (fk *ForeignKeyConstraint) Compile(layout *Template) (compiled string, err error) {
+	if z, ok := layout.Read(fk); ok {
+		return z, nil
+	}
+
+	data := constraintT{
+		Columns:           quotedIdentifiers(layout, fk.Columns),
+		ReferencedTable:   quotedTableName(layout, fk.ReferencedTable),
+		ReferencedColumns: quotedIdentifiers(layout, fk.ReferencedColumns),
+		OnUpdate:          strings.ToUpper(fk.OnUpdate),
+		OnDelete:          strings.ToUpper(fk.OnDelete),
+	}
+	if fk.Name != "" {
+		data.Name = quotedIdentifier(layout, fk.Name)
+	}
+	compiled = trimString(layout.MustCompile(layout.ForeignKeyLayout, data))
+
+	layout.Write(fk, compiled)
+	return
+}
+
+// TableDefinition represents the columns and constraints of a CREATE TABLE
+// statement.
+type TableDefinition struct {
+	Columns     []Fragment
+	Constraints []Fragment
+}
+
+var _ = Fragment(&TableDefinition{})
+
+// Hash returns a unique identifier for the struct.
+func (t *TableDefinition) Hash() uint64 {
+	if t == nil {
+		return cache.NewHash(FragmentType_TableDefinition, nil)
+	}
+	h := cache.InitHash(FragmentType_TableDefinition)
+	for i := range t.Columns {
+		h = cache.AddToHash(h, t.Columns[i])
+	}
+	for i := range t.Constraints {
+		h = cache.AddToHash(h, t.Constraints[i])
+	}
+	return h
+}
+
+// Compile transforms the TableDefinition into its SQL representation.
+// Constraints that compile into an empty string, because the database does
+// not support them, are left out.
+func (t *TableDefinition) Compile(layout *Template) (compiled string, err error) {
+	if z, ok := layout.Read(t); ok {
+		return z, nil
+	}
+
+	out := make([]string, 0, len(t.Columns)+len(t.Constraints))
+	for _, frag := range append(append([]Fragment{}, t.Columns...), t.Constraints...) {
+		s, err := frag.Compile(layout)
+		if err != nil {
+			return "", err
+		}
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	compiled = strings.Join(out, layout.IdentifierSeparator)
+
+	layout.Write(t, compiled)
+	return
+}
+
+// Alteration represents a single change of an ALTER TABLE statement.
+type Alteration struct {
+	Type       AlterationType
+	Table      string
+	Column     *ColumnDefinition
+	Constraint Fragment
+	Name       string
+	NewName    string
+}
+
+var _ = Fragment(&Alteration{})
+
+// Hash returns a unique identifier for the struct.
+func (a *Alteration) Hash() uint64 {
+	if a == nil {
+		return cache.NewHash(FragmentType_Alteration, nil)
+	}
+	return cache.NewHash(FragmentType_Alteration, uint8(a.Type), a.Table, a.Column, a.Constraint, a.Name, a.NewName)
+}
+
+// Compile transforms the Alteration into its SQL representation.
+func (a *Alteration) Compile(layout *Template) (compiled string, err error) {
+	if z, ok := layout.Read(a); ok {
+		return z, nil
+	}
+
+	data := alterationT{
+		Table:      quotedTableName(layout, a.Table),
+		RawTable:   a.Table,
+		RawName:    a.Name,
+		RawNewName: a.NewName,
+	}
+	if a.Name != "" {
+		data.Name = quotedIdentifier(layout, a.Name)
+	}
+	if a.NewName != "" {
+		data.NewName = quotedIdentifier(layout, a.NewName)
+	}
+	if a.Column != nil {
+		if data.Column, err = a.Column.Compile(layout); err != nil {
+			return "", err
+		}
+	}
+	if a.Constraint != nil {
+		if data.Constraint, err = a.Constraint.Compile(layout); err != nil {
+			return "", err
+		}
+	}
+
+	var tpl string
+	switch a.Type {
+	case AddColumn:
+		tpl = layout.AddColumnLayout
+	case DropColumn:
+		tpl = layout.DropColumnLayout
+	case RenameColumn:
+		tpl = layout.RenameColumnLayout
+	case RenameTable:
+		tpl = layout.RenameTableLayout
+	case AddConstraint:
+		tpl = layout.AddConstraintLayout
+	case DropConstraint:
+		tpl = layout.DropConstraintLayout
+	default:
+		return "", errUnknownTemplateType
+	}
+
+	compiled = trimString(layout.MustCompile(tpl, data))
+
+	layout.Write(a, compiled)
+	return
+}
+
+func quotedIdentifier(layout *Template, name string) string {
+	return layout.MustCompile(layout.IdentifierQuote, Raw{Value: trimString(name)})
+}
+
+func quotedIdentifiers(layout *Template, names []string) string {
+	out := make([]string, len(names))
+	for i := range names {
+		out[i] = quotedIdentifier(layout, names[i])
+	}
+	return strings.Join(out, layout.IdentifierSeparator)
+}
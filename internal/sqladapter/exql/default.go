@@ -146,9 +146,43 @@ const (
   `
 
 	defaultDropTableLayout = `
-    DROP TABLE {{.Table | compile}}
+    DROP TABLE {{if .IfExists}}IF EXISTS {{end}}{{.Table | compile}}
   `
 
+	defaultTrueKeyword  = `TRUE`
+	defaultFalseKeyword = `FALSE`
+
+	defaultCreateTableLayout = `
+    CREATE TABLE {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Table | compile}} (
+      {{.Definition | compile}}
+    )
+  `
+
+	defaultAlterTableLayout = `
+    {{.Definition | compile}}
+  `
+
+	defaultCreateIndexLayout = `
+    CREATE {{if .Unique}}UNIQUE {{end}}INDEX {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Name | compile}}
+      ON {{.Table | compile}} ({{.Columns | compile}})
+  `
+
+	defaultDropIndexLayout = `
+    DROP INDEX {{if .IfExists}}IF EXISTS {{end}}{{.Name | compile}}
+  `
+
+	defaultColumnDefinitionLayout = `{{.Name}} {{.Type}}{{if .NotNull}} NOT NULL{{end}}{{if .Default}} DEFAULT {{.Default}}{{end}}{{if .Unique}} UNIQUE{{end}}`
+	defaultPrimaryKeyLayout       = `PRIMARY KEY ({{.Columns}})`
+	defaultUniqueLayout           = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}UNIQUE ({{.Columns}})`
+	defaultForeignKeyLayout       = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}FOREIGN KEY ({{.Columns}}) REFERENCES {{.ReferencedTable}} ({{.ReferencedColumns}}){{if .OnUpdate}} ON UPDATE {{.OnUpdate}}{{end}}{{if .OnDelete}} ON DELETE {{.OnDelete}}{{end}}`
+
+	defaultAddColumnLayout      = `ALTER TABLE {{.Table}} ADD COLUMN {{.Column}}`
+	defaultDropColumnLayout     = `ALTER TABLE {{.Table}} DROP COLUMN {{.Name}}`
+	defaultRenameColumnLayout   = `ALTER TABLE {{.Table}} RENAME COLUMN {{.Name}} TO {{.NewName}}`
+	defaultRenameTableLayout    = `ALTER TABLE {{.Table}} RENAME TO {{.NewName}}`
+	defaultAddConstraintLayout  = `ALTER TABLE {{.Table}} ADD {{.Constraint}}`
+	defaultDropConstraintLayout = `ALTER TABLE {{.Table}} DROP CONSTRAINT {{.Name}}`
+
 	defaultGroupByLayout = `
     {{if .GroupColumns}}
       GROUP BY {{.GroupColumns}}
@@ -156,6 +190,23 @@ const (
   `
 )
 
+var defaultColumnTypes = map[string]string{
+	"serial":    `SERIAL`,
+	"bigserial": `BIGSERIAL`,
+	"integer":   `INTEGER`,
+	"bigint":    `BIGINT`,
+	"string":    `VARCHAR({{if .Size}}{{.Size}}{{else}}255{{end}})`,
+	"text":      `TEXT`,
+	"boolean":   `BOOLEAN`,
+	"float":     `DOUBLE PRECISION`,
+	"decimal":   `NUMERIC{{if .Precision}}({{.Precision}}, {{.Scale}}){{end}}`,
+	"timestamp": `TIMESTAMP`,
+	"date":      `DATE`,
+	"binary":    `BLOB`,
+	"json":      `TEXT`,
+	"uuid":      `CHAR(36)`,
+}
+
 var defaultTemplate = &Template{
 	AndKeyword:          defaultAndKeyword,
 	AscKeyword:          defaultAscKeyword,
@@ -188,5 +239,23 @@ var defaultTemplate = &Template{
 	ValueSeparator:      defaultValueSeparator,
 	WhereLayout:         defaultWhereLayout,
 
+	TrueKeyword:            defaultTrueKeyword,
+	FalseKeyword:           defaultFalseKeyword,
+	CreateTableLayout:      defaultCreateTableLayout,
+	AlterTableLayout:       defaultAlterTableLayout,
+	CreateIndexLayout:      defaultCreateIndexLayout,
+	DropIndexLayout:        defaultDropIndexLayout,
+	ColumnDefinitionLayout: defaultColumnDefinitionLayout,
+	PrimaryKeyLayout:       defaultPrimaryKeyLayout,
+	UniqueLayout:           defaultUniqueLayout,
+	ForeignKeyLayout:       defaultForeignKeyLayout,
+	AddColumnLayout:        defaultAddColumnLayout,
+	DropColumnLayout:       defaultDropColumnLayout,
+	RenameColumnLayout:     defaultRenameColumnLayout,
+	RenameTableLayout:      defaultRenameTableLayout,
+	AddConstraintLayout:    defaultAddConstraintLayout,
+	DropConstraintLayout:   defaultDropConstraintLayout,
+	ColumnTypes:            defaultColumnTypes,
+
 	Cache: cache.NewCache(),
 }
@@ -0,0 +1,61 @@
+package exql
+
+import (
+	"strings"
+
+	"github.com/upper/db/v4/internal/cache"
+)
+
+type onConflictT struct {
+	Columns string
+	Update  string
+	Where   string
+}
+
+// OnConflict represents the clause of an INSERT statement that updates the
+// rows that conflict with the inserted ones, or skips the inserted rows if
+// there are no columns to update.
+type OnConflict struct {
+	Columns []string
+	Update  []string
+
+	// Where restricts the rows that are updated, databases that don't support
+	// conditional upserts leave it out.
+	Where Fragment
+}
+
+var _ = Fragment(&OnConflict{})
+
+// Hash returns a unique identifier for the struct.
+func (oc *OnConflict) Hash() uint64 {
+	if oc == nil {
+		return cache.NewHash(FragmentType_OnConflict, nil)
+	}
+	return cache.NewHash(FragmentType_OnConflict, strings.Join(oc.Columns, "\x00"), strings.Join(oc.Update, "\x00"), oc.Where)
+}
+
+// Compile transforms the OnConflict into its SQL representation.
+func (oc *OnConflict) Compile(layout *Template) (compiled string, err error) {
+	if z, ok := layout.Read(oc); ok {
+		return z, nil
+	}
+
+	assignments := make([]string, len(oc.Update))
+	for i := range oc.Update {
+		assignments[i] = trimString(layout.MustCompile(layout.ConflictUpdateLayout, quotedIdentifier(layout, oc.Update[i])))
+	}
+
+	data := onConflictT{
+		Columns: quotedIdentifiers(layout, oc.Columns),
+		Update:  strings.Join(assignments, layout.IdentifierSeparator),
+	}
+	if oc.Where != nil {
+		if data.Where, err = oc.Where.Compile(layout); err != nil {
+			return "", err
+		}
+	}
+	compiled = trimString(layout.MustCompile(layout.OnConflictLayout, data))
+
+	layout.Write(oc, compiled)
+	return
+}
@@ -24,6 +24,12 @@ type Statement struct {
 	Joins        Fragment
 	Where        Fragment
 	Returning    Fragment
+	OnConflict   Fragment
+	Name         Fragment
+	Definition   Fragment
+	Unique       bool
+	IfExists     bool
+	IfNotExists  bool
 
 	Limit
 	Offset
@@ -59,6 +65,12 @@ func (s *Statement) Hash() uint64 {
 		s.Joins,
 		s.Where,
 		s.Returning,
+		s.OnConflict,
+		s.Name,
+		s.Definition,
+		s.Unique,
+		s.IfExists,
+		s.IfNotExists,
 		s.Limit,
 		s.Offset,
 		s.SQL,
@@ -94,6 +106,14 @@ func (s *Statement) template(layout *Template) (string, error) {
 		return layout.UpdateLayout, nil
 	case Insert:
 		return layout.InsertLayout, nil
+	case CreateTable:
+		return layout.CreateTableLayout, nil
+	case AlterTable:
+		return layout.AlterTableLayout, nil
+	case CreateIndex:
+		return layout.CreateIndexLayout, nil
+	case DropIndex:
+		return layout.DropIndexLayout, nil
 	default:
 		return "", errUnknownTemplateType
 	}
@@ -25,6 +25,10 @@ const (
 	Select
 	Update
 	Delete
+	CreateTable
+	AlterTable
+	CreateIndex
+	DropIndex
 
 	SQL
 )
@@ -81,6 +85,32 @@ type Template struct {
 	ValueSeparator      string
 	WhereLayout         string
 
+	TrueKeyword            string
+	FalseKeyword           string
+	CreateTableLayout      string
+	AlterTableLayout       string
+	CreateIndexLayout      string
+	DropIndexLayout        string
+	ColumnDefinitionLayout string
+	PrimaryKeyLayout       string
+	UniqueLayout           string
+	ForeignKeyLayout       string
+	AddColumnLayout        string
+	DropColumnLayout       string
+	RenameColumnLayout     string
+	RenameTableLayout      string
+	AddConstraintLayout    string
+	DropConstraintLayout   string
+
+	// OnConflictLayout and ConflictUpdateLayout compile the clause of upserts,
+	// see OnConflict. They're empty if the database doesn't support upserts.
+	OnConflictLayout     string
+	ConflictUpdateLayout string
+
+	// ColumnTypes maps portable column types into native ones, values are
+	// templates that receive a *ColumnDefinition.
+	ColumnTypes map[string]string
+
 	ComparisonOperator map[adapter.ComparisonOperator]string
 
 	templateMutex sync.RWMutex
@@ -32,4 +32,11 @@ const (
 	FragmentType_ValueGroups
 	FragmentType_Values
 	FragmentType_Where
+	FragmentType_ColumnDefinition
+	FragmentType_PrimaryKeyConstraint
+	FragmentType_UniqueConstraint
+	FragmentType_ForeignKeyConstraint
+	FragmentType_TableDefinition
+	FragmentType_Alteration
+	FragmentType_OnConflict
 )
@@ -0,0 +1,69 @@
+package sqladapter
+
+import (
+	"reflect"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/records"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// optionGenerate is the struct tag option for fields that are set with a key
+// of the named generator when a record is created, like
+// `db:"id,generate=uuidv7"`.
+const optionGenerate = "generate"
+
+// generateRecordKeys sets the empty primary key fields of a record with keys
+// of the store, if it's a db.KeyGenerator, and the empty fields tagged with
+// the generate option with keys of the named generator. It returns true if
+// any key was generated.
+func generateRecordKeys(store db.Store, record db.Record) (bool, error) {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return false, nil
+	}
+
+	generated := false
+
+	if generator, ok := store.(db.KeyGenerator); ok {
+		pKeys, err := store.Session().(Session).PrimaryKeys(store.Name())
+		if err != nil {
+			return false, err
+		}
+		fields := mapperOf(store.Session()).FieldsByName(v, pKeys)
+		for i := range fields {
+			if !fields[i].IsValid() || !fields[i].IsZero() {
+				continue
+			}
+			key, err := generator.GenerateKey(pKeys[i])
+			if err != nil {
+				return false, err
+			}
+			if err := records.SetKeyField(fields[i], key); err != nil {
+				return false, err
+			}
+			generated = true
+		}
+	}
+
+	for _, fi := range mapperOf(store.Session()).TypeMap(v.Elem().Type()).Index {
+		name, ok := fi.Options[optionGenerate]
+		if !ok {
+			continue
+		}
+		field := reflectx.FieldByIndexes(v.Elem(), fi.Index)
+		if !field.IsZero() {
+			continue
+		}
+		key, err := db.GenerateKey(name)
+		if err != nil {
+			return false, err
+		}
+		if err := records.SetKeyField(field, key); err != nil {
+			return false, err
+		}
+		generated = true
+	}
+
+	return generated, nil
+}
@@ -0,0 +1,56 @@
+package sqladapter
+
+import (
+	"reflect"
+
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// optionOptimisticLock is the struct tag option for the integer field that
+// holds the version of a record, like `db:"version,optlock"`.
+const optionOptimisticLock = "optlock"
+
+// versionField is the field of a record used for optimistic locking.
+type versionField struct {
+	column string
+	field  reflect.Value
+}
+
+// versionFieldOf returns the version field of the given record, or nil if
+// the record has none.
+func versionFieldOf(mapper *reflectx.Mapper, record interface{}) *versionField {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	for _, fi := range mapper.TypeMap(v.Elem().Type()).Index {
+		if _, ok := fi.Options[optionOptimisticLock]; !ok {
+			continue
+		}
+		field := reflectx.FieldByIndexes(v.Elem(), fi.Index)
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return &versionField{column: fi.Name, field: field}
+		}
+	}
+	return nil
+}
+
+func (f *versionField) value() interface{} {
+	return f.field.Interface()
+}
+
+// increment adds one to the version and returns a function that restores the
+// previous value.
+func (f *versionField) increment() (restore func()) {
+	prev := reflect.ValueOf(f.value())
+	if f.field.Kind() >= reflect.Uint && f.field.Kind() <= reflect.Uint64 {
+		f.field.SetUint(f.field.Uint() + 1)
+	} else {
+		f.field.SetInt(f.field.Int() + 1)
+	}
+	return func() {
+		f.field.Set(prev)
+	}
+}
@@ -0,0 +1,35 @@
+package sqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+func TestVersionField(t *testing.T) {
+	item := struct {
+		ID      int64  `db:"id"`
+		Version uint32 `db:"lock_version,optlock"`
+	}{ID: 1, Version: 7}
+
+	version := versionFieldOf(sqlbuilder.Mapper, &item)
+	if assert.NotNil(t, version) {
+		assert.Equal(t, "lock_version", version.column)
+		assert.Equal(t, uint32(7), version.value())
+
+		restore := version.increment()
+		assert.Equal(t, uint32(8), item.Version)
+
+		restore()
+		assert.Equal(t, uint32(7), item.Version)
+	}
+
+	assert.Nil(t, versionFieldOf(sqlbuilder.Mapper, &struct {
+		Version string `db:"version,optlock"`
+	}{}))
+	assert.Nil(t, versionFieldOf(sqlbuilder.Mapper, &struct {
+		Version int `db:"version"`
+	}{}))
+	assert.Nil(t, versionFieldOf(sqlbuilder.Mapper, item))
+}
@@ -43,7 +43,7 @@ func recordPrimaryKeyFieldValues(store db.Store, record db.Record) ([]string, []
 		return nil, nil, err
 	}
 
-	fields := sqlbuilder.Mapper.FieldsByName(reflect.ValueOf(record), pKeys)
+	fields := mapperOf(sess).FieldsByName(reflect.ValueOf(record), pKeys)
 
 	values := make([]interface{}, 0, len(fields))
 	for i := range fields {
@@ -56,8 +56,49 @@ func recordPrimaryKeyFieldValues(store db.Store, record db.Record) ([]string, []
 }
 
 func recordCreate(store db.Store, record db.Record) error {
+	if err := recordBeforeCreate(store, record); err != nil {
+		return err
+	}
+
+	if creator, ok := store.(db.StoreCreator); ok {
+		if err := creator.Create(record); err != nil {
+			return err
+		}
+	} else if err := recordInsert(store, record); err != nil {
+		return err
+	}
+
+	return recordAfterCreate(store, record)
+}
+
+// recordInsert inserts the row of a record and reloads the record.
+func recordInsert(store db.Store, record db.Record) error {
+	id, err := recordKey(store, record)
+	if err != nil {
+		return err
+	}
+	if id == nil {
+		return store.InsertReturning(record)
+	}
+	// The key is known (like generated keys), the row is read back with it
+	// instead of relying on the key returned by the database.
+	if _, err := store.Insert(record); err != nil {
+		return err
+	}
+	return store.Find(id).WithDeleted().Unscoped().One(record)
+}
+
+func recordBeforeCreate(store db.Store, record db.Record) error {
 	sess := store.Session()
 
+	if err := checkEncrypted(sess, store.Name(), record); err != nil {
+		return err
+	}
+
+	if _, err := generateRecordKeys(store, record); err != nil {
+		return err
+	}
+
 	if validator, ok := record.(db.Validator); ok {
 		if err := validator.Validate(); err != nil {
 			return err
@@ -70,18 +111,13 @@ func recordCreate(store db.Store, record db.Record) error {
 		}
 	}
 
-	if creator, ok := store.(db.StoreCreator); ok {
-		if err := creator.Create(record); err != nil {
-			return err
-		}
-	} else {
-		if err := store.InsertReturning(record); err != nil {
-			return err
-		}
-	}
+	setRecordTimestamps(mapperOf(sess), record, sess.Now(), true)
+	return nil
+}
 
+func recordAfterCreate(store db.Store, record db.Record) error {
 	if hook, ok := record.(db.AfterCreateHook); ok {
-		if err := hook.AfterCreate(sess); err != nil {
+		if err := hook.AfterCreate(store.Session()); err != nil {
 			return err
 		}
 	}
@@ -91,6 +127,45 @@ func recordCreate(store db.Store, record db.Record) error {
 func recordUpdate(store db.Store, record db.Record) error {
 	sess := store.Session()
 
+	if err := recordBeforeUpdate(store, record); err != nil {
+		return err
+	}
+
+	if updater, ok := store.(db.StoreUpdater); ok {
+		if err := updater.Update(record); err != nil {
+			return err
+		}
+	} else if changes := db.Changed(record); changes != nil {
+		// Tracked records only send the columns that changed.
+		if len(changes) > 0 {
+			values, err := changedValues(store, record, changes)
+			if err != nil {
+				return err
+			}
+			if err := recordUpdateColumns(store, record, values, versionFieldOf(mapperOf(sess), record)); err != nil {
+				return err
+			}
+		}
+	} else if version := versionFieldOf(mapperOf(sess), record); version != nil {
+		if err := recordUpdateColumns(store, record, record, version); err != nil {
+			return err
+		}
+	} else {
+		if err := record.Store(sess).UpdateReturning(record); err != nil {
+			return err
+		}
+	}
+
+	return recordAfterUpdate(store, record)
+}
+
+func recordBeforeUpdate(store db.Store, record db.Record) error {
+	sess := store.Session()
+
+	if err := checkEncrypted(sess, store.Name(), record); err != nil {
+		return err
+	}
+
 	if validator, ok := record.(db.Validator); ok {
 		if err := validator.Validate(); err != nil {
 			return err
@@ -103,20 +178,88 @@ func recordUpdate(store db.Store, record db.Record) error {
 		}
 	}
 
-	if updater, ok := store.(db.StoreUpdater); ok {
-		if err := updater.Update(record); err != nil {
+	setRecordTimestamps(mapperOf(sess), record, sess.Now(), false)
+	return nil
+}
+
+func recordAfterUpdate(store db.Store, record db.Record) error {
+	if hook, ok := record.(db.AfterUpdateHook); ok {
+		if err := hook.AfterUpdate(store.Session()); err != nil {
 			return err
 		}
-	} else {
-		if err := record.Store(sess).UpdateReturning(record); err != nil {
-			return err
+	}
+	return nil
+}
+
+// changedValues returns the new values of the changed columns of a tracked
+// record, encoded by the codecs of their fields. The values of encrypted
+// columns are left to be encrypted by the SQL builder.
+func changedValues(store db.Store, record db.Record, changes map[string]db.Change) (map[string]interface{}, error) {
+	sess := store.Session()
+	encrypted := codecsOf(sess).EncryptedColumns(store.Name())
+
+	values := make(map[string]interface{}, len(changes))
+	for column := range changes {
+		if _, ok := encrypted[column]; ok {
+			values[column] = changes[column].To
+			continue
+		}
+		codec, err := sqlbuilder.FieldCodec(mapperOf(sess), codecsOf(sess), record, column)
+		if err != nil {
+			return nil, err
+		}
+		if codec == nil {
+			values[column] = changes[column].To
+			continue
+		}
+		if values[column], err = codec.Encode(changes[column].To); err != nil {
+			return nil, err
 		}
 	}
+	return values, nil
+}
 
-	if hook, ok := record.(db.AfterUpdateHook); ok {
-		if err := hook.AfterUpdate(sess); err != nil {
+// recordUpdateColumns updates the row of a record with the given values (a
+// map or the record itself) and reloads the record. If version is not nil the
+// row is only updated if its version matches the version of the record, the
+// version is incremented on success and db.ErrStaleRecord is returned if no
+// row was updated.
+func recordUpdateColumns(store db.Store, record db.Record, values interface{}, version *versionField) error {
+	conds, err := recordID(store, record)
+	if err != nil {
+		return err
+	}
+
+	query := store.Session().SQL().
+		Update(store.Name()).
+		Where(conds)
+
+	restore := func() {}
+	if version != nil {
+		query = query.And(db.Cond{version.column: version.value()})
+		restore = version.increment()
+		if columns, ok := values.(map[string]interface{}); ok {
+			columns[version.column] = version.value()
+		}
+	}
+
+	res, err := query.Set(values).Exec()
+	if err != nil {
+		restore()
+		return err
+	}
+
+	if version != nil {
+		affected, err := res.RowsAffected()
+		if err != nil {
+			restore()
 			return err
 		}
+		if affected == 0 {
+			restore()
+			return db.ErrStaleRecord
+		}
 	}
-	return nil
+
+	return store.Find(conds).WithDeleted().Unscoped().One(record)
 }
@@ -0,0 +1,529 @@
+package sqladapter
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// preloadBatchSize is the maximum number of keys used within a single
+// IN (...) condition when loading related records.
+const preloadBatchSize = 500
+
+var relationsCache sync.Map
+
+var hasRelationsType = reflect.TypeOf((*db.HasRelations)(nil)).Elem()
+
+// relationKinds maps the names used in "rel" struct tags to relation kinds.
+var relationKinds = map[string]db.RelationKind{
+	"belongs_to":   db.RelationBelongsTo,
+	"has_one":      db.RelationHasOne,
+	"has_many":     db.RelationHasMany,
+	"many_to_many": db.RelationManyToMany,
+}
+
+// preloadTree represents the relations to preload, nested relations are
+// children of their parent relation.
+type preloadTree map[string]preloadTree
+
+func newPreloadTree(paths []string) preloadTree {
+	tree := preloadTree{}
+	for _, path := range paths {
+		node := tree
+		for _, name := range strings.Split(path, ".") {
+			if node[name] == nil {
+				node[name] = preloadTree{}
+			}
+			node = node[name]
+		}
+	}
+	return tree
+}
+
+func (t preloadTree) names() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseRelationTag parses "rel" struct tags like
+// `rel:"many_to_many,through=post_tags,fk=post_id,references=tag_id"`.
+func parseRelationTag(tag string) (db.Relation, error) {
+	parts := strings.Split(tag, ",")
+
+	kind, ok := relationKinds[strings.TrimSpace(parts[0])]
+	if !ok {
+		return db.Relation{}, fmt.Errorf("%w: unknown kind %q", db.ErrInvalidRelation, parts[0])
+	}
+
+	rel := db.Relation{Kind: kind}
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return db.Relation{}, fmt.Errorf("%w: expecting key=value, got %q", db.ErrInvalidRelation, part)
+		}
+		value := strings.TrimSpace(kv[1])
+		switch strings.TrimSpace(kv[0]) {
+		case "fk":
+			rel.ForeignKey = value
+		case "key":
+			rel.Key = value
+		case "through":
+			rel.Through = value
+		case "references":
+			rel.References = value
+		case "table":
+			rel.Table = value
+		default:
+			return db.Relation{}, fmt.Errorf("%w: unknown option %q", db.ErrInvalidRelation, kv[0])
+		}
+	}
+	return rel, nil
+}
+
+// relationsOf returns the relations declared by the given struct type, both
+// with a Relations method and with "rel" struct tags.
+func relationsOf(t reflect.Type) (db.Relations, error) {
+	if cached, ok := relationsCache.Load(t); ok {
+		return cached.(db.Relations), nil
+	}
+
+	relations := db.Relations{}
+	if reflect.PtrTo(t).Implements(hasRelationsType) {
+		for name, rel := range reflect.New(t).Interface().(db.HasRelations).Relations() {
+			relations[name] = rel
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("rel")
+		if !ok {
+			continue
+		}
+		rel, err := parseRelationTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		relations[field.Name] = rel
+	}
+
+	relationsCache.Store(t, relations)
+	return relations, nil
+}
+
+// relationField describes the struct field that holds related records.
+type relationField struct {
+	index []int
+
+	// many is true for slices, ptr is true when the field (or the elements of
+	// the slice) are pointers.
+	many bool
+	ptr  bool
+
+	// elem is the struct type of the related records.
+	elem reflect.Type
+}
+
+func newRelationField(t reflect.Type, name string) (*relationField, error) {
+	field, ok := t.FieldByName(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no field %q", db.ErrUnknownRelation, t.Name(), name)
+	}
+
+	rf := &relationField{index: field.Index}
+
+	ft := field.Type
+	if ft.Kind() == reflect.Slice {
+		rf.many = true
+		ft = ft.Elem()
+	}
+	if ft.Kind() == reflect.Ptr {
+		rf.ptr = true
+		ft = ft.Elem()
+	}
+	if ft.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: field %s.%s must be a struct, a pointer to struct or a slice of them", db.ErrInvalidRelation, t.Name(), name)
+	}
+	rf.elem = ft
+
+	return rf, nil
+}
+
+// set stores the given related records (pointers to structs) on the field of
+// item.
+func (rf *relationField) set(item reflect.Value, related []reflect.Value) {
+	field := item.FieldByIndex(rf.index)
+
+	if !rf.many {
+		if len(related) == 0 {
+			field.Set(reflect.Zero(field.Type()))
+			return
+		}
+		if rf.ptr {
+			field.Set(related[0])
+		} else {
+			field.Set(related[0].Elem())
+		}
+		return
+	}
+
+	slice := reflect.MakeSlice(field.Type(), 0, len(related))
+	for _, v := range related {
+		if rf.ptr {
+			slice = reflect.Append(slice, v)
+		} else {
+			slice = reflect.Append(slice, v.Elem())
+		}
+	}
+	field.Set(slice)
+}
+
+// keyOf normalizes key values so keys of different types that represent the
+// same value, like int64(1) and uint(1), can be matched.
+func keyOf(v interface{}) string {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			v = value
+		}
+	}
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	if b, ok := rv.Interface().([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", rv.Interface())
+}
+
+// columnValue returns the value of the field of item mapped to the given
+// column.
+func columnValue(mapper *reflectx.Mapper, item reflect.Value, column string) (interface{}, error) {
+	// QL exposes its implicit primary key as "id()" and maps it to "id".
+	name := strings.TrimSuffix(column, "()")
+
+	fi, ok := mapper.TypeMap(item.Type()).Names[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no field mapped to column %q", db.ErrInvalidRelation, item.Type().Name(), name)
+	}
+	return reflectx.FieldByIndexesReadOnly(item, fi.Index).Interface(), nil
+}
+
+// columnValues returns the distinct non-nil values of the given column.
+func columnValues(mapper *reflectx.Mapper, items []reflect.Value, column string) ([]interface{}, error) {
+	seen := map[string]bool{}
+	values := []interface{}{}
+	for _, item := range items {
+		v, err := columnValue(mapper, item, column)
+		if err != nil {
+			return nil, err
+		}
+		k := keyOf(v)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// primaryKey returns the single column primary key of the table, or "id" if
+// the table has no primary key or has a composite one.
+func primaryKey(sess db.Session, table string) string {
+	if col, ok := sess.Collection(table).(interface {
+		PrimaryKeys() ([]string, error)
+	}); ok {
+		if pk, err := col.PrimaryKeys(); err == nil && len(pk) == 1 {
+			return pk[0]
+		}
+	}
+	return "id"
+}
+
+// tableOf returns the name of the table records of the given type are stored
+// in.
+func tableOf(sess db.Session, t reflect.Type, rel db.Relation) (string, error) {
+	if rel.Table != "" {
+		return rel.Table, nil
+	}
+	if record, ok := reflect.New(t).Interface().(db.Record); ok {
+		return record.Store(sess).Name(), nil
+	}
+	return "", fmt.Errorf("%w: %s is not a db.Record and the relation has no table", db.ErrInvalidRelation, t.Name())
+}
+
+// findIn fetches the records of table whose column matches any of the given
+// values, in batches.
+func findIn(sess db.Session, table string, elem reflect.Type, column string, values []interface{}) ([]reflect.Value, error) {
+	items := []reflect.Value{}
+	for len(values) > 0 {
+		n := len(values)
+		if n > preloadBatchSize {
+			n = preloadBatchSize
+		}
+		batch := reflect.New(reflect.SliceOf(reflect.PtrTo(elem)))
+		err := sess.Collection(table).Find(db.Cond{column: db.In(values[:n]...)}).All(batch.Interface())
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < batch.Elem().Len(); i++ {
+			items = append(items, batch.Elem().Index(i))
+		}
+		values = values[n:]
+	}
+	return items, nil
+}
+
+// findPairs fetches the pairs of keys of a join table.
+func findPairs(sess db.Session, table string, foreignKey string, references string, values []interface{}) ([][2]interface{}, error) {
+	pairs := [][2]interface{}{}
+	for len(values) > 0 {
+		n := len(values)
+		if n > preloadBatchSize {
+			n = preloadBatchSize
+		}
+		iter := sess.SQL().
+			Select(foreignKey, references).
+			From(table).
+			Where(db.Cond{foreignKey: db.In(values[:n]...)}).
+			Iterator()
+		for iter.Next() {
+			var pair [2]interface{}
+			if err := iter.Scan(&pair[0], &pair[1]); err != nil {
+				iter.Close()
+				return nil, err
+			}
+			pairs = append(pairs, pair)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+		values = values[n:]
+	}
+	return pairs, nil
+}
+
+// preload loads the relations in tree for the given items, which are
+// addressable values of type t stored in table.
+func preload(sess db.Session, table string, t reflect.Type, items []reflect.Value, tree preloadTree) error {
+	if len(items) == 0 || len(tree) == 0 {
+		return nil
+	}
+
+	relations, err := relationsOf(t)
+	if err != nil {
+		return err
+	}
+
+	mapper := mapperOf(sess)
+	for _, name := range tree.names() {
+		rel, ok := relations[name]
+		if !ok {
+			return fmt.Errorf("%w: %s has no relation %q", db.ErrUnknownRelation, t.Name(), name)
+		}
+		if rel.ForeignKey == "" {
+			return fmt.Errorf("%w: %s.%s has no foreign key", db.ErrInvalidRelation, t.Name(), name)
+		}
+
+		field, err := newRelationField(t, name)
+		if err != nil {
+			return err
+		}
+
+		relatedTable, err := tableOf(sess, field.elem, rel)
+		if err != nil {
+			return err
+		}
+
+		// related holds the loaded records, matchesOf returns the ones that
+		// belong to the given item.
+		var related []reflect.Value
+		var matchesOf func(item reflect.Value) []reflect.Value
+
+		switch rel.Kind {
+		case db.RelationBelongsTo:
+			key := rel.Key
+			if key == "" {
+				key = primaryKey(sess, relatedTable)
+			}
+			values, err := columnValues(mapper, items, rel.ForeignKey)
+			if err != nil {
+				return err
+			}
+			if related, err = findIn(sess, relatedTable, field.elem, key, values); err != nil {
+				return err
+			}
+			index := map[string][]reflect.Value{}
+			for _, v := range related {
+				k, err := columnValue(mapper, v.Elem(), key)
+				if err != nil {
+					return err
+				}
+				index[keyOf(k)] = []reflect.Value{v}
+			}
+			matchesOf = func(item reflect.Value) []reflect.Value {
+				fk, _ := columnValue(mapper, item, rel.ForeignKey)
+				return index[keyOf(fk)]
+			}
+
+		case db.RelationHasOne, db.RelationHasMany:
+			key := rel.Key
+			if key == "" {
+				key = primaryKey(sess, table)
+			}
+			values, err := columnValues(mapper, items, key)
+			if err != nil {
+				return err
+			}
+			if related, err = findIn(sess, relatedTable, field.elem, rel.ForeignKey, values); err != nil {
+				return err
+			}
+			index := map[string][]reflect.Value{}
+			for _, v := range related {
+				fk, err := columnValue(mapper, v.Elem(), rel.ForeignKey)
+				if err != nil {
+					return err
+				}
+				index[keyOf(fk)] = append(index[keyOf(fk)], v)
+			}
+			matchesOf = func(item reflect.Value) []reflect.Value {
+				k, _ := columnValue(mapper, item, key)
+				return index[keyOf(k)]
+			}
+
+		case db.RelationManyToMany:
+			if rel.Through == "" || rel.References == "" {
+				return fmt.Errorf("%w: %s.%s needs a join table and a references column", db.ErrInvalidRelation, t.Name(), name)
+			}
+			ownerKey, relatedKey := rel.Key, rel.Key
+			if ownerKey == "" {
+				ownerKey = primaryKey(sess, table)
+				relatedKey = primaryKey(sess, relatedTable)
+			}
+			values, err := columnValues(mapper, items, ownerKey)
+			if err != nil {
+				return err
+			}
+			pairs, err := findPairs(sess, rel.Through, rel.ForeignKey, rel.References, values)
+			if err != nil {
+				return err
+			}
+			relatedValues := []interface{}{}
+			seen := map[string]bool{}
+			for _, pair := range pairs {
+				if k := keyOf(pair[1]); !seen[k] {
+					seen[k] = true
+					relatedValues = append(relatedValues, pair[1])
+				}
+			}
+			if related, err = findIn(sess, relatedTable, field.elem, relatedKey, relatedValues); err != nil {
+				return err
+			}
+			byKey := map[string]reflect.Value{}
+			for _, v := range related {
+				k, err := columnValue(mapper, v.Elem(), relatedKey)
+				if err != nil {
+					return err
+				}
+				byKey[keyOf(k)] = v
+			}
+			index := map[string][]reflect.Value{}
+			for _, pair := range pairs {
+				if v, ok := byKey[keyOf(pair[1])]; ok {
+					index[keyOf(pair[0])] = append(index[keyOf(pair[0])], v)
+				}
+			}
+			matchesOf = func(item reflect.Value) []reflect.Value {
+				k, _ := columnValue(mapper, item, ownerKey)
+				return index[keyOf(k)]
+			}
+
+		default:
+			return fmt.Errorf("%w: %s.%s has an unknown kind", db.ErrInvalidRelation, t.Name(), name)
+		}
+
+		// Nested relations are loaded before related records are assigned, as
+		// fields that are not pointers hold copies of them.
+		if len(tree[name]) > 0 {
+			nested := make([]reflect.Value, 0, len(related))
+			for _, v := range related {
+				nested = append(nested, v.Elem())
+			}
+			if err := preload(sess, relatedTable, field.elem, nested, tree[name]); err != nil {
+				return err
+			}
+		}
+
+		for _, item := range items {
+			field.set(item, matchesOf(item))
+		}
+	}
+
+	return nil
+}
+
+// preloadRelations loads the relations set with Preload into dst, which is
+// either a pointer to a struct or a pointer to a slice of structs.
+func (r *Result) preloadRelations(dst interface{}) error {
+	res, err := r.fastForward()
+	if err != nil {
+		return err
+	}
+	if len(res.preload) == 0 {
+		return nil
+	}
+
+	dstv := reflect.ValueOf(dst)
+	if dstv.Kind() != reflect.Ptr || dstv.IsNil() {
+		return db.ErrUnsupportedDestination
+	}
+	dstv = dstv.Elem()
+
+	var items []reflect.Value
+	var t reflect.Type
+
+	switch dstv.Kind() {
+	case reflect.Struct:
+		items, t = []reflect.Value{dstv}, dstv.Type()
+	case reflect.Slice:
+		t = dstv.Type().Elem()
+		isPtr := t.Kind() == reflect.Ptr
+		if isPtr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return db.ErrUnsupportedDestination
+		}
+		items = make([]reflect.Value, 0, dstv.Len())
+		for i := 0; i < dstv.Len(); i++ {
+			item := dstv.Index(i)
+			if isPtr {
+				if item.IsNil() {
+					continue
+				}
+				item = item.Elem()
+			}
+			items = append(items, item)
+		}
+	default:
+		return db.ErrUnsupportedDestination
+	}
+
+	return preload(r.Session(), res.table, t, items, newPreloadTree(res.preload))
+}
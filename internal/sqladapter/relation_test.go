@@ -0,0 +1,108 @@
+package sqladapter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+type relationTestPost struct {
+	ID       int64              `db:"id"`
+	AuthorID int64              `db:"author_id"`
+	Author   *relationTestUser  `db:"-" rel:"belongs_to,fk=author_id"`
+	Tags     []relationTestUser `db:"-" rel:"many_to_many,through=post_tags,fk=post_id,references=tag_id"`
+}
+
+type relationTestUser struct {
+	ID    int64              `db:"id"`
+	Posts []relationTestPost `db:"-"`
+}
+
+func (*relationTestUser) Relations() db.Relations {
+	return db.Relations{
+		"Posts": db.HasMany("author_id"),
+	}
+}
+
+func TestParseRelationTag(t *testing.T) {
+	rel, err := parseRelationTag("belongs_to,fk=author_id")
+	assert.NoError(t, err)
+	assert.Equal(t, db.BelongsTo("author_id"), rel)
+
+	rel, err = parseRelationTag("many_to_many, through=post_tags, fk=post_id, references=tag_id, key=code")
+	assert.NoError(t, err)
+	assert.Equal(t, db.Relation{
+		Kind:       db.RelationManyToMany,
+		ForeignKey: "post_id",
+		Key:        "code",
+		Through:    "post_tags",
+		References: "tag_id",
+	}, rel)
+
+	_, err = parseRelationTag("has_few,fk=post_id")
+	assert.True(t, errors.Is(err, db.ErrInvalidRelation))
+
+	_, err = parseRelationTag("has_many,fk")
+	assert.True(t, errors.Is(err, db.ErrInvalidRelation))
+
+	_, err = parseRelationTag("has_many,foreign=post_id")
+	assert.True(t, errors.Is(err, db.ErrInvalidRelation))
+}
+
+func TestRelationsOf(t *testing.T) {
+	relations, err := relationsOf(reflect.TypeOf(relationTestPost{}))
+	assert.NoError(t, err)
+	assert.Equal(t, db.Relations{
+		"Author": db.BelongsTo("author_id"),
+		"Tags":   db.ManyToMany("post_tags", "post_id", "tag_id"),
+	}, relations)
+
+	relations, err = relationsOf(reflect.TypeOf(relationTestUser{}))
+	assert.NoError(t, err)
+	assert.Equal(t, db.Relations{
+		"Posts": db.HasMany("author_id"),
+	}, relations)
+}
+
+func TestPreloadTree(t *testing.T) {
+	tree := newPreloadTree([]string{"Posts.Tags", "Posts.Author", "Avatar"})
+	assert.Equal(t, preloadTree{
+		"Avatar": preloadTree{},
+		"Posts": preloadTree{
+			"Author": preloadTree{},
+			"Tags":   preloadTree{},
+		},
+	}, tree)
+	assert.Equal(t, []string{"Avatar", "Posts"}, tree.names())
+}
+
+func TestRelationFieldSet(t *testing.T) {
+	post := reflect.ValueOf(&relationTestPost{}).Elem()
+
+	field, err := newRelationField(post.Type(), "Author")
+	assert.NoError(t, err)
+	assert.False(t, field.many)
+	assert.True(t, field.ptr)
+
+	author := &relationTestUser{ID: 3}
+	field.set(post, []reflect.Value{reflect.ValueOf(author)})
+	assert.Equal(t, author, post.Interface().(relationTestPost).Author)
+
+	field, err = newRelationField(post.Type(), "Tags")
+	assert.NoError(t, err)
+	assert.True(t, field.many)
+	assert.False(t, field.ptr)
+
+	field.set(post, nil)
+	assert.NotNil(t, post.Interface().(relationTestPost).Tags)
+	assert.Len(t, post.Interface().(relationTestPost).Tags, 0)
+
+	_, err = newRelationField(post.Type(), "Comments")
+	assert.True(t, errors.Is(err, db.ErrUnknownRelation))
+
+	_, err = newRelationField(post.Type(), "AuthorID")
+	assert.True(t, errors.Is(err, db.ErrInvalidRelation))
+}
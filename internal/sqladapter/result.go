@@ -31,7 +31,7 @@ import (
 )
 
 type Result struct {
-	builder db.SQL
+	sess db.Session
 
 	err atomic.Value
 
@@ -59,6 +59,20 @@ type result struct {
 	orderBy []interface{}
 	groupBy []interface{}
 	conds   [][]interface{}
+
+	preload []string
+
+	// strict is true if rows must be scanned strictly, see db.WithStrictScan.
+	strict bool
+
+	// softDelete is the soft delete column of the collection, as declared by
+	// its store or by the records the result set is fetched into.
+	softDelete string
+	deleted    softDeleteMode
+
+	// scoped is true once the default scope was applied or if it must not be
+	// applied.
+	scoped bool
 }
 
 func filter(conds []interface{}) []interface{} {
@@ -67,9 +81,9 @@ func filter(conds []interface{}) []interface{} {
 
 // NewResult creates and Results a new Result set on the given table, this set
 // is limited by the given exql.Where conditions.
-func NewResult(builder db.SQL, table string, conds []interface{}) *Result {
+func NewResult(sess db.Session, table string, conds []interface{}) *Result {
 	r := &Result{
-		builder: builder,
+		sess: sess,
 	}
 	return r.from(table).where(conds)
 }
@@ -79,15 +93,21 @@ func (r *Result) frame(fn func(*result) error) *Result {
 }
 
 func (r *Result) SQL() db.SQL {
+	return r.Session().SQL()
+}
+
+func (r *Result) Session() db.Session {
 	if r.prev == nil {
-		return r.builder
+		return r.sess
 	}
-	return r.prev.SQL()
+	return r.prev.Session()
 }
 
 func (r *Result) from(table string) *Result {
+	column := declarationsOf(r.Session()).SoftDeleteColumn(table)
 	return r.frame(func(res *result) error {
 		res.table = table
+		res.softDelete = column
 		return nil
 	})
 }
@@ -203,6 +223,15 @@ func (r *Result) OrderBy(fields ...interface{}) db.Result {
 	})
 }
 
+// Preload sets the relations to be loaded along with the records fetched by
+// One and All.
+func (r *Result) Preload(relations ...string) db.Result {
+	return r.frame(func(res *result) error {
+		res.preload = append(res.preload, relations...)
+		return nil
+	})
+}
+
 // Select determines which fields to return.
 func (r *Result) Select(fields ...interface{}) db.Result {
 	return r.frame(func(res *result) error {
@@ -222,19 +251,39 @@ func (r *Result) String() string {
 
 // All dumps all Results into a pointer to an slice of structs or maps.
 func (r *Result) All(dst interface{}) error {
-	query, err := r.Paginator()
+	q, err := r.withDestination(dst)
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+	query, err := q.Paginator()
 	if err != nil {
 		r.setErr(err)
 		return err
 	}
 	err = query.Iterator().All(dst)
+	if err == nil {
+		err = r.preloadRelations(dst)
+	}
+	if err == nil {
+		snapshotRecords(r.Session(), dst)
+	}
 	r.setErr(err)
 	return err
 }
 
+// Pluck fetches the values of the given column into a pointer to slice.
+func (r *Result) Pluck(column string, dst interface{}) error {
+	return r.Select(column).All(dst)
+}
+
 // One fetches only one Result from the set.
 func (r *Result) One(dst interface{}) error {
-	one := r.Limit(1).(*Result)
+	one, err := r.Limit(1).(*Result).withDestination(dst)
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
 	query, err := one.Paginator()
 	if err != nil {
 		r.setErr(err)
@@ -242,6 +291,12 @@ func (r *Result) One(dst interface{}) error {
 	}
 
 	err = query.Iterator().One(dst)
+	if err == nil {
+		err = r.preloadRelations(dst)
+	}
+	if err == nil {
+		snapshotRecords(r.Session(), dst)
+	}
 	r.setErr(err)
 	return err
 }
@@ -252,7 +307,12 @@ func (r *Result) Next(dst interface{}) bool {
 	defer r.iterMu.Unlock()
 
 	if r.iter == nil {
-		query, err := r.Paginator()
+		q, err := r.withDestination(dst)
+		if err != nil {
+			r.setErr(err)
+			return false
+		}
+		query, err := q.Paginator()
 		if err != nil {
 			r.setErr(err)
 			return false
@@ -272,8 +332,26 @@ func (r *Result) Next(dst interface{}) bool {
 	return false
 }
 
-// Delete deletes all matching items from the collection.
+// Delete deletes all matching items from the collection, items of
+// soft-deletable collections are marked as deleted instead.
 func (r *Result) Delete() error {
+	res, err := r.fastForward()
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	if column := res.softDelete; column != "" {
+		query, err := r.buildUpdate(map[string]interface{}{column: r.Session().Now()})
+		if err != nil {
+			r.setErr(err)
+			return err
+		}
+		_, err = query.And(db.Cond{column: db.IsNull()}).Exec()
+		r.setErr(err)
+		return err
+	}
+
 	query, err := r.buildDelete()
 	if err != nil {
 		r.setErr(err)
@@ -298,7 +376,12 @@ func (r *Result) Close() error {
 // Update updates matching items from the collection with values of the given
 // map or struct.
 func (r *Result) Update(values interface{}) error {
-	query, err := r.buildUpdate(values)
+	if err := r.checkEncrypted(values); err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	query, err := r.buildUpdate(withUpdateTimestamps(mapperOf(r.Session()), values, r.Session().Now()))
 	if err != nil {
 		r.setErr(err)
 		return err
@@ -402,7 +485,12 @@ func (r *Result) Paginator() (db.Paginator, error) {
 		return nil, err
 	}
 
-	sel := r.SQL().Select(res.fields...).
+	sess := r.Session()
+	if res.strict {
+		sess = strictSession(sess)
+	}
+
+	sel := sess.SQL().Select(res.fields...).
 		From(res.table).
 		Limit(res.limit).
 		Offset(res.offset).
@@ -412,6 +500,9 @@ func (r *Result) Paginator() (db.Paginator, error) {
 	for i := range res.conds {
 		sel = sel.And(filter(res.conds[i])...)
 	}
+	if conds := res.softDeleteConds(); len(conds) > 0 {
+		sel = sel.And(conds...)
+	}
 
 	pag := sel.Paginate(res.pageSize).
 		Page(res.pageNumber).
@@ -444,6 +535,9 @@ func (r *Result) buildDelete() (db.Deleter, error) {
 	for i := range res.conds {
 		del = del.And(filter(res.conds[i])...)
 	}
+	if conds := res.softDeleteConds(); len(conds) > 0 {
+		del = del.And(conds...)
+	}
 
 	return del, nil
 }
@@ -465,6 +559,9 @@ func (r *Result) buildUpdate(values interface{}) (db.Updater, error) {
 	for i := range res.conds {
 		upd = upd.And(filter(res.conds[i])...)
 	}
+	if conds := res.softDeleteConds(); len(conds) > 0 {
+		upd = upd.And(conds...)
+	}
 
 	return upd, nil
 }
@@ -486,6 +583,9 @@ func (r *Result) buildCount() (db.Selector, error) {
 	for i := range res.conds {
 		sel = sel.And(filter(res.conds[i])...)
 	}
+	if conds := res.softDeleteConds(); len(conds) > 0 {
+		sel = sel.And(conds...)
+	}
 
 	return sel, nil
 }
@@ -513,6 +613,13 @@ func (r *Result) fastForward() (*result, error) {
 	if err != nil {
 		return nil, err
 	}
+	scoped, ok, err := r.withDefaultScope(ff.(*result))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return scoped.fastForward()
+	}
 	return ff.(*result), nil
 }
 
@@ -0,0 +1,45 @@
+package sqladapter
+
+import (
+	db "github.com/upper/db/v4"
+)
+
+// WithStrictScan returns a copy of the session that fails to scan results
+// whose columns don't match the fields of the destination struct, see
+// db.WithStrictScan.
+func (sess *sessionWithContext) WithStrictScan() db.Session {
+	return &sessionWithContext{
+		session:      sess.session,
+		ctx:          sess.ctx,
+		tenancy:      sess.tenancy,
+		fieldMapper:  sess.fieldMapper,
+		mapper:       sess.mapper,
+		strictScan:   true,
+		codecs:       sess.codecs,
+		declarations: sess.declarations,
+	}
+}
+
+// StrictScan returns true if the session scans results strictly.
+func (sess *sessionWithContext) StrictScan() bool {
+	return sess.strictScan
+}
+
+// Strict makes the result set fail to scan rows whose columns don't match the
+// fields of the destination struct.
+func (r *Result) Strict() db.Result {
+	return r.frame(func(res *result) error {
+		res.strict = true
+		return nil
+	})
+}
+
+// strictSession returns a copy of sess that scans results strictly.
+func strictSession(sess db.Session) db.Session {
+	if s, ok := sess.(interface {
+		WithStrictScan() db.Session
+	}); ok {
+		return s.WithStrictScan()
+	}
+	return sess
+}
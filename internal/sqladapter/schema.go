@@ -0,0 +1,186 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sqladapter
+
+import (
+	"sort"
+
+	db "github.com/upper/db/v4"
+)
+
+// ColumnDescription represents a row of a query that describes the columns of
+// a table. Adapters can use it as destination when querying their catalogs.
+type ColumnDescription struct {
+	Name          string  `db:"column_name"`
+	Type          string  `db:"data_type"`
+	Nullable      bool    `db:"is_nullable"`
+	Default       *string `db:"column_default"`
+	AutoIncrement bool    `db:"is_autoincrement"`
+}
+
+// IndexColumn represents a column that belongs to an index, rows are expected
+// to be sorted by index name and position within the index.
+type IndexColumn struct {
+	Index   string `db:"index_name"`
+	Column  string `db:"column_name"`
+	Unique  bool   `db:"is_unique"`
+	Primary bool   `db:"is_primary"`
+}
+
+// ConstraintColumn represents a column that belongs to a constraint, rows are
+// expected to be sorted by constraint name and position within the
+// constraint.
+type ConstraintColumn struct {
+	Constraint string `db:"constraint_name"`
+	Column     string `db:"column_name"`
+}
+
+// ForeignKeyColumn represents a column that belongs to a foreign key, rows are
+// expected to be sorted by constraint name and position within the
+// constraint.
+type ForeignKeyColumn struct {
+	Constraint       string `db:"constraint_name"`
+	Column           string `db:"column_name"`
+	ReferencedTable  string `db:"referenced_table"`
+	ReferencedColumn string `db:"referenced_column"`
+	OnUpdate         string `db:"on_update"`
+	OnDelete         string `db:"on_delete"`
+}
+
+// Columns converts column descriptions into db.Column values.
+func Columns(rows []ColumnDescription) []*db.Column {
+	columns := make([]*db.Column, 0, len(rows))
+	for i := range rows {
+		columns = append(columns, &db.Column{
+			Name:          rows[i].Name,
+			Type:          rows[i].Type,
+			Nullable:      rows[i].Nullable,
+			Default:       rows[i].Default,
+			AutoIncrement: rows[i].AutoIncrement,
+		})
+	}
+	return columns
+}
+
+// Indexes groups index columns by index name.
+func Indexes(rows []IndexColumn) []*db.Index {
+	indexes := []*db.Index{}
+	for i := range rows {
+		n := len(indexes)
+		if n == 0 || indexes[n-1].Name != rows[i].Index {
+			indexes = append(indexes, &db.Index{
+				Name:    rows[i].Index,
+				Unique:  rows[i].Unique,
+				Primary: rows[i].Primary,
+			})
+			n++
+		}
+		indexes[n-1].Columns = append(indexes[n-1].Columns, rows[i].Column)
+	}
+	return indexes
+}
+
+// UniqueConstraints groups constraint columns by constraint name.
+func UniqueConstraints(rows []ConstraintColumn) []*db.UniqueConstraint {
+	uniques := []*db.UniqueConstraint{}
+	for i := range rows {
+		n := len(uniques)
+		if n == 0 || uniques[n-1].Name != rows[i].Constraint {
+			uniques = append(uniques, &db.UniqueConstraint{
+				Name: rows[i].Constraint,
+			})
+			n++
+		}
+		uniques[n-1].Columns = append(uniques[n-1].Columns, rows[i].Column)
+	}
+	return uniques
+}
+
+// ForeignKeys groups foreign key columns by constraint name.
+func ForeignKeys(rows []ForeignKeyColumn) []*db.ForeignKey {
+	fks := []*db.ForeignKey{}
+	for i := range rows {
+		n := len(fks)
+		if n == 0 || fks[n-1].Name != rows[i].Constraint {
+			fks = append(fks, &db.ForeignKey{
+				Name:            rows[i].Constraint,
+				ReferencedTable: rows[i].ReferencedTable,
+				OnUpdate:        rows[i].OnUpdate,
+				OnDelete:        rows[i].OnDelete,
+			})
+			n++
+		}
+		fks[n-1].Columns = append(fks[n-1].Columns, rows[i].Column)
+		fks[n-1].ReferencedColumns = append(fks[n-1].ReferencedColumns, rows[i].ReferencedColumn)
+	}
+	return fks
+}
+
+// schema implements db.Schema on top of the AdapterSession.
+type schema struct {
+	sess *sessionWithContext
+}
+
+func (s *schema) Tables() ([]*db.Table, error) {
+	names, err := s.sess.adapter.Collections(s.sess)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	tables := make([]*db.Table, 0, len(names))
+	for i := range names {
+		table, err := s.Table(names[i])
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+func (s *schema) Table(name string) (*db.Table, error) {
+	if err := s.sess.TableExists(name); err != nil {
+		return nil, err
+	}
+
+	table, err := s.sess.adapter.DescribeTable(s.sess, name)
+	if err != nil {
+		return nil, err
+	}
+
+	table.Name = name
+	if table.PrimaryKey == nil {
+		if table.PrimaryKey, err = s.sess.PrimaryKeys(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return table, nil
+}
+
+func (s *schema) Views() ([]*db.View, error) {
+	return s.sess.adapter.Views(s.sess)
+}
+
+var _ = db.Schema(&schema{})
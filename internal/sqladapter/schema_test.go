@@ -0,0 +1,57 @@
+package sqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaIndexes(t *testing.T) {
+	indexes := Indexes([]IndexColumn{
+		{Index: "artist_pkey", Column: "id", Unique: true, Primary: true},
+		{Index: "artist_name_idx", Column: "name"},
+		{Index: "artist_name_idx", Column: "country"},
+	})
+
+	assert.Len(t, indexes, 2)
+
+	assert.Equal(t, "artist_pkey", indexes[0].Name)
+	assert.Equal(t, []string{"id"}, indexes[0].Columns)
+	assert.True(t, indexes[0].Primary)
+	assert.True(t, indexes[0].Unique)
+
+	assert.Equal(t, "artist_name_idx", indexes[1].Name)
+	assert.Equal(t, []string{"name", "country"}, indexes[1].Columns)
+	assert.False(t, indexes[1].Primary)
+	assert.False(t, indexes[1].Unique)
+}
+
+func TestSchemaForeignKeys(t *testing.T) {
+	fks := ForeignKeys([]ForeignKeyColumn{
+		{Constraint: "fk_author", Column: "author_id", ReferencedTable: "artist", ReferencedColumn: "id", OnDelete: "CASCADE"},
+		{Constraint: "fk_pair", Column: "a", ReferencedTable: "pair", ReferencedColumn: "x"},
+		{Constraint: "fk_pair", Column: "b", ReferencedTable: "pair", ReferencedColumn: "y"},
+	})
+
+	assert.Len(t, fks, 2)
+
+	assert.Equal(t, []string{"author_id"}, fks[0].Columns)
+	assert.Equal(t, "artist", fks[0].ReferencedTable)
+	assert.Equal(t, "CASCADE", fks[0].OnDelete)
+
+	assert.Equal(t, []string{"a", "b"}, fks[1].Columns)
+	assert.Equal(t, []string{"x", "y"}, fks[1].ReferencedColumns)
+}
+
+func TestSchemaColumns(t *testing.T) {
+	def := "0"
+	columns := Columns([]ColumnDescription{
+		{Name: "id", Type: "integer", AutoIncrement: true},
+		{Name: "total", Type: "integer", Nullable: true, Default: &def},
+	})
+
+	assert.Len(t, columns, 2)
+	assert.True(t, columns[0].AutoIncrement)
+	assert.False(t, columns[0].Nullable)
+	assert.Equal(t, "0", *columns[1].Default)
+}
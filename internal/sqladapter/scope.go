@@ -0,0 +1,60 @@
+package sqladapter
+
+import (
+	"fmt"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/immutable"
+)
+
+// tableName returns the table of the result set.
+func (r *Result) tableName() (string, error) {
+	ff, err := immutable.FastForward(r)
+	if err != nil {
+		return "", err
+	}
+	return ff.(*result).table, nil
+}
+
+// Scope applies a scope declared by the store of the collection, see
+// db.HasScopes.
+func (r *Result) Scope(name string, args ...interface{}) db.Result {
+	table, err := r.tableName()
+	if err != nil {
+		return r.frame(func(*result) error {
+			return err
+		})
+	}
+	scope, ok := declarationsOf(r.Session()).Scope(table, name)
+	if !ok {
+		return r.frame(func(*result) error {
+			return fmt.Errorf("%w: %q", db.ErrUnknownScope, name)
+		})
+	}
+	return scope(r, args...)
+}
+
+// Unscoped prevents the default scope from being applied.
+func (r *Result) Unscoped() db.Result {
+	return r.frame(func(res *result) error {
+		res.scoped = true
+		return nil
+	})
+}
+
+// withDefaultScope returns the result set with the default scope of the table
+// applied, unless Unscoped was called.
+func (r *Result) withDefaultScope(res *result) (*Result, bool, error) {
+	if res.scoped {
+		return r, false, nil
+	}
+	scope, ok := declarationsOf(r.Session()).Scope(res.table, db.DefaultScope)
+	if !ok {
+		return r, false, nil
+	}
+	scoped, ok := scope(r.Unscoped()).(*Result)
+	if !ok {
+		return nil, false, fmt.Errorf("upper: the default scope of %q must return a result of the same collection", res.table)
+	}
+	return scoped, true, nil
+}
@@ -16,9 +16,11 @@ import (
 
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/cache"
+	"github.com/upper/db/v4/internal/reflectx"
 	"github.com/upper/db/v4/internal/sqladapter/compat"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
 	"github.com/upper/db/v4/internal/sqlbuilder"
+	"github.com/upper/db/v4/internal/stores"
 )
 
 var (
@@ -67,6 +69,12 @@ type valueConverter interface {
 	}
 }
 
+// rowsReturner is implemented by adapters of databases that support RETURNING
+// on statements that insert many rows.
+type rowsReturner interface {
+	SupportsReturning() bool
+}
+
 // errorConverter converts an error value from the underlying driver into
 // something different.
 type errorConverter interface {
@@ -93,6 +101,13 @@ type AdapterSession interface {
 
 	// PrimaryKeys returns all primary keys on the table.
 	PrimaryKeys(sess Session, name string) ([]string, error)
+
+	// DescribeTable returns the columns, indexes and constraints of the
+	// table.
+	DescribeTable(sess Session, name string) (*db.Table, error)
+
+	// Views returns the definitions of all views on the database.
+	Views(sess Session) ([]*db.View, error)
 }
 
 // Session satisfies db.Session.
@@ -106,6 +121,9 @@ type Session interface {
 	// database.
 	Collections() ([]db.Collection, error)
 
+	// Schema returns an interface to inspect the structure of the database.
+	Schema() db.Schema
+
 	// Name returns the name of the database.
 	Name() string
 
@@ -140,6 +158,14 @@ type Session interface {
 
 	Delete(db.Record) error
 
+	Reload(db.Record) error
+
+	Exists(db.Record) (bool, error)
+
+	SaveAll(...db.Record) error
+
+	DeleteAll(...db.Record) error
+
 	// WaitForConnection attempts to run the given connection function a fixed
 	// number of times before failing.
 	WaitForConnection(func() error) error
@@ -244,6 +270,9 @@ type session struct {
 	cachedStatements  *cache.Cache
 	cachedCollections *cache.Cache
 
+	clockMu sync.RWMutex // guards clock
+	clock   func() time.Time
+
 	template *exql.Template
 }
 
@@ -251,6 +280,126 @@ type sessionWithContext struct {
 	*session
 
 	ctx context.Context
+
+	tenancy *tenancy
+
+	fieldMapper *db.FieldMapper
+	mapper      *reflectx.Mapper
+
+	strictScan bool
+
+	codecs *db.Codecs
+
+	declarations *stores.Declarations
+}
+
+// WithFieldMapper returns a copy of the session that maps struct fields to
+// columns as defined by the given field mapper.
+func (sess *sessionWithContext) WithFieldMapper(fieldMapper *db.FieldMapper) db.Session {
+	return &sessionWithContext{
+		session:      sess.session,
+		ctx:          sess.ctx,
+		tenancy:      sess.tenancy,
+		fieldMapper:  fieldMapper,
+		mapper:       sqlbuilder.NewMapper(*fieldMapper),
+		strictScan:   sess.strictScan,
+		codecs:       sess.codecs,
+		declarations: sess.declarations,
+	}
+}
+
+// WithCodecs returns a copy of the session that encodes and decodes values with
+// the given type codecs.
+func (sess *sessionWithContext) WithCodecs(codecs *db.Codecs) db.Session {
+	return &sessionWithContext{
+		session:      sess.session,
+		ctx:          sess.ctx,
+		tenancy:      sess.tenancy,
+		fieldMapper:  sess.fieldMapper,
+		mapper:       sess.mapper,
+		strictScan:   sess.strictScan,
+		codecs:       codecs,
+		declarations: sess.declarations,
+	}
+}
+
+// SetClock sets the clock of the session, the clock of db.DefaultSettings is
+// used if fn is nil.
+func (sess *sessionWithContext) SetClock(fn func() time.Time) {
+	sess.clockMu.Lock()
+	sess.clock = fn
+	sess.clockMu.Unlock()
+}
+
+// Now returns the current time according to the clock of the session.
+func (sess *sessionWithContext) Now() time.Time {
+	sess.clockMu.RLock()
+	clock := sess.clock
+	sess.clockMu.RUnlock()
+	if clock == nil {
+		return sess.Settings.Now()
+	}
+	return clock()
+}
+
+// Codecs returns the type codecs of the session, or nil if it has none.
+func (sess *sessionWithContext) Codecs() *db.Codecs {
+	return sess.codecs
+}
+
+// FieldMapper returns the field mapper of the session, or nil if the session
+// uses the default one.
+func (sess *sessionWithContext) FieldMapper() *db.FieldMapper {
+	return sess.fieldMapper
+}
+
+// mapperOf returns the struct mapper of the given session.
+func mapperOf(sess db.Session) *reflectx.Mapper {
+	return sqlbuilder.MapperOf(sess)
+}
+
+// WithStores returns a copy of the session that applies the declarations of
+// the given stores to their collections, see db.WithStores.
+func (sess *sessionWithContext) WithStores(stores ...db.Store) db.Session {
+	return &sessionWithContext{
+		session:      sess.session,
+		ctx:          sess.ctx,
+		tenancy:      sess.tenancy,
+		fieldMapper:  sess.fieldMapper,
+		mapper:       sess.mapper,
+		strictScan:   sess.strictScan,
+		codecs:       sess.codecs,
+		declarations: sess.declarations.Declare(stores...),
+	}
+}
+
+// StoreDeclarations returns the declarations of the stores of the session.
+func (sess *sessionWithContext) StoreDeclarations() *stores.Declarations {
+	return sess.declarations
+}
+
+// declarationsOf returns the declarations of the stores of the given session.
+func declarationsOf(sess db.Session) *stores.Declarations {
+	if s, ok := sess.(interface {
+		StoreDeclarations() *stores.Declarations
+	}); ok {
+		return s.StoreDeclarations()
+	}
+	return nil
+}
+
+// codecsOf returns the type codecs of the given session, or nil if it has
+// none.
+func codecsOf(sess db.Session) *db.Codecs {
+	return sqlbuilder.CodecsOf(sess)
+}
+
+// Mapper returns the struct mapper of the session.
+func (sess *sessionWithContext) Mapper() *reflectx.Mapper {
+	if sess.mapper == nil {
+		return sqlbuilder.Mapper
+	}
+	return sess.mapper
 }
 
 func (sess *sessionWithContext) WithContext(ctx context.Context) db.Session {
@@ -258,8 +407,14 @@ func (sess *sessionWithContext) WithContext(ctx context.Context) db.Session {
 		panic("nil context")
 	}
 	newSess := &sessionWithContext{
-		session: sess.session,
-		ctx:     ctx,
+		session:      sess.session,
+		ctx:          ctx,
+		tenancy:      sess.tenancy,
+		fieldMapper:  sess.fieldMapper,
+		mapper:       sess.mapper,
+		strictScan:   sess.strictScan,
+		codecs:       sess.codecs,
+		declarations: sess.declarations,
 	}
 	return newSess
 }
@@ -303,6 +458,10 @@ func (sess *sessionWithContext) PrimaryKeys(tableName string) ([]string, error)
 	return pk, nil
 }
 
+func (sess *sessionWithContext) Schema() db.Schema {
+	return &schema{sess: sess}
+}
+
 func (sess *sessionWithContext) TableExists(name string) error {
 	return sess.adapter.TableExists(sess, name)
 }
@@ -378,7 +537,51 @@ func (sess *sessionWithContext) Get(record db.Record, id interface{}) error {
 	if getter, ok := store.(db.StoreGetter); ok {
 		return getter.Get(record, id)
 	}
-	return store.Find(id).One(record)
+	return store.Find(id).Unscoped().One(record)
+}
+
+func (sess *sessionWithContext) Reload(record db.Record) error {
+	if record == nil {
+		return db.ErrNilRecord
+	}
+
+	if reflect.TypeOf(record).Kind() != reflect.Ptr {
+		return db.ErrExpectingPointerToStruct
+	}
+
+	store := record.Store(sess)
+	conds, err := recordID(store, record)
+	if err != nil {
+		return err
+	}
+	return store.Find(conds).WithDeleted().Unscoped().One(record)
+}
+
+func (sess *sessionWithContext) Exists(record db.Record) (bool, error) {
+	if record == nil {
+		return false, db.ErrNilRecord
+	}
+
+	store := record.Store(sess)
+
+	conds, err := recordID(store, record)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordIDIsZero) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	res := store.Find(conds).Unscoped()
+	if softDeletable, ok := record.(db.SoftDeletable); ok {
+		res = res.And(db.Cond{softDeletable.SoftDeleteColumn(): db.IsNull()})
+	}
+
+	count, err := res.Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
 func (sess *sessionWithContext) Save(record db.Record) error {
@@ -392,7 +595,11 @@ func (sess *sessionWithContext) Save(record db.Record) error {
 
 	store := record.Store(sess)
 	if saver, ok := store.(db.StoreSaver); ok {
-		return saver.Save(record)
+		if err := saver.Save(record); err != nil {
+			return err
+		}
+		snapshotRecord(sess, record)
+		return nil
 	}
 
 	id := db.Cond{}
@@ -408,13 +615,21 @@ func (sess *sessionWithContext) Save(record db.Record) error {
 
 	if len(id) > 0 && len(id) == len(values) {
 		// check if record exists before updating it
-		exists, _ := store.Find(id).Count()
+		exists, _ := store.Find(id).WithDeleted().Unscoped().Count()
 		if exists > 0 {
-			return recordUpdate(store, record)
+			if err := recordUpdate(store, record); err != nil {
+				return err
+			}
+			snapshotRecord(sess, record)
+			return nil
 		}
 	}
 
-	return recordCreate(store, record)
+	if err := recordCreate(store, record); err != nil {
+		return err
+	}
+	snapshotRecord(sess, record)
+	return nil
 }
 
 func (sess *sessionWithContext) Delete(record db.Record) error {
@@ -443,7 +658,14 @@ func (sess *sessionWithContext) Delete(record db.Record) error {
 		if err != nil {
 			return err
 		}
-		if err := store.Find(conds).Delete(); err != nil {
+		if softDeletable, ok := record.(db.SoftDeletable); ok {
+			column := softDeletable.SoftDeleteColumn()
+			now := sess.Now()
+			if err := store.Find(conds).Unscoped().Update(map[string]interface{}{column: now}); err != nil {
+				return err
+			}
+			setSoftDeleteField(sess.Mapper(), record, column, now)
+		} else if err := store.Find(conds).Unscoped().Delete(); err != nil {
 			return err
 		}
 	}
@@ -606,6 +828,12 @@ func (sess *sessionWithContext) NewClone(adapter AdapterSession, checkConn bool)
 	newSess.name = sess.name
 	newSess.sqlDB = sess.sqlDB
 	newSess.cachedPKs = sess.cachedPKs
+	newSess.tenancy = sess.tenancy
+	newSess.fieldMapper = sess.fieldMapper
+	newSess.mapper = sess.mapper
+	newSess.strictScan = sess.strictScan
+	newSess.codecs = sess.codecs
+	newSess.declarations = sess.declarations
 
 	if checkConn {
 		if err := newSess.Ping(); err != nil {
@@ -619,6 +847,10 @@ func (sess *sessionWithContext) NewClone(adapter AdapterSession, checkConn bool)
 	// New transaction should inherit parent settings
 	copySettings(sess, newSess)
 
+	sess.clockMu.RLock()
+	newSess.clock = sess.clock
+	sess.clockMu.RUnlock()
+
 	return newSess, nil
 }
 
@@ -700,6 +932,17 @@ func (sess *sessionWithContext) StatementPrepare(ctx context.Context, stmt *exql
 		})
 	}(time.Now())
 
+	if sess.tenancy != nil {
+		var filtered *exql.Statement
+		if filtered, _, err = sess.tenantStatement(ctx, stmt, nil); err != nil {
+			return nil, err
+		}
+		if filtered != stmt {
+			err = fmt.Errorf("%w: prepared statement on a protected table", db.ErrTenantIsolation)
+			return nil, err
+		}
+	}
+
 	query, _, err = sess.compileStatement(stmt, nil)
 	if err != nil {
 		return nil, err
@@ -766,6 +1009,10 @@ func (sess *sessionWithContext) StatementExec(ctx context.Context, stmt *exql.St
 		queryLog(&status)
 	}(time.Now())
 
+	if stmt, args, err = sess.tenantStatement(ctx, stmt, args); err != nil {
+		return nil, err
+	}
+
 	if execer, ok := sess.adapter.(statementExecer); ok {
 		query, args, err = sess.compileStatement(stmt, args)
 		if err != nil {
@@ -819,6 +1066,10 @@ func (sess *sessionWithContext) StatementQuery(ctx context.Context, stmt *exql.S
 		queryLog(&status)
 	}(time.Now())
 
+	if stmt, args, err = sess.tenantStatement(ctx, stmt, args); err != nil {
+		return nil, err
+	}
+
 	tx := sess.Transaction()
 
 	if sess.Settings.PreparedStatementCacheEnabled() && tx == nil {
@@ -864,6 +1115,10 @@ func (sess *sessionWithContext) StatementQueryRow(ctx context.Context, stmt *exq
 		queryLog(&status)
 	}(time.Now())
 
+	if stmt, args, err = sess.tenantStatement(ctx, stmt, args); err != nil {
+		return nil, err
+	}
+
 	tx := sess.Transaction()
 
 	if sess.Settings.PreparedStatementCacheEnabled() && tx == nil {
@@ -0,0 +1,148 @@
+package sqladapter
+
+import (
+	"reflect"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+type softDeleteMode uint8
+
+const (
+	excludeDeleted softDeleteMode = iota
+	includeDeleted
+	onlyDeleted
+)
+
+var softDeletableType = reflect.TypeOf((*db.SoftDeletable)(nil)).Elem()
+
+// softDeleteColumnOf returns the soft delete column of the records dst points
+// to, or an empty string if they're not SoftDeletable.
+func softDeleteColumnOf(dst interface{}) string {
+	t := reflect.TypeOf(dst)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return ""
+	}
+	t = t.Elem()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || !reflect.PtrTo(t).Implements(softDeletableType) {
+		return ""
+	}
+	return reflect.New(t).Interface().(db.SoftDeletable).SoftDeleteColumn()
+}
+
+// setSoftDeleteField sets the field of record mapped to column to the given
+// time, if the record has such field.
+func setSoftDeleteField(mapper *reflectx.Mapper, record interface{}, column string, t time.Time) {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	fi, ok := mapper.TypeMap(v.Elem().Type()).Names[column]
+	if !ok {
+		return
+	}
+	setTimeField(reflectx.FieldByIndexes(v.Elem(), fi.Index), t)
+}
+
+// softDeleteConds returns the conditions that restrict the result set to
+// rows that are or aren't soft deleted.
+func (res *result) softDeleteConds() []interface{} {
+	column := res.softDelete
+	if column == "" {
+		return nil
+	}
+	switch res.deleted {
+	case includeDeleted:
+		return nil
+	case onlyDeleted:
+		return []interface{}{db.Cond{column: db.IsNotNull()}}
+	}
+	return []interface{}{db.Cond{column: db.IsNull()}}
+}
+
+// withDestination returns a result set that knows about the soft delete
+// column of the records dst points to, after checking their encrypted fields.
+func (r *Result) withDestination(dst interface{}) (*Result, error) {
+	res, err := r.fastForward()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEncrypted(r.Session(), res.table, dst); err != nil {
+		return nil, err
+	}
+	column := softDeleteColumnOf(dst)
+	if column == "" {
+		return r, nil
+	}
+	return r.frame(func(res *result) error {
+		res.softDelete = column
+		return nil
+	}), nil
+}
+
+func (r *Result) softDeleteMode(mode softDeleteMode) *Result {
+	return r.frame(func(res *result) error {
+		res.deleted = mode
+		return nil
+	})
+}
+
+// WithDeleted includes soft deleted rows in the result set.
+func (r *Result) WithDeleted() db.Result {
+	return r.softDeleteMode(includeDeleted)
+}
+
+// OnlyDeleted restricts the result set to soft deleted rows.
+func (r *Result) OnlyDeleted() db.Result {
+	return r.softDeleteMode(onlyDeleted)
+}
+
+// Restore clears the soft delete column of all matching rows.
+func (r *Result) Restore() error {
+	res, err := r.fastForward()
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	column := res.softDelete
+	if column == "" {
+		r.setErr(db.ErrNotSoftDeletable)
+		return db.ErrNotSoftDeletable
+	}
+
+	return r.softDeleteMode(onlyDeleted).Update(map[string]interface{}{column: nil})
+}
+
+// HardDelete removes all matching rows from the collection, including soft
+// deleted ones.
+func (r *Result) HardDelete() error {
+	res, err := r.fastForward()
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	q := r
+	if res.deleted != onlyDeleted {
+		q = r.softDeleteMode(includeDeleted)
+	}
+
+	query, err := q.buildDelete()
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	_, err = query.Exec()
+	r.setErr(err)
+	return err
+}
@@ -0,0 +1,59 @@
+package sqladapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+type softDeleteTestItem struct {
+	ID        int64      `db:"id"`
+	DeletedAt *time.Time `db:"removed_at"`
+}
+
+func (*softDeleteTestItem) Store(sess db.Session) db.Store {
+	return nil
+}
+
+func (*softDeleteTestItem) SoftDeleteColumn() string {
+	return "removed_at"
+}
+
+func TestSoftDeleteColumnOf(t *testing.T) {
+	assert.Equal(t, "removed_at", softDeleteColumnOf(&softDeleteTestItem{}))
+	assert.Equal(t, "removed_at", softDeleteColumnOf(&[]softDeleteTestItem{}))
+	assert.Equal(t, "removed_at", softDeleteColumnOf(&[]*softDeleteTestItem{}))
+	assert.Equal(t, "", softDeleteColumnOf(&map[string]interface{}{}))
+	assert.Equal(t, "", softDeleteColumnOf(softDeleteTestItem{}))
+
+	res := &result{table: "soft_delete_items", softDelete: "removed_at"}
+	assert.Equal(t, []interface{}{db.Cond{"removed_at": db.IsNull()}}, res.softDeleteConds())
+
+	res.deleted = onlyDeleted
+	assert.Equal(t, []interface{}{db.Cond{"removed_at": db.IsNotNull()}}, res.softDeleteConds())
+
+	res.deleted = includeDeleted
+	assert.Nil(t, res.softDeleteConds())
+}
+
+func TestSetSoftDeleteField(t *testing.T) {
+	now := time.Now()
+
+	item := softDeleteTestItem{}
+	setSoftDeleteField(sqlbuilder.Mapper, &item, "removed_at", now)
+	if assert.NotNil(t, item.DeletedAt) {
+		assert.Equal(t, now, *item.DeletedAt)
+	}
+
+	var row struct {
+		DeletedAt time.Time `db:"deleted_at"`
+	}
+	setSoftDeleteField(sqlbuilder.Mapper, &row, "deleted_at", now)
+	assert.Equal(t, now, row.DeletedAt)
+
+	setSoftDeleteField(sqlbuilder.Mapper, &row, "removed_at", time.Time{})
+	assert.Equal(t, now, row.DeletedAt)
+}
@@ -0,0 +1,118 @@
+package sqladapter
+
+import (
+	"reflect"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/records"
+)
+
+// iterate calls fn with a pointer to each result of the set, itemT is the type
+// the results are fetched into.
+func (r *Result) iterate(itemT reflect.Type, fn func(item reflect.Value) error) error {
+	q, err := r.withDestination(reflect.New(itemT).Interface())
+	if err != nil {
+		return err
+	}
+	query, err := q.Paginator()
+	if err != nil {
+		return err
+	}
+
+	iter := query.Iterator()
+	defer iter.Close()
+
+	ctx := r.Session().Context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		item := reflect.New(itemT)
+		if !iter.Next(item.Interface()) {
+			return iter.Err()
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// Iterator returns an iterator over the results of the set, it's used to
+// export them, see db.ExportCSV.
+func (r *Result) Iterator() (db.Iterator, error) {
+	query, err := r.Paginator()
+	if err != nil {
+		return nil, err
+	}
+	return query.IteratorContext(r.Session().Context()), nil
+}
+
+// Each calls fn with each result of the set.
+func (r *Result) Each(fn interface{}) error {
+	fnv, argT, err := records.CallbackOf(fn)
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	itemT := argT
+	isPtr := argT.Kind() == reflect.Ptr
+	if isPtr {
+		itemT = argT.Elem()
+	}
+
+	err = r.iterate(itemT, func(item reflect.Value) error {
+		if err := r.preloadRelations(item.Interface()); err != nil {
+			return err
+		}
+		snapshotRecords(r.Session(), item.Interface())
+		if isPtr {
+			return records.Call(fnv, item)
+		}
+		return records.Call(fnv, item.Elem())
+	})
+	r.setErr(err)
+	return err
+}
+
+// Chunk calls fn with batches of up to size results of the set.
+func (r *Result) Chunk(size int, fn interface{}) error {
+	if size < 1 {
+		r.setErr(db.ErrInvalidChunkSize)
+		return db.ErrInvalidChunkSize
+	}
+	fnv, sliceT, err := records.CallbackOf(fn)
+	if err == nil && sliceT.Kind() != reflect.Slice {
+		err = db.ErrInvalidCallback
+	}
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	flush := func(batch reflect.Value) error {
+		batchPtr := reflect.New(sliceT)
+		batchPtr.Elem().Set(batch)
+		if err := r.preloadRelations(batchPtr.Interface()); err != nil {
+			return err
+		}
+		snapshotRecords(r.Session(), batchPtr.Interface())
+		return records.Call(fnv, batch)
+	}
+
+	batch := reflect.MakeSlice(sliceT, 0, size)
+	err = r.iterate(sliceT.Elem(), func(item reflect.Value) error {
+		batch = reflect.Append(batch, item.Elem())
+		if batch.Len() < size {
+			return nil
+		}
+		full := batch
+		batch = reflect.MakeSlice(sliceT, 0, size)
+		return flush(full)
+	})
+	if err == nil && batch.Len() > 0 {
+		err = flush(batch)
+	}
+	r.setErr(err)
+	return err
+}
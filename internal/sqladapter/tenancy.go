@@ -0,0 +1,782 @@
+package sqladapter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter/exql"
+)
+
+const tenantMarkerFormat = "__upper_tenant_%d__"
+
+// tenancy holds the settings of a session created with db.WithTenancy.
+type tenancy struct {
+	column string
+	tables map[string]struct{}
+	value  func(ctx context.Context) (interface{}, error)
+}
+
+func newTenancy(settings db.Tenancy) *tenancy {
+	t := &tenancy{
+		column: settings.Column,
+		tables: make(map[string]struct{}, len(settings.Tables)),
+		value:  settings.Value,
+	}
+	for _, table := range settings.Tables {
+		t.tables[strings.ToLower(unquoteIdentifier(table))] = struct{}{}
+	}
+	return t
+}
+
+// protects reports whether the given table, which may be qualified by a
+// schema name, is one of the protected tables.
+func (t *tenancy) protects(table string) bool {
+	table = strings.ToLower(unquoteIdentifier(table))
+	if _, ok := t.tables[table]; ok {
+		return true
+	}
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		_, ok := t.tables[table[i+1:]]
+		return ok
+	}
+	return false
+}
+
+// tenant returns the identifier of the current tenant, ctx is the context of
+// the statement and sessCtx the default context of the session.
+func (t *tenancy) tenant(ctx context.Context, sessCtx context.Context) (interface{}, error) {
+	for _, c := range []context.Context{ctx, sessCtx} {
+		if c == nil {
+			continue
+		}
+		if t.value != nil {
+			tenant, err := t.value(c)
+			if err != nil {
+				return nil, err
+			}
+			if tenant != nil {
+				return tenant, nil
+			}
+			continue
+		}
+		if tenant, ok := db.TenantFromContext(c); ok {
+			return tenant, nil
+		}
+	}
+	return nil, db.ErrMissingTenant
+}
+
+// WithTenancy returns a copy of the session that restricts the statements on
+// the protected tables to the current tenant, see db.WithTenancy.
+func (sess *sessionWithContext) WithTenancy(settings db.Tenancy) db.Session {
+	return &sessionWithContext{
+		session:      sess.session,
+		ctx:          sess.ctx,
+		tenancy:      newTenancy(settings),
+		fieldMapper:  sess.fieldMapper,
+		mapper:       sess.mapper,
+		strictScan:   sess.strictScan,
+		codecs:       sess.codecs,
+		declarations: sess.declarations,
+	}
+}
+
+// tenantStatement returns a copy of stmt and args that is restricted to the
+// current tenant. Statements that don't touch protected tables are returned
+// as they are.
+func (sess *sessionWithContext) tenantStatement(ctx context.Context, stmt *exql.Statement, args []interface{}) (*exql.Statement, []interface{}, error) {
+	if sess.tenancy == nil || stmt == nil {
+		return stmt, args, nil
+	}
+
+	f := &tenantFilter{
+		tenancy:  sess.tenancy,
+		layout:   sess.adapter.Template(),
+		allowRaw: db.RawAllowed(ctx) || db.RawAllowed(sess.Context()),
+	}
+
+	marked, err := f.filter(stmt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(f.markers) == 0 {
+		return stmt, args, nil
+	}
+
+	tenant, err := sess.tenancy.tenant(ctx, sess.Context())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	compiled, err := marked.Compile(f.layout)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args, err = f.insertTenant(compiled, args, tenant); err != nil {
+		return nil, nil, err
+	}
+
+	f.markers, f.final = nil, true
+	filtered, err := f.filter(stmt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return filtered, args, nil
+}
+
+// tenantFilter rewrites statements on protected tables. Tenant values are
+// added as placeholders; in a first pass every placeholder is written as a
+// unique marker, which is used to find out where the tenant value goes in the
+// list of arguments.
+type tenantFilter struct {
+	*tenancy
+
+	layout   *exql.Template
+	allowRaw bool
+	final    bool
+
+	// markers holds the number of arguments that were replaced by each
+	// marker.
+	markers []int
+}
+
+func (f *tenantFilter) placeholder(replaced exql.Fragment) (exql.Fragment, error) {
+	if f.final {
+		return &exql.Raw{Value: "?"}, nil
+	}
+	n := 0
+	if replaced != nil {
+		compiled, err := replaced.Compile(f.layout)
+		if err != nil {
+			return nil, err
+		}
+		n = strings.Count(compiled, "?")
+	}
+	f.markers = append(f.markers, n)
+	return &exql.Raw{Value: fmt.Sprintf(tenantMarkerFormat, len(f.markers)-1)}, nil
+}
+
+// condition returns a "column = tenant" condition for the table with the
+// given qualifier.
+func (f *tenantFilter) condition(qualifier string) (exql.Fragment, error) {
+	column := f.column
+	if qualifier != "" {
+		column = qualifier + "." + column
+	}
+	value, err := f.placeholder(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &exql.ColumnValue{
+		Column:   exql.ColumnWithName(column),
+		Operator: "=",
+		Value:    value,
+	}, nil
+}
+
+// insertTenant returns a copy of args with the tenant value in place of
+// every marker found on the compiled query.
+func (f *tenantFilter) insertTenant(compiled string, args []interface{}, tenant interface{}) ([]interface{}, error) {
+	type marker struct {
+		at       int
+		replaced int
+	}
+
+	markers := make([]marker, 0, len(f.markers))
+	for i, replaced := range f.markers {
+		at := strings.Index(compiled, fmt.Sprintf(tenantMarkerFormat, i))
+		if at < 0 {
+			return nil, fmt.Errorf("%w: could not place the tenant value", db.ErrTenantIsolation)
+		}
+		markers = append(markers, marker{at: at, replaced: replaced})
+	}
+	sort.Slice(markers, func(i, j int) bool {
+		return markers[i].at < markers[j].at
+	})
+
+	out := make([]interface{}, 0, len(args)+len(markers))
+	src, removed := 0, 0
+	for _, m := range markers {
+		before := strings.Count(compiled[:m.at], "?") + removed
+		if before > len(args) || before+m.replaced > len(args) {
+			return nil, fmt.Errorf("%w: could not place the tenant value", db.ErrTenantIsolation)
+		}
+		out = append(out, args[src:before]...)
+		out = append(out, tenant)
+		src = before + m.replaced
+		removed += m.replaced
+	}
+	return append(out, args[src:]...), nil
+}
+
+func (f *tenantFilter) refuse(format string, a ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{db.ErrTenantIsolation}, a...)...)
+}
+
+// filter returns a copy of stmt that is restricted to the current tenant.
+func (f *tenantFilter) filter(stmt *exql.Statement) (*exql.Statement, error) {
+	switch stmt.Type {
+	case exql.SQL:
+		if !f.allowRaw {
+			for _, table := range sqlTableNames(stmt.SQL) {
+				if f.protects(table) {
+					return nil, f.refuse("raw query on table %q", table)
+				}
+			}
+		}
+		return stmt, nil
+	case exql.Truncate:
+		for _, table := range f.tablesOf(stmt.Table) {
+			if f.protects(table.name) {
+				return nil, f.refuse("truncate of table %q", table.name)
+			}
+		}
+		return stmt, nil
+	case exql.Select, exql.Count:
+		return f.filterSelect(stmt)
+	case exql.Update, exql.Delete:
+		return f.filterUpdate(stmt)
+	case exql.Insert:
+		return f.filterInsert(stmt)
+	}
+	return stmt, nil
+}
+
+func (f *tenantFilter) filterSelect(stmt *exql.Statement) (*exql.Statement, error) {
+	tables := f.tablesOf(stmt.Table)
+
+	var joins []*exql.Join
+	if j, ok := stmt.Joins.(*exql.Joins); ok && j != nil {
+		for _, c := range j.Conditions {
+			if join, ok := c.(*exql.Join); ok {
+				joins = append(joins, join)
+			}
+		}
+	}
+
+	qualify := len(tables)+len(joins) > 1
+
+	var conds []exql.Fragment
+	for _, table := range tables {
+		if table.raw {
+			if err := f.checkRaw(table.name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !f.protects(table.name) {
+			continue
+		}
+		cond, err := f.condition(table.qualifier(qualify))
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+
+	filteredJoins := make([]exql.Fragment, 0, len(joins))
+	changedJoins := false
+	for _, join := range joins {
+		var joinConds []exql.Fragment
+		for _, table := range f.tablesOf(join.Table) {
+			if table.raw {
+				if err := f.checkRaw(table.name); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if !f.protects(table.name) {
+				continue
+			}
+			cond, err := f.condition(table.qualifier(true))
+			if err != nil {
+				return nil, err
+			}
+			joinConds = append(joinConds, cond)
+		}
+		if err := f.checkConditions(join.On); err != nil {
+			return nil, err
+		}
+		if len(joinConds) == 0 {
+			filteredJoins = append(filteredJoins, join)
+			continue
+		}
+		changedJoins = true
+		on, ok := join.On.(*exql.On)
+		if !ok || on == nil {
+			// Joins without an ON clause are filtered on the WHERE clause.
+			conds = append(conds, joinConds...)
+			filteredJoins = append(filteredJoins, join)
+			continue
+		}
+		filteredJoin := *join
+		filteredJoin.On = exql.OnConditions(append(append([]exql.Fragment{}, on.Conditions...), joinConds...)...)
+		filteredJoins = append(filteredJoins, &filteredJoin)
+	}
+
+	for _, fragment := range []exql.Fragment{stmt.Where, stmt.Columns, stmt.OrderBy, stmt.GroupBy} {
+		if err := f.checkConditions(fragment); err != nil {
+			return nil, err
+		}
+	}
+	if len(conds) == 0 && !changedJoins {
+		return stmt, nil
+	}
+
+	filtered := *stmt
+	if changedJoins {
+		filtered.Joins = &exql.Joins{Conditions: filteredJoins}
+	}
+	if len(conds) > 0 {
+		filtered.Where = f.appendConditions(stmt.Where, conds)
+	}
+	return &filtered, nil
+}
+
+func (f *tenantFilter) filterUpdate(stmt *exql.Statement) (*exql.Statement, error) {
+	if err := f.checkConditions(stmt.Where); err != nil {
+		return nil, err
+	}
+
+	var conds []exql.Fragment
+	for _, table := range f.tablesOf(stmt.Table) {
+		if !f.protects(table.name) {
+			continue
+		}
+		cond, err := f.condition("")
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	if len(conds) == 0 {
+		return stmt, nil
+	}
+
+	if columnValues, ok := stmt.ColumnValues.(*exql.ColumnValues); ok && columnValues != nil {
+		for _, c := range columnValues.ColumnValues {
+			if cv, ok := c.(*exql.ColumnValue); ok && f.isTenantColumn(cv.Column) {
+				return nil, f.refuse("update of column %q", f.column)
+			}
+		}
+	}
+
+	filtered := *stmt
+	filtered.Where = f.appendConditions(stmt.Where, conds)
+	return &filtered, nil
+}
+
+func (f *tenantFilter) filterInsert(stmt *exql.Statement) (*exql.Statement, error) {
+	var protected *tenantTable
+	for _, table := range f.tablesOf(stmt.Table) {
+		if f.protects(table.name) {
+			table := table
+			protected = &table
+		}
+	}
+	if protected == nil {
+		return stmt, nil
+	}
+
+	var columns []exql.Fragment
+	if c, ok := stmt.Columns.(*exql.Columns); ok && c != nil {
+		columns = c.Columns
+	}
+	var groups []*exql.Values
+	if v, ok := stmt.Values.(*exql.ValueGroups); ok && v != nil {
+		groups = v.Values
+	}
+	if len(columns) == 0 && len(groups) > 0 {
+		return nil, f.refuse("insert without column names")
+	}
+	if len(groups) == 0 {
+		groups = []*exql.Values{{}}
+	}
+
+	index := -1
+	for i := range columns {
+		if f.isTenantColumn(columns[i]) {
+			index = i
+			break
+		}
+	}
+
+	filteredColumns := append([]exql.Fragment{}, columns...)
+	if index < 0 {
+		filteredColumns = append(filteredColumns, exql.ColumnWithName(f.column))
+	}
+
+	filteredGroups := make([]*exql.Values, 0, len(groups))
+	for _, group := range groups {
+		values := append([]exql.Fragment{}, group.Values...)
+		if index < 0 {
+			value, err := f.placeholder(nil)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		} else {
+			if index >= len(values) {
+				return nil, f.refuse("insert with missing values")
+			}
+			value, err := f.placeholder(values[index])
+			if err != nil {
+				return nil, err
+			}
+			values[index] = value
+		}
+		filteredGroups = append(filteredGroups, exql.NewValueGroup(values...))
+	}
+
+	filtered := *stmt
+	filtered.Columns = exql.JoinColumns(filteredColumns...)
+	filtered.Values = exql.JoinValueGroups(filteredGroups...)
+	if oc, ok := stmt.OnConflict.(*exql.OnConflict); ok && oc != nil {
+		filteredOnConflict, err := f.filterOnConflict(*protected, oc)
+		if err != nil {
+			return nil, err
+		}
+		filtered.OnConflict = filteredOnConflict
+	}
+	return &filtered, nil
+}
+
+// filterOnConflict restricts the rows that are updated by an upsert to the
+// ones of the current tenant. Upserts that could move rows to another tenant,
+// or that are not restricted by the database, are refused.
+func (f *tenantFilter) filterOnConflict(table tenantTable, oc *exql.OnConflict) (*exql.OnConflict, error) {
+	if len(oc.Update) == 0 {
+		// Conflicting rows are left as they are.
+		return oc, nil
+	}
+	for _, column := range oc.Update {
+		if f.isTenantColumn(exql.ColumnWithName(column)) {
+			return nil, f.refuse("update of column %q", f.column)
+		}
+	}
+
+	cond, err := f.condition(table.qualifier(true))
+	if err != nil {
+		return nil, err
+	}
+	filtered := *oc
+	filtered.Where = exql.WhereConditions(cond)
+
+	if !f.final {
+		compiled, err := filtered.Compile(f.layout)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(compiled, fmt.Sprintf(tenantMarkerFormat, len(f.markers)-1)) {
+			return nil, f.refuse("upsert on table %q", table.name)
+		}
+	}
+	return &filtered, nil
+}
+
+func (f *tenantFilter) isTenantColumn(column exql.Fragment) bool {
+	c, ok := column.(*exql.Column)
+	if !ok {
+		return false
+	}
+	name, ok := c.Name.(string)
+	if !ok {
+		return false
+	}
+	name = unquoteIdentifier(strings.TrimSpace(name))
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.EqualFold(name, f.column)
+}
+
+func (f *tenantFilter) appendConditions(where exql.Fragment, conds []exql.Fragment) exql.Fragment {
+	if w, ok := where.(*exql.Where); ok && w != nil {
+		return exql.WhereConditions(append(append([]exql.Fragment{}, w.Conditions...), conds...)...)
+	}
+	return exql.WhereConditions(conds...)
+}
+
+// checkRaw refuses raw fragments, like subqueries, that read from protected
+// tables.
+func (f *tenantFilter) checkRaw(fragment string) error {
+	if f.allowRaw {
+		return nil
+	}
+	for _, table := range sqlTableNames(fragment) {
+		if f.protects(table) {
+			return f.refuse("raw query on table %q", table)
+		}
+	}
+	return nil
+}
+
+// checkConditions looks for raw fragments within conditions and within the
+// columns of the select list, ORDER BY and GROUP BY.
+func (f *tenantFilter) checkConditions(fragment exql.Fragment) error {
+	var conds []exql.Fragment
+	switch c := fragment.(type) {
+	case *exql.Columns:
+		if c != nil {
+			conds = c.Columns
+		}
+	case *exql.Column:
+		if c != nil {
+			if name, ok := c.Name.(exql.Fragment); ok {
+				conds = []exql.Fragment{name}
+			}
+		}
+	case *exql.OrderBy:
+		if c != nil {
+			conds = []exql.Fragment{c.SortColumns}
+		}
+	case *exql.SortColumns:
+		if c != nil {
+			conds = c.Columns
+		}
+	case *exql.SortColumn:
+		if c != nil {
+			conds = []exql.Fragment{c.Column}
+		}
+	case *exql.GroupBy:
+		if c != nil {
+			conds = []exql.Fragment{c.Columns}
+		}
+	case *exql.Where:
+		if c != nil {
+			conds = c.Conditions
+		}
+	case *exql.And:
+		if c != nil {
+			conds = c.Conditions
+		}
+	case *exql.Or:
+		if c != nil {
+			conds = c.Conditions
+		}
+	case *exql.On:
+		if c != nil {
+			conds = c.Conditions
+		}
+	case *exql.ColumnValue:
+		if c != nil {
+			conds = []exql.Fragment{c.Column, c.Value}
+		}
+	case *exql.Raw:
+		if c != nil {
+			return f.checkRaw(c.Value)
+		}
+	}
+	for _, cond := range conds {
+		if err := f.checkConditions(cond); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type tenantTable struct {
+	name  string
+	alias string
+	raw   bool
+}
+
+// qualifier returns the name that is used to refer to the columns of the
+// table.
+func (t tenantTable) qualifier(qualify bool) string {
+	if !qualify {
+		return ""
+	}
+	if t.alias != "" {
+		return t.alias
+	}
+	name := t.name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// tablesOf returns the tables within a Table or Columns fragment.
+func (f *tenantFilter) tablesOf(fragment exql.Fragment) []tenantTable {
+	var tables []tenantTable
+	switch t := fragment.(type) {
+	case *exql.Table:
+		if t == nil {
+			break
+		}
+		switch name := t.Name.(type) {
+		case string:
+			for _, part := range strings.Split(name, ",") {
+				tables = append(tables, parseTenantTable(part))
+			}
+		case exql.Raw:
+			tables = append(tables, tenantTable{name: name.Value, raw: true})
+		}
+	case *exql.Columns:
+		if t == nil {
+			break
+		}
+		for _, c := range t.Columns {
+			switch column := c.(type) {
+			case *exql.Column:
+				if name, ok := column.Name.(string); ok {
+					tables = append(tables, parseTenantTable(name))
+				} else if raw, ok := column.Name.(*exql.Raw); ok {
+					tables = append(tables, tenantTable{name: raw.Value, raw: true})
+				}
+			case *exql.Raw:
+				tables = append(tables, tenantTable{name: column.Value, raw: true})
+			}
+		}
+	}
+	return tables
+}
+
+// parseTenantTable parses a table name like "name", "name alias" or "name AS
+// alias".
+func parseTenantTable(s string) tenantTable {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return tenantTable{}
+	}
+	table := tenantTable{name: unquoteIdentifier(fields[0])}
+	if len(fields) > 1 {
+		table.alias = unquoteIdentifier(fields[len(fields)-1])
+	}
+	return table
+}
+
+func unquoteIdentifier(s string) string {
+	return strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(s)
+}
+
+// sqlKeywords are the keywords that may follow a table name, so they are not
+// taken as table aliases.
+var sqlKeywords = map[string]struct{}{
+	"AS": {}, "CROSS": {}, "DEFAULT": {}, "EXCEPT": {}, "FOR": {}, "FULL": {},
+	"GROUP": {}, "HAVING": {}, "INNER": {}, "INTERSECT": {}, "JOIN": {},
+	"LEFT": {}, "LIMIT": {}, "NATURAL": {}, "OFFSET": {}, "ON": {}, "ORDER": {},
+	"OUTER": {}, "RETURNING": {}, "RIGHT": {}, "SELECT": {}, "SET": {},
+	"UNION": {}, "USING": {}, "VALUES": {}, "WHERE": {}, "WINDOW": {},
+}
+
+// sqlTableNames returns the names of the tables a raw SQL query reads from or
+// writes to, that is, the names that follow FROM, JOIN, UPDATE, INTO and
+// TRUNCATE.
+func sqlTableNames(query string) []string {
+	tokens := sqlTokens(query)
+
+	isName := func(i int) bool {
+		if i >= len(tokens) || tokens[i] == "" {
+			return false
+		}
+		if _, ok := sqlKeywords[strings.ToUpper(tokens[i])]; ok {
+			return false
+		}
+		return !strings.ContainsAny(tokens[i][:1], ",()'=;*+-/<>!|%&")
+	}
+
+	var names []string
+	for i := 0; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "FROM", "JOIN", "UPDATE", "INTO":
+		case "TRUNCATE":
+			if i+1 < len(tokens) && strings.EqualFold(tokens[i+1], "TABLE") {
+				i++
+			}
+		default:
+			continue
+		}
+		for isName(i + 1) {
+			names = append(names, tokens[i+1])
+			i++
+			if i+1 < len(tokens) && strings.EqualFold(tokens[i+1], "AS") {
+				i++
+			}
+			if isName(i + 1) {
+				i++ // alias
+			}
+			if i+1 < len(tokens) && tokens[i+1] == "," {
+				i++
+				continue
+			}
+			break
+		}
+	}
+	return names
+}
+
+// sqlTokens splits a query into words, which may be qualified and quoted
+// identifiers, and punctuation. String literals are replaced by a single "'"
+// token, comments are skipped like whitespace.
+func sqlTokens(query string) []string {
+	var tokens []string
+	var word strings.Builder
+
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '_' || c == '$' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80:
+			word.WriteByte(c)
+		case c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(query[i+1:], closing)
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			word.WriteString(query[i+1 : i+1+end])
+			i += end + 1
+		case c == '\'':
+			flush()
+			end := i + 1
+			for end < len(query) {
+				if query[end] == '\'' {
+					if end+1 < len(query) && query[end+1] == '\'' {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			tokens = append(tokens, "'")
+			i = end
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			flush()
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			i += end
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			flush()
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 2
+			}
+			i += end + 3
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		default:
+			flush()
+			tokens = append(tokens, string(c))
+		}
+	}
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,74 @@
+package sqladapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+func TestSQLTableNames(t *testing.T) {
+	testCases := map[string][]string{
+		`SELECT * FROM accounts`: {"accounts"},
+		`SELECT * FROM "public"."accounts" AS a, invoices i WHERE a.id = i.account_id`: {"public.accounts", "invoices"},
+		`SELECT a.id FROM accounts a JOIN invoices ON (a.id = invoices.account_id)`:    {"accounts", "invoices"},
+		`SELECT * FROM (SELECT * FROM accounts) AS t`:                                  {"accounts"},
+		`UPDATE accounts SET name = 'FROM invoices'`:                                   {"accounts"},
+		`INSERT INTO accounts (name) VALUES (?)`:                                       {"accounts"},
+		`DELETE FROM accounts WHERE id = ?`:                                            {"accounts"},
+		`TRUNCATE TABLE accounts`:                                                      {"accounts"},
+		`SELECT accounts.name FROM users WHERE users.id = ?`:                           {"users"},
+		`PRAGMA TABLE_INFO('accounts')`:                                                nil,
+		`SELECT secret FROM/**/accounts`:                                               {"accounts"},
+		`SELECT secret FROM /* FROM invoices */ accounts`:                              {"accounts"},
+		"SELECT secret FROM -- FROM invoices\naccounts":                                {"accounts"},
+		"SELECT secret -- FROM invoices":                                               nil,
+		`SELECT 4/2, 2*3 FROM accounts`:                                                {"accounts"},
+	}
+	for query, tables := range testCases {
+		assert.Equal(t, tables, sqlTableNames(query), query)
+	}
+}
+
+func TestTenancyProtects(t *testing.T) {
+	tenancy := newTenancy(db.Tenancy{Column: "tenant_id", Tables: []string{"Accounts"}})
+
+	assert.True(t, tenancy.protects("accounts"))
+	assert.True(t, tenancy.protects(`"public"."accounts"`))
+	assert.False(t, tenancy.protects("accounts_archive"))
+
+	assert.Equal(t, tenantTable{name: "accounts", alias: "a"}, parseTenantTable("accounts AS a"))
+	assert.Equal(t, tenantTable{name: "accounts", alias: "a"}, parseTenantTable(" accounts a"))
+	assert.Equal(t, tenantTable{name: "accounts"}, parseTenantTable("accounts"))
+}
+
+func TestTenancyTenant(t *testing.T) {
+	tenancy := newTenancy(db.Tenancy{Column: "tenant_id", Tables: []string{"accounts"}})
+
+	_, err := tenancy.tenant(context.Background(), context.Background())
+	assert.True(t, errors.Is(err, db.ErrMissingTenant))
+
+	tenant, err := tenancy.tenant(context.Background(), db.ContextWithTenant(context.Background(), 7))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, tenant)
+
+	tenant, err = tenancy.tenant(db.ContextWithTenant(context.Background(), 8), db.ContextWithTenant(context.Background(), 7))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, tenant)
+}
+
+func TestTenantFilterInsertTenant(t *testing.T) {
+	f := &tenantFilter{markers: []int{0, 1}}
+
+	// The second marker replaces one argument.
+	args, err := f.insertTenant(`a = ? AND b = __upper_tenant_1__ AND c = ? AND d = __upper_tenant_0__`, []interface{}{"a", "b", "c"}, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", 9, "c", 9}, args)
+
+	// The replaced argument is missing.
+	f = &tenantFilter{markers: []int{1}}
+	_, err = f.insertTenant(`a = __upper_tenant_0__`, nil, 9)
+	assert.True(t, errors.Is(err, db.ErrTenantIsolation))
+}
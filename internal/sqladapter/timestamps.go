@@ -0,0 +1,107 @@
+package sqladapter
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// Struct tag options for fields that are set automatically to the time
+// records are created or updated, like `db:"created_at,autocreatetime"`.
+const (
+	optionAutoCreateTime = "autocreatetime"
+	optionAutoUpdateTime = "autoupdatetime"
+)
+
+// setTimeField sets field to the given time. Fields of type time.Time,
+// *time.Time and sql.Scanner values that accept time.Time are supported.
+func setTimeField(field reflect.Value, t time.Time) bool {
+	switch field.Interface().(type) {
+	case time.Time:
+		field.Set(reflect.ValueOf(t))
+		return true
+	case *time.Time:
+		field.Set(reflect.ValueOf(&t))
+		return true
+	}
+	if field.CanAddr() {
+		if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(t) == nil
+		}
+	}
+	return false
+}
+
+// isZeroTimeField returns true if field holds no time.
+func isZeroTimeField(field reflect.Value) bool {
+	switch v := field.Interface().(type) {
+	case time.Time:
+		return v.IsZero()
+	case *time.Time:
+		return v == nil || v.IsZero()
+	}
+	return field.IsZero()
+}
+
+// setTimestamps sets the fields of item tagged with autocreatetime (only when
+// creating, and only if they're zero) and autoupdatetime to the given time.
+// item must be an addressable struct value.
+func setTimestamps(mapper *reflectx.Mapper, item reflect.Value, t time.Time, creating bool) {
+	for _, fi := range mapper.TypeMap(item.Type()).Index {
+		if fi.Options == nil {
+			continue
+		}
+		if _, ok := fi.Options[optionAutoUpdateTime]; ok {
+			setTimeField(reflectx.FieldByIndexes(item, fi.Index), t)
+			continue
+		}
+		if _, ok := fi.Options[optionAutoCreateTime]; ok && creating {
+			field := reflectx.FieldByIndexes(item, fi.Index)
+			if isZeroTimeField(field) {
+				setTimeField(field, t)
+			}
+		}
+	}
+}
+
+// setRecordTimestamps sets the automatic timestamps of a record, which is
+// expected to be a pointer to a struct.
+func setRecordTimestamps(mapper *reflectx.Mapper, record interface{}, t time.Time, creating bool) {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	setTimestamps(mapper, v.Elem(), t, creating)
+}
+
+// withUpdateTimestamps returns values with fields tagged with autoupdatetime
+// set to the given time. Pointers to structs are updated in place, struct
+// values are copied.
+func withUpdateTimestamps(mapper *reflectx.Mapper, values interface{}, t time.Time) interface{} {
+	v := reflect.ValueOf(values)
+	switch {
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct:
+		setTimestamps(mapper, v.Elem(), t, false)
+		return values
+	case v.Kind() == reflect.Struct:
+		if !hasAutoUpdateTime(mapper, v.Type()) {
+			return values
+		}
+		item := reflect.New(v.Type())
+		item.Elem().Set(v)
+		setTimestamps(mapper, item.Elem(), t, false)
+		return item.Interface()
+	}
+	return values
+}
+
+func hasAutoUpdateTime(mapper *reflectx.Mapper, t reflect.Type) bool {
+	for _, fi := range mapper.TypeMap(t).Index {
+		if _, ok := fi.Options[optionAutoUpdateTime]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package sqladapter
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+type timestampsTestItem struct {
+	Name      string       `db:"name"`
+	CreatedAt time.Time    `db:"created_at,autocreatetime"`
+	UpdatedAt *time.Time   `db:"updated_at,autoupdatetime"`
+	SeenAt    sql.NullTime `db:"seen_at,omitempty,autoupdatetime"`
+}
+
+func TestSetTimestamps(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+
+	item := timestampsTestItem{Name: "foo"}
+	setRecordTimestamps(sqlbuilder.Mapper, &item, created, true)
+	assert.Equal(t, created, item.CreatedAt)
+	if assert.NotNil(t, item.UpdatedAt) {
+		assert.Equal(t, created, *item.UpdatedAt)
+	}
+	assert.Equal(t, sql.NullTime{Time: created, Valid: true}, item.SeenAt)
+
+	setRecordTimestamps(sqlbuilder.Mapper, &item, updated, false)
+	assert.Equal(t, created, item.CreatedAt)
+	assert.Equal(t, updated, *item.UpdatedAt)
+	assert.Equal(t, updated, item.SeenAt.Time)
+
+	// Creation times that were already set are kept.
+	item = timestampsTestItem{CreatedAt: created}
+	setRecordTimestamps(sqlbuilder.Mapper, &item, updated, true)
+	assert.Equal(t, created, item.CreatedAt)
+}
+
+func TestWithUpdateTimestamps(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	item := &timestampsTestItem{Name: "foo"}
+	assert.Equal(t, item, withUpdateTimestamps(sqlbuilder.Mapper, item, now))
+	assert.Equal(t, now, *item.UpdatedAt)
+	assert.True(t, item.CreatedAt.IsZero())
+
+	value := timestampsTestItem{Name: "bar"}
+	copied, ok := withUpdateTimestamps(sqlbuilder.Mapper, value, now).(*timestampsTestItem)
+	if assert.True(t, ok) {
+		assert.Equal(t, "bar", copied.Name)
+		assert.Equal(t, now, *copied.UpdatedAt)
+	}
+	assert.Nil(t, value.UpdatedAt)
+
+	values := map[string]interface{}{"name": "baz"}
+	assert.Equal(t, values, withUpdateTimestamps(sqlbuilder.Mapper, values, now))
+}
@@ -0,0 +1,51 @@
+package sqladapter
+
+import (
+	"reflect"
+
+	db "github.com/upper/db/v4"
+)
+
+var trackedType = reflect.TypeOf((*db.Tracked)(nil)).Elem()
+
+// snapshotRecord takes a snapshot of a tracked record with the struct mapper
+// of the session.
+func snapshotRecord(sess db.Session, record db.Record) {
+	db.SnapshotWith(record, sess)
+}
+
+// snapshotRecords takes snapshots of the tracked records dst points to, dst
+// is either a pointer to a record or a pointer to a slice of records.
+func snapshotRecords(sess db.Session, dst interface{}) {
+	if record, ok := dst.(db.Tracked); ok {
+		snapshotRecord(sess, record)
+		return
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return
+	}
+	v = v.Elem()
+
+	t := v.Type().Elem()
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+	if !reflect.PtrTo(t).Implements(trackedType) {
+		return
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		if isPtr {
+			if item.IsNil() {
+				continue
+			}
+		} else {
+			item = item.Addr()
+		}
+		snapshotRecord(sess, item.Interface().(db.Record))
+	}
+}
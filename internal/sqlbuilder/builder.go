@@ -35,6 +35,7 @@ import (
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/adapter"
 	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/records"
 	"github.com/upper/db/v4/internal/sqladapter/compat"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
 )
@@ -43,6 +44,17 @@ import (
 type MapOptions struct {
 	IncludeZeroed bool
 	IncludeNil    bool
+
+	// Mapper maps struct fields to columns, Mapper is used if nil.
+	Mapper *reflectx.Mapper
+
+	// Codecs encode the values of the types registered in it.
+	Codecs *db.Codecs
+
+	// Table is the table values are mapped for, the values that maps have
+	// for the encrypted columns declared for it in Codecs are encrypted, see
+	// db.Codecs.EncryptColumns.
+	Table string
 }
 
 var defaultMapOptions = MapOptions{
@@ -67,6 +79,10 @@ type iterator struct {
 	sess   exprDB
 	cursor *sql.Rows // This is the main query cursor. It starts as a nil value.
 	err    error
+
+	// columns and kinds are the column types of cursor, see loadColumns.
+	columns []db.ResultColumn
+	kinds   []columnKind
 }
 
 type fieldValue struct {
@@ -115,7 +131,7 @@ func WithTemplate(t *exql.Template) db.SQL {
 }
 
 func (b *sqlBuilder) NewIteratorContext(ctx context.Context, rows *sql.Rows) db.Iterator {
-	return &iterator{b.sess, rows, nil}
+	return &iterator{sess: b.sess, cursor: rows}
 }
 
 func (b *sqlBuilder) NewIterator(rows *sql.Rows) db.Iterator {
@@ -128,7 +144,7 @@ func (b *sqlBuilder) Iterator(query interface{}, args ...interface{}) db.Iterato
 
 func (b *sqlBuilder) IteratorContext(ctx context.Context, query interface{}, args ...interface{}) db.Iterator {
 	rows, err := b.QueryContext(ctx, query, args...)
-	return &iterator{b.sess, rows, err}
+	return &iterator{sess: b.sess, cursor: rows, err: err}
 }
 
 func (b *sqlBuilder) Prepare(query interface{}) (*sql.Stmt, error) {
@@ -234,6 +250,41 @@ func (b *sqlBuilder) Update(table string) db.Updater {
 	return qu.setTable(table)
 }
 
+func (b *sqlBuilder) CreateTable(table string) db.TableCreator {
+	tc := &tableCreator{
+		builder: b,
+	}
+	return tc.setTable(table)
+}
+
+func (b *sqlBuilder) AlterTable(table string) db.TableAlterer {
+	ta := &tableAlterer{
+		builder: b,
+	}
+	return ta.setTable(table)
+}
+
+func (b *sqlBuilder) DropTable(table string) db.TableDropper {
+	td := &tableDropper{
+		builder: b,
+	}
+	return td.setTable(table)
+}
+
+func (b *sqlBuilder) CreateIndex(name string) db.IndexCreator {
+	ic := &indexCreator{
+		builder: b,
+	}
+	return ic.setName(name)
+}
+
+func (b *sqlBuilder) DropIndex(name string) db.IndexDropper {
+	id := &indexDropper{
+		builder: b,
+	}
+	return id.setName(name)
+}
+
 // Map receives a pointer to map or struct and maps it to columns and values.
 func Map(item interface{}, options *MapOptions) ([]string, []interface{}, error) {
 	var fv fieldValue
@@ -258,7 +309,11 @@ func Map(item interface{}, options *MapOptions) ([]string, []interface{}, error)
 
 	switch itemT.Kind() {
 	case reflect.Struct:
-		fieldMap := Mapper.TypeMap(itemT).Names
+		mapper := options.Mapper
+		if mapper == nil {
+			mapper = Mapper
+		}
+		fieldMap := mapper.TypeMap(itemT).Names
 		nfields := len(fieldMap)
 
 		fv.values = make([]interface{}, 0, nfields)
@@ -307,8 +362,18 @@ func Map(item interface{}, options *MapOptions) ([]string, []interface{}, error)
 				continue
 			}
 
+			codec, deref, err := fieldCodec(options.Codecs, fi)
+			if err != nil {
+				return nil, nil, err
+			}
+
 			fv.fields = append(fv.fields, fi.Name)
-			v, err := marshal(value)
+			var v interface{}
+			if codec != nil {
+				v, err = encodeValue(codec, deref, fld)
+			} else {
+				v, err = marshal(value, options.Codecs)
+			}
 			if err != nil {
 				return nil, nil, err
 			}
@@ -324,11 +389,22 @@ func Map(item interface{}, options *MapOptions) ([]string, []interface{}, error)
 		fv.fields = make([]string, nfields)
 		mkeys := itemV.MapKeys()
 
+		var encrypted map[string]bool
+		if options.Table != "" {
+			encrypted = options.Codecs.EncryptedColumns(options.Table)
+		}
+
 		for i, keyV := range mkeys {
 			valv := itemV.MapIndex(keyV)
 			fv.fields[i] = fmt.Sprintf("%v", keyV.Interface())
 
-			v, err := marshal(valv.Interface())
+			var v interface{}
+			var err error
+			if deterministic, ok := encrypted[fv.fields[i]]; ok {
+				v, err = encryptValue(options.Codecs, deterministic, valv.Interface())
+			} else {
+				v, err = marshal(valv.Interface(), options.Codecs)
+			}
 			if err != nil {
 				return nil, nil, err
 			}
@@ -488,7 +564,14 @@ func (iter *iterator) All(dst interface{}) error {
 		return iter.setErr(err)
 	}
 
-	return nil
+	return iter.setErr(iter.afterFind(dst))
+}
+
+// afterFind calls the AfterFind hook of the records that were fetched into
+// dst.
+func (iter *iterator) afterFind(dst interface{}) error {
+	sess, _ := iter.sess.(db.Session)
+	return records.AfterFind(sess, dst)
 }
 
 func (iter *iterator) Err() (err error) {
@@ -532,12 +615,55 @@ func (iter *iterator) next(dst ...interface{}) error {
 			defer iter.Close()
 			return err
 		}
+		if err := iter.afterFind(dst[0]); err != nil {
+			defer iter.Close()
+			return err
+		}
 		return nil
 	}
 
 	return errors.New("Next does not currently supports more than one parameters")
 }
 
+func (iter *iterator) Stream(ctx context.Context, dst interface{}) (<-chan interface{}, <-chan error) {
+	items := make(chan interface{})
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(items)
+		defer iter.Close()
+
+		t := reflect.TypeOf(dst)
+		if t == nil || t.Kind() != reflect.Ptr {
+			errs <- ErrExpectingPointer
+			return
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+			item := reflect.New(t.Elem()).Interface()
+			if !iter.Next(item) {
+				if err := iter.Err(); err != nil {
+					errs <- err
+				}
+				return
+			}
+			select {
+			case items <- item:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return items, errs
+}
+
 func (iter *iterator) Close() (err error) {
 	if iter.cursor != nil {
 		err = iter.cursor.Close()
@@ -546,7 +672,10 @@ func (iter *iterator) Close() (err error) {
 	return err
 }
 
-func marshal(v interface{}) (interface{}, error) {
+func marshal(v interface{}, codecs *db.Codecs) (interface{}, error) {
+	if codec, deref := typeCodec(codecs, reflect.TypeOf(v)); codec != nil {
+		return encodeValue(codec, deref, reflect.ValueOf(v))
+	}
 	if m, isMarshaler := v.(db.Marshaler); isMarshaler {
 		var err error
 		if v, err = m.MarshalDB(); err != nil {
@@ -899,6 +899,16 @@ func TestInsert(t *testing.T) {
 		b.InsertInto("artist").Values(map[string]string{"id": "12", "name": "Chavela Vargas"}).Returning("id").String(),
 	)
 
+	assert.Equal(
+		`INSERT INTO "artist" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name" RETURNING "id"`,
+		b.InsertInto("artist").Values(map[string]string{"id": "12", "name": "Chavela Vargas"}).OnConflictUpdate([]string{"id"}, "name").Returning("id").String(),
+	)
+
+	assert.Equal(
+		`INSERT INTO "artist" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO NOTHING`,
+		b.InsertInto("artist").Values(map[string]string{"id": "12", "name": "Chavela Vargas"}).OnConflictUpdate([]string{"id"}).String(),
+	)
+
 	assert.Equal(
 		`INSERT INTO "artist" ("id", "name") VALUES ($1, $2) RETURNING "id"`,
 		b.InsertInto("artist").Values(map[string]string{"id": "12", "name": "Chavela Vargas"}).Amend(func(query string) string {
@@ -1482,3 +1492,111 @@ func stripWhitespace(in string) string {
 	q := reInvisibleChars.ReplaceAllString(in, ` `)
 	return strings.TrimSpace(q)
 }
+
+func TestCreateTable(t *testing.T) {
+	b := WithTemplate(&testTemplate)
+	assert := assert.New(t)
+
+	assert.Equal(
+		`CREATE TABLE "artist" ( "id" SERIAL, "name" VARCHAR(60) NOT NULL, PRIMARY KEY ("id") )`,
+		b.CreateTable("artist").
+			Column("id", db.TypeSerial).
+			Column("name", db.TypeString, db.Size(60), db.NotNull()).
+			PrimaryKey("id").
+			String(),
+	)
+
+	assert.Equal(
+		`CREATE TABLE IF NOT EXISTS "account" ( "id" BIGSERIAL, "email" VARCHAR(255) UNIQUE, "balance" NUMERIC(10, 2) NOT NULL DEFAULT 0, "active" BOOLEAN DEFAULT TRUE, "nickname" TEXT DEFAULT 'it''s me', "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, "artist_id" INTEGER, "data" jsonb, PRIMARY KEY ("id"), CONSTRAINT "account_nickname" UNIQUE ("nickname", "artist_id"), CONSTRAINT "account_artist_fk" FOREIGN KEY ("artist_id") REFERENCES "artist" ("id") ON DELETE CASCADE )`,
+		b.CreateTable("account").
+			IfNotExists().
+			Column("id", db.TypeBigSerial).
+			Column("email", db.TypeString, db.Unique()).
+			Column("balance", db.TypeDecimal, db.Precision(10, 2), db.NotNull(), db.Default(0)).
+			Column("active", db.TypeBoolean, db.Default(true)).
+			Column("nickname", db.TypeText, db.Default("it's me")).
+			Column("created_at", db.TypeTimestamp, db.Default(db.Raw("CURRENT_TIMESTAMP"))).
+			Column("artist_id", db.TypeInteger).
+			Column("data", "jsonb").
+			PrimaryKey("id").
+			Unique("account_nickname", "nickname", "artist_id").
+			ForeignKey(db.ForeignKey{
+				Name:              "account_artist_fk",
+				Columns:           []string{"artist_id"},
+				ReferencedTable:   "artist",
+				ReferencedColumns: []string{"id"},
+				OnDelete:          "cascade",
+			}).
+			String(),
+	)
+
+	{
+		q := b.CreateTable("artist").Column("data", db.TypeJSON, db.Default(struct{}{}))
+		_, err := q.(*tableCreator).Compile()
+		assert.Error(err)
+	}
+}
+
+func TestAlterTable(t *testing.T) {
+	b := WithTemplate(&testTemplate)
+	assert := assert.New(t)
+
+	assert.Equal(
+		`ALTER TABLE "artist" ADD COLUMN "country" VARCHAR(2) NOT NULL DEFAULT 'MX'`,
+		b.AlterTable("artist").AddColumn("country", db.TypeString, db.Size(2), db.NotNull(), db.Default("MX")).String(),
+	)
+
+	assert.Equal(
+		`ALTER TABLE "artist" DROP COLUMN "country"; ALTER TABLE "artist" RENAME COLUMN "name" TO "full_name"; ALTER TABLE "artist" RENAME TO "artists"`,
+		b.AlterTable("artist").DropColumn("country").RenameColumn("name", "full_name").RenameTo("artists").String(),
+	)
+
+	assert.Equal(
+		`ALTER TABLE "publication" ADD CONSTRAINT "publication_author_fk" FOREIGN KEY ("author_id") REFERENCES "artist" ("id"); ALTER TABLE "publication" ADD CONSTRAINT "publication_title" UNIQUE ("title"); ALTER TABLE "publication" DROP CONSTRAINT "publication_old"`,
+		b.AlterTable("publication").
+			AddForeignKey(db.ForeignKey{
+				Name:              "publication_author_fk",
+				Columns:           []string{"author_id"},
+				ReferencedTable:   "artist",
+				ReferencedColumns: []string{"id"},
+			}).
+			AddUnique("publication_title", "title").
+			DropConstraint("publication_old").
+			String(),
+	)
+}
+
+func TestDropTable(t *testing.T) {
+	b := WithTemplate(&testTemplate)
+	assert := assert.New(t)
+
+	assert.Equal(
+		`DROP TABLE "artist"`,
+		b.DropTable("artist").String(),
+	)
+
+	assert.Equal(
+		`DROP TABLE IF EXISTS "artist"`,
+		b.DropTable("artist").IfExists().String(),
+	)
+}
+
+func TestIndexes(t *testing.T) {
+	b := WithTemplate(&testTemplate)
+	assert := assert.New(t)
+
+	assert.Equal(
+		`CREATE INDEX "artist_name_idx" ON "artist" ("name")`,
+		b.CreateIndex("artist_name_idx").On("artist", "name").String(),
+	)
+
+	assert.Equal(
+		`CREATE UNIQUE INDEX IF NOT EXISTS "artist_name_idx" ON "artist" ("name", "country")`,
+		b.CreateIndex("artist_name_idx").On("artist", "name", "country").Unique().IfNotExists().String(),
+	)
+
+	assert.Equal(
+		`DROP INDEX IF EXISTS "artist_name_idx"`,
+		b.DropIndex("artist_name_idx").IfExists().String(),
+	)
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"database/sql"
+	"reflect"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+const (
+	optionCodec     = "codec"
+	optionEncrypted = "encrypted"
+)
+
+const encryptedDeterministic = "deterministic"
+
+// CodecsOf returns the type codecs used by the given session, or nil if the
+// session has none, see db.WithCodecs.
+func CodecsOf(sess interface{}) *db.Codecs {
+	if s, ok := sess.(interface {
+		Codecs() *db.Codecs
+	}); ok {
+		return s.Codecs()
+	}
+	return nil
+}
+
+// typeCodec returns the codec of values of type t, the codec of T is also
+// the codec of *T. deref is true if the codec is the one of the element type
+// of t.
+func typeCodec(codecs *db.Codecs, t reflect.Type) (codec db.Codec, deref bool) {
+	if codecs == nil || t == nil {
+		return nil, false
+	}
+	if codec := codecs.Lookup(t); codec != nil {
+		return codec, false
+	}
+	if t.Kind() == reflect.Ptr {
+		if codec := codecs.Lookup(t.Elem()); codec != nil {
+			return codec, true
+		}
+	}
+	return nil, false
+}
+
+// FieldCodec returns the codec named by the codec or encrypted options of the
+// field mapped to the given column of record, or nil if the field has neither.
+func FieldCodec(mapper *reflectx.Mapper, codecs *db.Codecs, record interface{}, column string) (db.Codec, error) {
+	t := reflectx.Deref(reflect.TypeOf(record))
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	fi, ok := mapper.TypeMap(t).Names[column]
+	if !ok {
+		return nil, nil
+	}
+	return namedCodec(codecs, fi)
+}
+
+// EncryptedCodec returns the codec of the fields tagged with the encrypted
+// option, which uses the key provider of codecs, see db.Codecs.SetKeyProvider.
+func EncryptedCodec(codecs *db.Codecs, deterministic bool) (db.Codec, error) {
+	keys := codecs.KeyProvider()
+	if keys == nil {
+		return nil, db.ErrMissingKeyProvider
+	}
+	return db.NewEncryptedCodec(keys, deterministic), nil
+}
+
+// EncryptedColumns returns the columns mapped to the fields of t that are
+// tagged with the encrypted option, the value of each column is true if it's
+// encrypted deterministically.
+func EncryptedColumns(mapper *reflectx.Mapper, t reflect.Type) map[string]bool {
+	if t == nil {
+		return nil
+	}
+	if t = reflectx.Deref(t); t.Kind() != reflect.Struct {
+		return nil
+	}
+	var columns map[string]bool
+	for _, fi := range mapper.TypeMap(t).Names {
+		mode, ok := fi.Options[optionEncrypted]
+		if !ok || fi.Embedded {
+			continue
+		}
+		if columns == nil {
+			columns = map[string]bool{}
+		}
+		columns[fi.Name] = mode == encryptedDeterministic
+	}
+	return columns
+}
+
+// namedCodec returns the codec named by the codec option of a struct field or
+// the encryption codec if the field has the encrypted option, nil if it has
+// neither.
+func namedCodec(codecs *db.Codecs, fi *reflectx.FieldInfo) (db.Codec, error) {
+	if mode, ok := fi.Options[optionEncrypted]; ok {
+		return EncryptedCodec(codecs, mode == encryptedDeterministic)
+	}
+	if name, ok := fi.Options[optionCodec]; ok {
+		return db.LookupCodec(name)
+	}
+	return nil, nil
+}
+
+// fieldCodec returns the codec of a struct field: the one named by its codec
+// or encrypted options or else the codec of its type.
+func fieldCodec(codecs *db.Codecs, fi *reflectx.FieldInfo) (codec db.Codec, deref bool, err error) {
+	if codec, err := namedCodec(codecs, fi); codec != nil || err != nil {
+		return codec, false, err
+	}
+	codec, deref = typeCodec(codecs, fi.Field.Type)
+	return codec, deref, nil
+}
+
+// encodeValue encodes v with codec, v is dereferenced first if deref is true.
+func encodeValue(codec db.Codec, deref bool, v reflect.Value) (interface{}, error) {
+	if deref {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	return codec.Encode(v.Interface())
+}
+
+// codecScanner decodes a column value into dst with a codec.
+type codecScanner struct {
+	codec db.Codec
+	deref bool
+	dst   reflect.Value
+}
+
+func (s codecScanner) Scan(src interface{}) error {
+	return decodeValue(s.codec, s.deref, src, s.dst)
+}
+
+// decodeValue decodes src into dst, an addressable value. If deref is true dst
+// is a pointer that is set to nil on NULL and allocated otherwise.
+func decodeValue(codec db.Codec, deref bool, src interface{}, dst reflect.Value) error {
+	if !deref {
+		return codec.Decode(src, dst.Addr().Interface())
+	}
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	v := reflect.New(dst.Type().Elem())
+	if err := codec.Decode(src, v.Interface()); err != nil {
+		return err
+	}
+	dst.Set(v)
+	return nil
+}
+
+var _ sql.Scanner = codecScanner{}
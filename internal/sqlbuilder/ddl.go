@@ -0,0 +1,734 @@
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/adapter"
+	"github.com/upper/db/v4/internal/immutable"
+	"github.com/upper/db/v4/internal/sqladapter/exql"
+)
+
+// literalValue converts a Go value into a literal that can be written into a
+// DDL statement, where placeholders are not allowed.
+func (tu *templateWithUtils) literalValue(in interface{}) (exql.Fragment, error) {
+	var s string
+
+	switch v := in.(type) {
+	case *adapter.RawExpr:
+		return &exql.Raw{Value: v.Raw()}, nil
+	case nil:
+		s = "NULL"
+	case bool:
+		if v {
+			s = tu.TrueKeyword
+		} else {
+			s = tu.FalseKeyword
+		}
+	case string:
+		if strings.HasPrefix(tu.ValueQuote, `"`) {
+			quoted := strconv.Quote(v)
+			s = tu.MustCompile(tu.ValueQuote, quoted[1:len(quoted)-1])
+		} else {
+			s = tu.MustCompile(tu.ValueQuote, strings.Replace(v, "'", "''", -1))
+		}
+	case time.Time:
+		s = tu.MustCompile(tu.ValueQuote, v.Format("2006-01-02 15:04:05"))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s = fmt.Sprintf("%d", v)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("upper: unsupported default value of type %T", in)
+	}
+
+	return &exql.Raw{Value: s}, nil
+}
+
+func (tu *templateWithUtils) columnDefinition(name string, columnType db.ColumnType, options []db.ColumnOption) (*exql.ColumnDefinition, error) {
+	opts := db.ColumnOptions{}
+	for _, fn := range options {
+		fn(&opts)
+	}
+
+	column := &exql.ColumnDefinition{
+		Name:      name,
+		Type:      string(columnType),
+		Size:      opts.Size,
+		Precision: opts.Precision,
+		Scale:     opts.Scale,
+		NotNull:   opts.NotNull,
+		Unique:    opts.Unique,
+	}
+
+	if opts.Default != nil {
+		value, err := tu.literalValue(opts.Default)
+		if err != nil {
+			return nil, err
+		}
+		column.Default = value
+	}
+
+	return column, nil
+}
+
+func foreignKeyConstraint(fk db.ForeignKey) *exql.ForeignKeyConstraint {
+	return &exql.ForeignKeyConstraint{
+		Name:              fk.Name,
+		Columns:           fk.Columns,
+		ReferencedTable:   fk.ReferencedTable,
+		ReferencedColumns: fk.ReferencedColumns,
+		OnUpdate:          fk.OnUpdate,
+		OnDelete:          fk.OnDelete,
+	}
+}
+
+type tableCreatorQuery struct {
+	table       string
+	ifNotExists bool
+
+	columns     []exql.Fragment
+	constraints []exql.Fragment
+}
+
+func (tq *tableCreatorQuery) statement() *exql.Statement {
+	return &exql.Statement{
+		Type:        exql.CreateTable,
+		Table:       exql.TableWithName(tq.table),
+		IfNotExists: tq.ifNotExists,
+		Definition: &exql.TableDefinition{
+			Columns:     tq.columns,
+			Constraints: tq.constraints,
+		},
+	}
+}
+
+type tableCreator struct {
+	builder *sqlBuilder
+
+	fn   func(*tableCreatorQuery) error
+	prev *tableCreator
+}
+
+var _ = immutable.Immutable(&tableCreator{})
+
+func (tc *tableCreator) SQL() *sqlBuilder {
+	if tc.prev == nil {
+		return tc.builder
+	}
+	return tc.prev.SQL()
+}
+
+func (tc *tableCreator) frame(fn func(*tableCreatorQuery) error) *tableCreator {
+	return &tableCreator{prev: tc, fn: fn}
+}
+
+func (tc *tableCreator) setTable(table string) *tableCreator {
+	return tc.frame(func(tq *tableCreatorQuery) error {
+		tq.table = table
+		return nil
+	})
+}
+
+func (tc *tableCreator) Column(name string, columnType db.ColumnType, options ...db.ColumnOption) db.TableCreator {
+	return tc.frame(func(tq *tableCreatorQuery) error {
+		column, err := tc.SQL().t.columnDefinition(name, columnType, options)
+		if err != nil {
+			return err
+		}
+		tq.columns = append(tq.columns, column)
+		return nil
+	})
+}
+
+func (tc *tableCreator) PrimaryKey(columns ...string) db.TableCreator {
+	return tc.frame(func(tq *tableCreatorQuery) error {
+		tq.constraints = append(tq.constraints, &exql.PrimaryKeyConstraint{Columns: columns})
+		return nil
+	})
+}
+
+func (tc *tableCreator) Unique(name string, columns ...string) db.TableCreator {
+	return tc.frame(func(tq *tableCreatorQuery) error {
+		tq.constraints = append(tq.constraints, &exql.UniqueConstraint{Name: name, Columns: columns})
+		return nil
+	})
+}
+
+func (tc *tableCreator) ForeignKey(fk db.ForeignKey) db.TableCreator {
+	return tc.frame(func(tq *tableCreatorQuery) error {
+		tq.constraints = append(tq.constraints, foreignKeyConstraint(fk))
+		return nil
+	})
+}
+
+func (tc *tableCreator) IfNotExists() db.TableCreator {
+	return tc.frame(func(tq *tableCreatorQuery) error {
+		tq.ifNotExists = true
+		return nil
+	})
+}
+
+func (tc *tableCreator) statement() (*exql.Statement, error) {
+	tq, err := immutable.FastForward(tc)
+	if err != nil {
+		return nil, err
+	}
+	return tq.(*tableCreatorQuery).statement(), nil
+}
+
+func (tc *tableCreator) Exec() (sql.Result, error) {
+	return tc.ExecContext(tc.SQL().sess.Context())
+}
+
+func (tc *tableCreator) ExecContext(ctx context.Context) (sql.Result, error) {
+	stmt, err := tc.statement()
+	if err != nil {
+		return nil, err
+	}
+	return tc.SQL().sess.StatementExec(ctx, stmt)
+}
+
+func (tc *tableCreator) Compile() (string, error) {
+	stmt, err := tc.statement()
+	if err != nil {
+		return "", err
+	}
+	return stmt.Compile(tc.SQL().t.Template)
+}
+
+func (tc *tableCreator) String() string {
+	s, err := tc.Compile()
+	if err != nil {
+		panic(err.Error())
+	}
+	return prepareQueryForDisplay(s)
+}
+
+func (tc *tableCreator) Prev() immutable.Immutable {
+	if tc == nil {
+		return nil
+	}
+	return tc.prev
+}
+
+func (tc *tableCreator) Fn(in interface{}) error {
+	if tc.fn == nil {
+		return nil
+	}
+	return tc.fn(in.(*tableCreatorQuery))
+}
+
+func (tc *tableCreator) Base() interface{} {
+	return &tableCreatorQuery{}
+}
+
+type tableAltererQuery struct {
+	table       string
+	alterations []*exql.Alteration
+}
+
+func (aq *tableAltererQuery) add(alteration *exql.Alteration) {
+	alteration.Table = aq.table
+	aq.alterations = append(aq.alterations, alteration)
+}
+
+func (aq *tableAltererQuery) statements() []*exql.Statement {
+	stmts := make([]*exql.Statement, 0, len(aq.alterations))
+	for i := range aq.alterations {
+		stmts = append(stmts, &exql.Statement{
+			Type:       exql.AlterTable,
+			Table:      exql.TableWithName(aq.table),
+			Definition: aq.alterations[i],
+		})
+	}
+	return stmts
+}
+
+type tableAlterer struct {
+	builder *sqlBuilder
+
+	fn   func(*tableAltererQuery) error
+	prev *tableAlterer
+}
+
+var _ = immutable.Immutable(&tableAlterer{})
+
+func (ta *tableAlterer) SQL() *sqlBuilder {
+	if ta.prev == nil {
+		return ta.builder
+	}
+	return ta.prev.SQL()
+}
+
+func (ta *tableAlterer) frame(fn func(*tableAltererQuery) error) *tableAlterer {
+	return &tableAlterer{prev: ta, fn: fn}
+}
+
+func (ta *tableAlterer) setTable(table string) *tableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		aq.table = table
+		return nil
+	})
+}
+
+func (ta *tableAlterer) AddColumn(name string, columnType db.ColumnType, options ...db.ColumnOption) db.TableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		column, err := ta.SQL().t.columnDefinition(name, columnType, options)
+		if err != nil {
+			return err
+		}
+		aq.add(&exql.Alteration{Type: exql.AddColumn, Column: column})
+		return nil
+	})
+}
+
+func (ta *tableAlterer) DropColumn(name string) db.TableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		aq.add(&exql.Alteration{Type: exql.DropColumn, Name: name})
+		return nil
+	})
+}
+
+func (ta *tableAlterer) RenameColumn(from string, to string) db.TableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		aq.add(&exql.Alteration{Type: exql.RenameColumn, Name: from, NewName: to})
+		return nil
+	})
+}
+
+func (ta *tableAlterer) RenameTo(name string) db.TableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		aq.add(&exql.Alteration{Type: exql.RenameTable, NewName: name})
+		return nil
+	})
+}
+
+func (ta *tableAlterer) AddUnique(name string, columns ...string) db.TableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		aq.add(&exql.Alteration{
+			Type:       exql.AddConstraint,
+			Constraint: &exql.UniqueConstraint{Name: name, Columns: columns},
+		})
+		return nil
+	})
+}
+
+func (ta *tableAlterer) AddForeignKey(fk db.ForeignKey) db.TableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		aq.add(&exql.Alteration{
+			Type:       exql.AddConstraint,
+			Constraint: foreignKeyConstraint(fk),
+		})
+		return nil
+	})
+}
+
+func (ta *tableAlterer) DropConstraint(name string) db.TableAlterer {
+	return ta.frame(func(aq *tableAltererQuery) error {
+		aq.add(&exql.Alteration{Type: exql.DropConstraint, Name: name})
+		return nil
+	})
+}
+
+func (ta *tableAlterer) statements() ([]*exql.Statement, error) {
+	aq, err := immutable.FastForward(ta)
+	if err != nil {
+		return nil, err
+	}
+	return aq.(*tableAltererQuery).statements(), nil
+}
+
+func (ta *tableAlterer) Exec() (sql.Result, error) {
+	return ta.ExecContext(ta.SQL().sess.Context())
+}
+
+func (ta *tableAlterer) ExecContext(ctx context.Context) (res sql.Result, err error) {
+	stmts, err := ta.statements()
+	if err != nil {
+		return nil, err
+	}
+	for i := range stmts {
+		if res, err = ta.SQL().sess.StatementExec(ctx, stmts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ta *tableAlterer) Compile() (string, error) {
+	stmts, err := ta.statements()
+	if err != nil {
+		return "", err
+	}
+	out := make([]string, 0, len(stmts))
+	for i := range stmts {
+		s, err := stmts[i].Compile(ta.SQL().t.Template)
+		if err != nil {
+			return "", err
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, ";\n"), nil
+}
+
+func (ta *tableAlterer) String() string {
+	s, err := ta.Compile()
+	if err != nil {
+		panic(err.Error())
+	}
+	return prepareQueryForDisplay(s)
+}
+
+func (ta *tableAlterer) Prev() immutable.Immutable {
+	if ta == nil {
+		return nil
+	}
+	return ta.prev
+}
+
+func (ta *tableAlterer) Fn(in interface{}) error {
+	if ta.fn == nil {
+		return nil
+	}
+	return ta.fn(in.(*tableAltererQuery))
+}
+
+func (ta *tableAlterer) Base() interface{} {
+	return &tableAltererQuery{}
+}
+
+type tableDropperQuery struct {
+	table    string
+	ifExists bool
+}
+
+func (dq *tableDropperQuery) statement() *exql.Statement {
+	return &exql.Statement{
+		Type:     exql.DropTable,
+		Table:    exql.TableWithName(dq.table),
+		IfExists: dq.ifExists,
+	}
+}
+
+type tableDropper struct {
+	builder *sqlBuilder
+
+	fn   func(*tableDropperQuery) error
+	prev *tableDropper
+}
+
+var _ = immutable.Immutable(&tableDropper{})
+
+func (td *tableDropper) SQL() *sqlBuilder {
+	if td.prev == nil {
+		return td.builder
+	}
+	return td.prev.SQL()
+}
+
+func (td *tableDropper) frame(fn func(*tableDropperQuery) error) *tableDropper {
+	return &tableDropper{prev: td, fn: fn}
+}
+
+func (td *tableDropper) setTable(table string) *tableDropper {
+	return td.frame(func(dq *tableDropperQuery) error {
+		dq.table = table
+		return nil
+	})
+}
+
+func (td *tableDropper) IfExists() db.TableDropper {
+	return td.frame(func(dq *tableDropperQuery) error {
+		dq.ifExists = true
+		return nil
+	})
+}
+
+func (td *tableDropper) statement() (*exql.Statement, error) {
+	dq, err := immutable.FastForward(td)
+	if err != nil {
+		return nil, err
+	}
+	return dq.(*tableDropperQuery).statement(), nil
+}
+
+func (td *tableDropper) Exec() (sql.Result, error) {
+	return td.ExecContext(td.SQL().sess.Context())
+}
+
+func (td *tableDropper) ExecContext(ctx context.Context) (sql.Result, error) {
+	stmt, err := td.statement()
+	if err != nil {
+		return nil, err
+	}
+	return td.SQL().sess.StatementExec(ctx, stmt)
+}
+
+func (td *tableDropper) Compile() (string, error) {
+	stmt, err := td.statement()
+	if err != nil {
+		return "", err
+	}
+	return stmt.Compile(td.SQL().t.Template)
+}
+
+func (td *tableDropper) String() string {
+	s, err := td.Compile()
+	if err != nil {
+		panic(err.Error())
+	}
+	return prepareQueryForDisplay(s)
+}
+
+func (td *tableDropper) Prev() immutable.Immutable {
+	if td == nil {
+		return nil
+	}
+	return td.prev
+}
+
+func (td *tableDropper) Fn(in interface{}) error {
+	if td.fn == nil {
+		return nil
+	}
+	return td.fn(in.(*tableDropperQuery))
+}
+
+func (td *tableDropper) Base() interface{} {
+	return &tableDropperQuery{}
+}
+
+type indexQuery struct {
+	name        string
+	table       string
+	columns     []string
+	unique      bool
+	ifExists    bool
+	ifNotExists bool
+}
+
+func (iq *indexQuery) statement(t exql.Type) *exql.Statement {
+	stmt := &exql.Statement{
+		Type:        t,
+		Name:        exql.ColumnWithName(iq.name),
+		Unique:      iq.unique,
+		IfExists:    iq.ifExists,
+		IfNotExists: iq.ifNotExists,
+	}
+	if iq.table != "" {
+		stmt.Table = exql.TableWithName(iq.table)
+	}
+	if len(iq.columns) > 0 {
+		columns := make([]exql.Fragment, len(iq.columns))
+		for i := range iq.columns {
+			columns[i] = exql.ColumnWithName(iq.columns[i])
+		}
+		stmt.Columns = exql.JoinColumns(columns...)
+	}
+	return stmt
+}
+
+type indexCreator struct {
+	builder *sqlBuilder
+
+	fn   func(*indexQuery) error
+	prev *indexCreator
+}
+
+var _ = immutable.Immutable(&indexCreator{})
+
+func (ic *indexCreator) SQL() *sqlBuilder {
+	if ic.prev == nil {
+		return ic.builder
+	}
+	return ic.prev.SQL()
+}
+
+func (ic *indexCreator) frame(fn func(*indexQuery) error) *indexCreator {
+	return &indexCreator{prev: ic, fn: fn}
+}
+
+func (ic *indexCreator) setName(name string) *indexCreator {
+	return ic.frame(func(iq *indexQuery) error {
+		iq.name = name
+		return nil
+	})
+}
+
+func (ic *indexCreator) On(table string, columns ...string) db.IndexCreator {
+	return ic.frame(func(iq *indexQuery) error {
+		iq.table, iq.columns = table, columns
+		return nil
+	})
+}
+
+func (ic *indexCreator) Unique() db.IndexCreator {
+	return ic.frame(func(iq *indexQuery) error {
+		iq.unique = true
+		return nil
+	})
+}
+
+func (ic *indexCreator) IfNotExists() db.IndexCreator {
+	return ic.frame(func(iq *indexQuery) error {
+		iq.ifNotExists = true
+		return nil
+	})
+}
+
+func (ic *indexCreator) statement() (*exql.Statement, error) {
+	iq, err := immutable.FastForward(ic)
+	if err != nil {
+		return nil, err
+	}
+	return iq.(*indexQuery).statement(exql.CreateIndex), nil
+}
+
+func (ic *indexCreator) Exec() (sql.Result, error) {
+	return ic.ExecContext(ic.SQL().sess.Context())
+}
+
+func (ic *indexCreator) ExecContext(ctx context.Context) (sql.Result, error) {
+	stmt, err := ic.statement()
+	if err != nil {
+		return nil, err
+	}
+	return ic.SQL().sess.StatementExec(ctx, stmt)
+}
+
+func (ic *indexCreator) Compile() (string, error) {
+	stmt, err := ic.statement()
+	if err != nil {
+		return "", err
+	}
+	return stmt.Compile(ic.SQL().t.Template)
+}
+
+func (ic *indexCreator) String() string {
+	s, err := ic.Compile()
+	if err != nil {
+		panic(err.Error())
+	}
+	return prepareQueryForDisplay(s)
+}
+
+func (ic *indexCreator) Prev() immutable.Immutable {
+	if ic == nil {
+		return nil
+	}
+	return ic.prev
+}
+
+func (ic *indexCreator) Fn(in interface{}) error {
+	if ic.fn == nil {
+		return nil
+	}
+	return ic.fn(in.(*indexQuery))
+}
+
+func (ic *indexCreator) Base() interface{} {
+	return &indexQuery{}
+}
+
+type indexDropper struct {
+	builder *sqlBuilder
+
+	fn   func(*indexQuery) error
+	prev *indexDropper
+}
+
+var _ = immutable.Immutable(&indexDropper{})
+
+func (id *indexDropper) SQL() *sqlBuilder {
+	if id.prev == nil {
+		return id.builder
+	}
+	return id.prev.SQL()
+}
+
+func (id *indexDropper) frame(fn func(*indexQuery) error) *indexDropper {
+	return &indexDropper{prev: id, fn: fn}
+}
+
+func (id *indexDropper) setName(name string) *indexDropper {
+	return id.frame(func(iq *indexQuery) error {
+		iq.name = name
+		return nil
+	})
+}
+
+func (id *indexDropper) On(table string) db.IndexDropper {
+	return id.frame(func(iq *indexQuery) error {
+		iq.table = table
+		return nil
+	})
+}
+
+func (id *indexDropper) IfExists() db.IndexDropper {
+	return id.frame(func(iq *indexQuery) error {
+		iq.ifExists = true
+		return nil
+	})
+}
+
+func (id *indexDropper) statement() (*exql.Statement, error) {
+	iq, err := immutable.FastForward(id)
+	if err != nil {
+		return nil, err
+	}
+	return iq.(*indexQuery).statement(exql.DropIndex), nil
+}
+
+func (id *indexDropper) Exec() (sql.Result, error) {
+	return id.ExecContext(id.SQL().sess.Context())
+}
+
+func (id *indexDropper) ExecContext(ctx context.Context) (sql.Result, error) {
+	stmt, err := id.statement()
+	if err != nil {
+		return nil, err
+	}
+	return id.SQL().sess.StatementExec(ctx, stmt)
+}
+
+func (id *indexDropper) Compile() (string, error) {
+	stmt, err := id.statement()
+	if err != nil {
+		return "", err
+	}
+	return stmt.Compile(id.SQL().t.Template)
+}
+
+func (id *indexDropper) String() string {
+	s, err := id.Compile()
+	if err != nil {
+		panic(err.Error())
+	}
+	return prepareQueryForDisplay(s)
+}
+
+func (id *indexDropper) Prev() immutable.Immutable {
+	if id == nil {
+		return nil
+	}
+	return id.prev
+}
+
+func (id *indexDropper) Fn(in interface{}) error {
+	if id.fn == nil {
+		return nil
+	}
+	return id.fn(in.(*indexQuery))
+}
+
+func (id *indexDropper) Base() interface{} {
+	return &indexQuery{}
+}
@@ -20,6 +20,12 @@ type deleterQuery struct {
 }
 
 func (dq *deleterQuery) and(b *sqlBuilder, terms ...interface{}) error {
+	codecs := CodecsOf(b.sess)
+	terms, err := encryptConds(codecs, encryptedColumns(codecs, dq.table), terms)
+	if err != nil {
+		return err
+	}
+
 	where, whereArgs := b.t.toWhereWithArguments(terms)
 
 	if dq.where == nil {
@@ -0,0 +1,224 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/adapter"
+	"github.com/upper/db/v4/internal/sqladapter/exql"
+)
+
+// encryptedColumns returns the encrypted columns declared by codecs for the
+// given tables, see db.Codecs.EncryptColumns. Tables may have an alias, the
+// columns are keyed by their names and by their names qualified with the name
+// or the alias of their table.
+func encryptedColumns(codecs *db.Codecs, tables ...string) map[string]bool {
+	var columns map[string]bool
+	for _, table := range tables {
+		fields := strings.Fields(table)
+		if len(fields) == 0 {
+			continue
+		}
+		declared := codecs.EncryptedColumns(fields[0])
+		if len(declared) == 0 {
+			continue
+		}
+		if columns == nil {
+			columns = map[string]bool{}
+		}
+		for column, deterministic := range declared {
+			columns[column] = deterministic
+			columns[fields[0]+"."+column] = deterministic
+			if len(fields) > 1 {
+				columns[fields[len(fields)-1]+"."+column] = deterministic
+			}
+		}
+	}
+	return columns
+}
+
+// tableNames returns the names of the tables given as column fragments,
+// tables given as raw fragments are left out.
+func tableNames(tables *exql.Columns) []string {
+	if tables == nil {
+		return nil
+	}
+	var names []string
+	for _, fragment := range tables.Columns {
+		if column, ok := fragment.(*exql.Column); ok {
+			if name, ok := column.Name.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// encryptValue encrypts the value of an encrypted column.
+func encryptValue(codecs *db.Codecs, deterministic bool, value interface{}) (interface{}, error) {
+	codec, err := EncryptedCodec(codecs, deterministic)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Encode(value)
+}
+
+// pinnedKey is a key provider that encrypts values with the key with the given
+// id instead of the current one.
+type pinnedKey struct {
+	db.KeyProvider
+	id string
+}
+
+func (k pinnedKey) CurrentKey() (string, []byte, error) {
+	key, err := k.Key(k.id)
+	return k.id, key, err
+}
+
+// encryptMatch returns the value a deterministically encrypted column is
+// compared with to match the given value: the value encrypted with the current
+// key, or a comparison with the value encrypted with each key if values may be
+// encrypted with more than one, see db.KeyProvider.KeyIDs.
+func encryptMatch(codecs *db.Codecs, value interface{}) (interface{}, error) {
+	keys := codecs.KeyProvider()
+	if keys == nil {
+		return nil, db.ErrMissingKeyProvider
+	}
+	current, _, err := keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	ids, err := keys.KeyIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(ids)+1)
+	matches := make([]interface{}, 0, len(ids)+1)
+	for _, id := range append([]string{current}, ids...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		match, err := db.NewEncryptedCodec(pinnedKey{KeyProvider: keys, id: id}, true).Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return db.In(matches...), nil
+}
+
+// encryptConds rewrites the conditions on the given encrypted columns: values
+// compared for equality with deterministically encrypted columns are
+// encrypted, NULL checks are kept as they are and any other condition on an
+// encrypted column is refused with db.ErrEncryptedCondition. Conditions that
+// are not db.Cond, db.And or db.Or are not inspected.
+func encryptConds(codecs *db.Codecs, columns map[string]bool, conds []interface{}) ([]interface{}, error) {
+	if len(columns) == 0 {
+		return conds, nil
+	}
+	filtered := make([]interface{}, len(conds))
+	for i := range conds {
+		expr, ok := conds[i].(db.LogicalExpr)
+		if !ok {
+			filtered[i] = conds[i]
+			continue
+		}
+		expr, err := encryptExpr(codecs, columns, expr)
+		if err != nil {
+			return nil, err
+		}
+		filtered[i] = expr
+	}
+	return filtered, nil
+}
+
+func encryptExpr(codecs *db.Codecs, columns map[string]bool, expr db.LogicalExpr) (db.LogicalExpr, error) {
+	switch expr := expr.(type) {
+	case db.Cond:
+		return encryptCond(codecs, columns, expr)
+	case *db.AndExpr, *db.OrExpr:
+		exprs := expr.Expressions()
+		encrypted := make([]db.LogicalExpr, len(exprs))
+		for i := range exprs {
+			var err error
+			if encrypted[i], err = encryptExpr(codecs, columns, exprs[i]); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := expr.(*db.OrExpr); ok {
+			return db.Or(encrypted...), nil
+		}
+		return db.And(encrypted...), nil
+	}
+	return expr, nil
+}
+
+func encryptCond(codecs *db.Codecs, columns map[string]bool, cond db.Cond) (db.Cond, error) {
+	var encrypted db.Cond
+	for key, value := range cond {
+		name, ok := key.(string)
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(name)
+		if len(fields) == 0 {
+			continue
+		}
+		deterministic, ok := columns[fields[0]]
+		if !ok || isNullCheck(value) {
+			continue
+		}
+		if !deterministic || len(fields) > 2 || (len(fields) == 2 && fields[1] != "=") {
+			return nil, db.ErrEncryptedCondition
+		}
+
+		if cmp, ok := value.(*db.Comparison); ok {
+			if cmp.Operator() != adapter.ComparisonOperatorEqual {
+				return nil, db.ErrEncryptedCondition
+			}
+			value = cmp.Value()
+		}
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Slice, reflect.Array:
+			if _, ok := value.([]byte); !ok {
+				return nil, db.ErrEncryptedCondition
+			}
+		}
+
+		value, err := encryptMatch(codecs, value)
+		if err != nil {
+			return nil, err
+		}
+		if encrypted == nil {
+			encrypted = make(db.Cond, len(cond))
+			for k, v := range cond {
+				encrypted[k] = v
+			}
+		}
+		delete(encrypted, key)
+		encrypted[fields[0]] = value
+	}
+	if encrypted == nil {
+		return cond, nil
+	}
+	return encrypted, nil
+}
+
+// isNullCheck returns true if value compares a column with NULL.
+func isNullCheck(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if cmp, ok := value.(*db.Comparison); ok {
+		op := cmp.Operator()
+		return cmp.Value() == nil &&
+			(op == adapter.ComparisonOperatorIs || op == adapter.ComparisonOperatorIsNot)
+	}
+	return false
+}
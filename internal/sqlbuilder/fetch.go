@@ -23,6 +23,8 @@ package sqlbuilder
 
 import (
 	"reflect"
+	"strings"
+	"time"
 
 	"database/sql"
 	"database/sql/driver"
@@ -43,6 +45,93 @@ type valueConverter interface {
 
 var Mapper = reflectx.NewMapper("db")
 
+// NewMapper returns a struct mapper that maps fields to columns as defined by
+// the given field mapper.
+func NewMapper(m db.FieldMapper) *reflectx.Mapper {
+	tagNames := []string{"db"}
+	if m.TagName != "" && m.TagName != "db" {
+		tagNames = []string{m.TagName, "db"}
+	}
+	return reflectx.NewMapperTagsFunc(tagNames, m.Naming)
+}
+
+// MapperOf returns the struct mapper used by the given session, sessions
+// created with db.WithFieldMapper have their own mapper.
+func MapperOf(sess interface{}) *reflectx.Mapper {
+	if s, ok := sess.(interface {
+		Mapper() *reflectx.Mapper
+	}); ok {
+		if mapper := s.Mapper(); mapper != nil {
+			return mapper
+		}
+	}
+	return Mapper
+}
+
+// isStrict returns true if the given session requires result columns to match
+// the fields of the destination struct, see db.WithStrictScan.
+func isStrict(sess interface{}) bool {
+	if s, ok := sess.(interface {
+		StrictScan() bool
+	}); ok {
+		return s.StrictScan()
+	}
+	return false
+}
+
+// checkColumns returns a *db.ScanError if the given columns don't match the
+// fields of itemT, which is expected to be a struct or a pointer to struct.
+func checkColumns(mapper *reflectx.Mapper, itemT reflect.Type, columns []string) error {
+	objT := reflectx.Deref(itemT)
+	if objT.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typeMap := mapper.TypeMap(objT)
+
+	var unmapped, missing []string
+	for _, column := range columns {
+		if _, ok := fieldByColumn(typeMap, column); !ok {
+			unmapped = append(unmapped, column)
+		}
+	}
+
+	for _, fi := range typeMap.Index {
+		if fi.Embedded || fi.Name == "" {
+			continue
+		}
+		// Only top level fields are required, the fields of nested structs
+		// are populated along with their parent.
+		if fi.Parent != typeMap.Tree && !fi.Parent.Embedded {
+			continue
+		}
+		if !hasColumn(columns, fi.Path) {
+			missing = append(missing, fi.Path)
+		}
+	}
+
+	if len(unmapped) == 0 && len(missing) == 0 {
+		return nil
+	}
+	return &db.ScanError{
+		Type:            objT.String(),
+		UnmappedColumns: unmapped,
+		MissingFields:   missing,
+	}
+}
+
+// hasColumn returns true if path is one of the given columns or the parent of
+// one of them.
+func hasColumn(columns []string, path string) bool {
+	for _, column := range columns {
+		column = strings.Replace(column, "__", ".", -1)
+		if column == path || strings.HasPrefix(column, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
 // fetchRow receives a *sql.Rows value and tries to map all the rows into a
 // single struct given by the pointer `dst`.
 func fetchRow(iter *iterator, dst interface{}) error {
@@ -75,6 +164,12 @@ func fetchRow(iter *iterator, dst interface{}) error {
 	}
 
 	itemT := itemV.Type()
+	if isStrict(iter.sess) {
+		if err := checkColumns(MapperOf(iter.sess), itemT, columns); err != nil {
+			return err
+		}
+	}
+
 	item, err := fetchResult(iter, itemT, columns)
 	if err != nil {
 		return err
@@ -119,9 +214,18 @@ func fetchRows(iter *iterator, dst interface{}) error {
 	slicev := dstv.Elem()
 	itemT := slicev.Type().Elem()
 
+	strict := isStrict(iter.sess)
+
 	reset(dst)
 
 	for rows.Next() {
+		if strict {
+			if err := checkColumns(MapperOf(iter.sess), itemT, columns); err != nil {
+				return err
+			}
+			strict = false
+		}
+
 		item, err := fetchResult(iter, itemT, columns)
 		if err != nil {
 			return err
@@ -138,12 +242,136 @@ func fetchRows(iter *iterator, dst interface{}) error {
 	return rows.Err()
 }
 
+var (
+	scannerType     = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*db.Unmarshaler)(nil)).Elem()
+	timeType        = reflect.TypeOf(time.Time{})
+
+	valueConverterType = reflect.TypeOf((*valueConverter)(nil)).Elem()
+)
+
+// isScalar returns true if values of type t are scanned from a single column
+// instead of being mapped from all the columns of a row.
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	ptrT := reflect.PtrTo(t)
+	if t == timeType || ptrT.Implements(scannerType) || ptrT.Implements(unmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Ptr:
+		return false
+	}
+	return true
+}
+
+// fetchScalar scans the only column of the current row into a new value of
+// type itemT.
+func fetchScalar(iter *iterator, itemT reflect.Type, columns []string) (reflect.Value, error) {
+	if len(columns) != 1 {
+		return reflect.Value{}, db.ErrExpectingSingleColumn
+	}
+
+	if codec, deref := typeCodec(CodecsOf(iter.sess), itemT); codec != nil {
+		item := reflect.New(itemT)
+		if err := iter.cursor.Scan(codecScanner{codec: codec, deref: deref, dst: item.Elem()}); err != nil {
+			return item, err
+		}
+		if itemT.Kind() == reflect.Ptr {
+			return item.Elem(), nil
+		}
+		return item, nil
+	}
+
+	if itemT.Kind() == reflect.Ptr {
+		// Pointers are set to nil on NULL.
+		item := reflect.New(itemT)
+		if err := iter.cursor.Scan(item.Interface()); err != nil {
+			return item, err
+		}
+		return item.Elem(), nil
+	}
+
+	item := reflect.New(itemT)
+	value := item.Interface()
+	if unmarshaler, ok := value.(db.Unmarshaler); ok {
+		value = scanner{unmarshaler}
+	} else if converter, ok := iter.sess.(sessValueConverter); ok {
+		value = converter.ConvertValue(value)
+	}
+	if err := iter.cursor.Scan(value); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// nullableColumn is a column of a field nested in a pointer to struct, holder
+// is a pointer to a pointer to the type of the field that is nil after
+// scanning a NULL. Fields with a codec are scanned into a pointer to an empty
+// interface that is decoded afterwards.
+type nullableColumn struct {
+	fi     *reflectx.FieldInfo
+	holder reflect.Value
+
+	codec db.Codec
+	deref bool
+}
+
+// fieldByColumn returns the field mapped to the given column. Columns of
+// nested struct fields are given as paths, like "author.name" or
+// "author__name".
+func fieldByColumn(typeMap *reflectx.StructMap, column string) (*reflectx.FieldInfo, bool) {
+	fi := typeMap.GetByPath(column)
+	if fi == nil && strings.Contains(column, "__") {
+		fi = typeMap.GetByPath(strings.Replace(column, "__", ".", -1))
+	}
+	if fi == nil || fi.Embedded || fi.Name == "" {
+		return nil, false
+	}
+	return fi, true
+}
+
+// hasNullableParent returns true if fi is nested in a field that is a pointer
+// to struct.
+func hasNullableParent(typeMap *reflectx.StructMap, fi *reflectx.FieldInfo) bool {
+	for p := fi.Parent; p != nil && p != typeMap.Tree; p = p.Parent {
+		if !p.Embedded && p.Field.Type.Kind() == reflect.Ptr {
+			return true
+		}
+	}
+	return false
+}
+
+// nullableHolder returns a new pointer to a pointer to the type of the given
+// field, or false if values of that type need to be converted before being
+// scanned.
+func nullableHolder(iter *iterator, fi *reflectx.FieldInfo) (reflect.Value, bool) {
+	fieldT := fi.Field.Type
+	if fieldT.Implements(valueConverterType) || reflect.PtrTo(fieldT).Implements(unmarshalerType) {
+		return reflect.Value{}, false
+	}
+	if converter, ok := iter.sess.(sessValueConverter); ok {
+		value := reflect.New(fieldT)
+		converted := reflect.ValueOf(converter.ConvertValue(value.Interface()))
+		if converted.Kind() != reflect.Ptr || converted.Pointer() != value.Pointer() {
+			return reflect.Value{}, false
+		}
+	}
+	return reflect.New(reflect.PtrTo(fieldT)), true
+}
+
 func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.Value, error) {
 
 	var item reflect.Value
 	var err error
 	rows := iter.cursor
 
+	if codec, _ := typeCodec(CodecsOf(iter.sess), itemT); codec != nil || isScalar(itemT) {
+		return fetchScalar(iter, itemT, columns)
+	}
+
 	objT := itemT
 
 	switch objT.Kind() {
@@ -165,11 +393,17 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 	case reflect.Struct:
 
 		values := make([]interface{}, len(columns))
-		typeMap := Mapper.TypeMap(itemT)
-		fieldMap := typeMap.Names
+		typeMap := MapperOf(iter.sess).TypeMap(itemT)
+
+		// Columns of fields nested in pointers to structs are scanned
+		// separately, so those pointers are left nil when all their columns
+		// are NULL.
+		var nullable []nullableColumn
+
+		codecs := CodecsOf(iter.sess)
 
 		for i, k := range columns {
-			fi, ok := fieldMap[k]
+			fi, ok := fieldByColumn(typeMap, k)
 			if !ok {
 				values[i] = new(interface{})
 				continue
@@ -180,8 +414,32 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 				return item, errDeprecatedJSONBTag
 			}
 
+			codec, deref, err := fieldCodec(codecs, fi)
+			if err != nil {
+				return item, err
+			}
+
+			if hasNullableParent(typeMap, fi) {
+				if codec != nil {
+					holder := reflect.New(reflect.TypeOf((*interface{})(nil)).Elem())
+					values[i] = holder.Interface()
+					nullable = append(nullable, nullableColumn{fi: fi, holder: holder, codec: codec, deref: deref})
+					continue
+				}
+				if holder, ok := nullableHolder(iter, fi); ok {
+					values[i] = holder.Interface()
+					nullable = append(nullable, nullableColumn{fi: fi, holder: holder})
+					continue
+				}
+			}
+
 			f := reflectx.FieldByIndexes(item, fi.Index)
 
+			if codec != nil {
+				values[i] = codecScanner{codec: codec, deref: deref, dst: f}
+				continue
+			}
+
 			// TODO: type switch + scanner
 
 			if w, ok := f.Interface().(valueConverter); ok {
@@ -208,6 +466,20 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 			return item, err
 		}
 
+		for _, column := range nullable {
+			if column.holder.Elem().IsNil() {
+				continue
+			}
+			f := reflectx.FieldByIndexes(item, column.fi.Index)
+			if column.codec != nil {
+				if err := decodeValue(column.codec, column.deref, column.holder.Elem().Interface(), f); err != nil {
+					return item, err
+				}
+				continue
+			}
+			f.Set(column.holder.Elem().Elem())
+		}
+
 	case reflect.Map:
 
 		columns, err := rows.Columns()
@@ -228,6 +500,17 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 			return item, err
 		}
 
+		if itemT.Elem().Kind() == reflect.Interface {
+			kinds, err := iter.columnKinds()
+			if err != nil {
+				return item, err
+			}
+			for i := range values {
+				v := values[i].(*interface{})
+				*v = normalizeValue(kinds[i], *v)
+			}
+		}
+
 		for i, column := range columns {
 			item.SetMapIndex(reflect.ValueOf(column), reflect.Indirect(reflect.ValueOf(values[i])))
 		}
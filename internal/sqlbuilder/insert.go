@@ -7,6 +7,7 @@ import (
 
 	"github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/immutable"
+	"github.com/upper/db/v4/internal/reflectx"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
 )
 
@@ -14,19 +15,20 @@ type inserterQuery struct {
 	table          string
 	enqueuedValues [][]interface{}
 	returning      []exql.Fragment
+	onConflict     *exql.OnConflict
 	columns        []exql.Fragment
 	values         []*exql.Values
 	arguments      []interface{}
 	amendFn        func(string) string
 }
 
-func (iq *inserterQuery) processValues() ([]*exql.Values, []interface{}, error) {
+func (iq *inserterQuery) processValues(mapper *reflectx.Mapper, codecs *db.Codecs) ([]*exql.Values, []interface{}, error) {
 	var values []*exql.Values
 	var arguments []interface{}
 
-	var mapOptions *MapOptions
+	mapOptions := &MapOptions{Mapper: mapper, Codecs: codecs, Table: iq.table}
 	if len(iq.enqueuedValues) > 1 {
-		mapOptions = &MapOptions{IncludeZeroed: true, IncludeNil: true}
+		mapOptions = &MapOptions{IncludeZeroed: true, IncludeNil: true, Mapper: mapper, Codecs: codecs, Table: iq.table}
 	}
 
 	for _, enqueuedValue := range iq.enqueuedValues {
@@ -87,6 +89,10 @@ func (iq *inserterQuery) statement() *exql.Statement {
 		stmt.Returning = exql.ReturningColumns(iq.returning...)
 	}
 
+	if iq.onConflict != nil {
+		stmt.OnConflict = iq.onConflict
+	}
+
 	stmt.SetAmendment(iq.amendFn)
 
 	return stmt
@@ -143,6 +149,16 @@ func (ins *inserter) Arguments() []interface{} {
 	return iq.arguments
 }
 
+func (ins *inserter) OnConflictUpdate(columns []string, update ...string) db.Inserter {
+	return ins.frame(func(iq *inserterQuery) error {
+		if ins.template().OnConflictLayout == "" {
+			return db.ErrNotSupportedByAdapter
+		}
+		iq.onConflict = &exql.OnConflict{Columns: columns, Update: update}
+		return nil
+	})
+}
+
 func (ins *inserter) Returning(columns ...string) db.Inserter {
 	return ins.frame(func(iq *inserterQuery) error {
 		columnsToFragments(&iq.returning, columns)
@@ -204,7 +220,7 @@ func (ins *inserter) Iterator() db.Iterator {
 
 func (ins *inserter) IteratorContext(ctx context.Context) db.Iterator {
 	rows, err := ins.QueryContext(ctx)
-	return &iterator{ins.SQL().sess, rows, err}
+	return &iterator{sess: ins.SQL().sess, cursor: rows, err: err}
 }
 
 func (ins *inserter) Into(table string) db.Inserter {
@@ -242,7 +258,7 @@ func (ins *inserter) build() (*inserterQuery, error) {
 		return nil, err
 	}
 	ret := iq.(*inserterQuery)
-	ret.values, ret.arguments, err = ret.processValues()
+	ret.values, ret.arguments, err = ret.processValues(MapperOf(ins.SQL().sess), CodecsOf(ins.SQL().sess))
 	if err != nil {
 		return nil, err
 	}
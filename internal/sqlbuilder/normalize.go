@@ -0,0 +1,348 @@
+package sqlbuilder
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	db "github.com/upper/db/v4"
+)
+
+// columnKind is the kind of Go value the values of a column are normalized
+// to when scanned into a map[string]interface{}.
+type columnKind uint8
+
+const (
+	columnKindUnknown columnKind = iota
+	columnKindString
+	columnKindInt
+	columnKindFloat
+	columnKindBool
+	columnKindTime
+	columnKindDecimal
+	columnKindJSON
+	columnKindBytes
+)
+
+// columnKinds maps database type names, as reported by
+// sql.ColumnType.DatabaseTypeName, to column kinds.
+var columnKinds = map[string]columnKind{
+	"CHAR":       columnKindString,
+	"CHARACTER":  columnKindString,
+	"VARCHAR":    columnKindString,
+	"NCHAR":      columnKindString,
+	"NVARCHAR":   columnKindString,
+	"TEXT":       columnKindString,
+	"NTEXT":      columnKindString,
+	"TINYTEXT":   columnKindString,
+	"MEDIUMTEXT": columnKindString,
+	"LONGTEXT":   columnKindString,
+	"CITEXT":     columnKindString,
+	"STRING":     columnKindString,
+	"UUID":       columnKindString,
+	"ENUM":       columnKindString,
+	"SET":        columnKindString,
+	"XML":        columnKindString,
+	"BPCHAR":     columnKindString,
+
+	"INT":         columnKindInt,
+	"INTEGER":     columnKindInt,
+	"TINYINT":     columnKindInt,
+	"SMALLINT":    columnKindInt,
+	"MEDIUMINT":   columnKindInt,
+	"BIGINT":      columnKindInt,
+	"INT2":        columnKindInt,
+	"INT4":        columnKindInt,
+	"INT8":        columnKindInt,
+	"SERIAL":      columnKindInt,
+	"SMALLSERIAL": columnKindInt,
+	"BIGSERIAL":   columnKindInt,
+	"YEAR":        columnKindInt,
+
+	"FLOAT":   columnKindFloat,
+	"FLOAT4":  columnKindFloat,
+	"FLOAT8":  columnKindFloat,
+	"REAL":    columnKindFloat,
+	"DOUBLE":  columnKindFloat,
+	"FLOAT32": columnKindFloat,
+	"FLOAT64": columnKindFloat,
+
+	"BOOL":    columnKindBool,
+	"BOOLEAN": columnKindBool,
+	"BIT":     columnKindBool,
+
+	"DATE":           columnKindTime,
+	"DATETIME":       columnKindTime,
+	"DATETIME2":      columnKindTime,
+	"SMALLDATETIME":  columnKindTime,
+	"DATETIMEOFFSET": columnKindTime,
+	"TIMESTAMP":      columnKindTime,
+	"TIMESTAMPTZ":    columnKindTime,
+
+	"DECIMAL":    columnKindDecimal,
+	"NUMERIC":    columnKindDecimal,
+	"MONEY":      columnKindDecimal,
+	"SMALLMONEY": columnKindDecimal,
+
+	"JSON":  columnKindJSON,
+	"JSONB": columnKindJSON,
+
+	"BLOB":       columnKindBytes,
+	"TINYBLOB":   columnKindBytes,
+	"MEDIUMBLOB": columnKindBytes,
+	"LONGBLOB":   columnKindBytes,
+	"BYTEA":      columnKindBytes,
+	"BINARY":     columnKindBytes,
+	"VARBINARY":  columnKindBytes,
+	"IMAGE":      columnKindBytes,
+}
+
+// timeLayouts are the layouts tried to parse times returned as text.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// kindOf returns the column kind of the given database type name, names like
+// "varchar(60)", "UNSIGNED BIGINT" or "timestamp with time zone" are reduced
+// to their base type first.
+func kindOf(typeName string) columnKind {
+	name := strings.ToUpper(strings.TrimSpace(typeName))
+	name = strings.TrimPrefix(name, "UNSIGNED ")
+	if i := strings.IndexAny(name, "( "); i >= 0 {
+		name = name[:i]
+	}
+	return columnKinds[name]
+}
+
+// normalizeValue converts a value scanned into an interface{} to the Go type
+// of the given column kind: string, int64, float64, bool, time.Time, a string
+// for decimals, json.RawMessage for JSON or []byte for binary data. Values of
+// columns of unknown kind are returned as strings if they're valid UTF-8 text.
+// Values that can't be converted are returned with their integer and float
+// types widened to int64 and float64.
+func normalizeValue(kind columnKind, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch kind {
+	case columnKindString:
+		if b, ok := v.([]byte); ok {
+			return string(b)
+		}
+	case columnKindInt:
+		switch x := v.(type) {
+		case []byte:
+			if n, err := strconv.ParseInt(string(x), 10, 64); err == nil {
+				return n
+			}
+		case string:
+			if n, err := strconv.ParseInt(x, 10, 64); err == nil {
+				return n
+			}
+		case bool:
+			if x {
+				return int64(1)
+			}
+			return int64(0)
+		}
+	case columnKindFloat:
+		switch x := v.(type) {
+		case []byte:
+			if f, err := strconv.ParseFloat(string(x), 64); err == nil {
+				return f
+			}
+		case string:
+			if f, err := strconv.ParseFloat(x, 64); err == nil {
+				return f
+			}
+		}
+		if n, ok := widen(v).(int64); ok {
+			return float64(n)
+		}
+	case columnKindBool:
+		switch x := v.(type) {
+		case bool:
+			return x
+		case []byte:
+			if len(x) == 1 && x[0] <= 1 {
+				return x[0] == 1
+			}
+			if b, err := strconv.ParseBool(string(x)); err == nil {
+				return b
+			}
+		case string:
+			if b, err := strconv.ParseBool(x); err == nil {
+				return b
+			}
+		}
+		if n, ok := widen(v).(int64); ok {
+			return n != 0
+		}
+	case columnKindTime:
+		switch x := v.(type) {
+		case []byte:
+			if t, ok := parseTime(string(x)); ok {
+				return t
+			}
+			return string(x)
+		case string:
+			if t, ok := parseTime(x); ok {
+				return t
+			}
+		}
+	case columnKindDecimal:
+		switch x := v.(type) {
+		case []byte:
+			return string(x)
+		case float64:
+			return strconv.FormatFloat(x, 'f', -1, 64)
+		case float32:
+			return strconv.FormatFloat(float64(x), 'f', -1, 32)
+		}
+		if n, ok := widen(v).(int64); ok {
+			return strconv.FormatInt(n, 10)
+		}
+	case columnKindJSON:
+		switch x := v.(type) {
+		case []byte:
+			return json.RawMessage(x)
+		case string:
+			return json.RawMessage(x)
+		}
+	case columnKindBytes:
+		if s, ok := v.(string); ok {
+			return []byte(s)
+		}
+	case columnKindUnknown:
+		// Drivers that don't report column types return text as []byte.
+		if b, ok := v.([]byte); ok && utf8.Valid(b) {
+			return string(b)
+		}
+	}
+	return widen(v)
+}
+
+// widen converts integers to int64 and floats to float64, unsigned integers
+// that overflow an int64 are returned as they are.
+func widen(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int:
+		return int64(x)
+	case int8:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case uint:
+		if uint64(x) <= math.MaxInt64 {
+			return int64(x)
+		}
+	case uint8:
+		return int64(x)
+	case uint16:
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint64:
+		if x <= math.MaxInt64 {
+			return int64(x)
+		}
+	case float32:
+		return float64(x)
+	}
+	return v
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// kindTypes maps column kinds to the Go types their values are normalized to.
+var kindTypes = map[columnKind]reflect.Type{
+	columnKindString:  reflect.TypeOf(""),
+	columnKindInt:     reflect.TypeOf(int64(0)),
+	columnKindFloat:   reflect.TypeOf(float64(0)),
+	columnKindBool:    reflect.TypeOf(false),
+	columnKindTime:    timeType,
+	columnKindDecimal: reflect.TypeOf(""),
+	columnKindJSON:    reflect.TypeOf(json.RawMessage{}),
+	columnKindBytes:   reflect.TypeOf([]byte{}),
+}
+
+// loadColumns reads the column types of the cursor once.
+func (iter *iterator) loadColumns() error {
+	if iter.columns != nil {
+		return nil
+	}
+	if iter.cursor == nil {
+		return db.ErrNoMoreRows
+	}
+	columnTypes, err := iter.cursor.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	columns := make([]db.ResultColumn, len(columnTypes))
+	kinds := make([]columnKind, len(columnTypes))
+	for i := range columnTypes {
+		kinds[i] = kindOf(columnTypes[i].DatabaseTypeName())
+		columns[i] = db.ResultColumn{
+			Name:         columnTypes[i].Name(),
+			DatabaseType: columnTypes[i].DatabaseTypeName(),
+			Type:         kindTypes[kinds[i]],
+		}
+	}
+	iter.columns, iter.kinds = columns, kinds
+	return nil
+}
+
+// columnKinds returns the kinds of the columns of the cursor.
+func (iter *iterator) columnKinds() ([]columnKind, error) {
+	if err := iter.loadColumns(); err != nil {
+		return nil, err
+	}
+	return iter.kinds, nil
+}
+
+func (iter *iterator) Columns() ([]db.ResultColumn, error) {
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	if err := iter.loadColumns(); err != nil {
+		return nil, err
+	}
+	return iter.columns, nil
+}
+
+func (iter *iterator) Values() ([]interface{}, error) {
+	kinds, err := iter.columnKinds()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(kinds))
+	dst := make([]interface{}, len(kinds))
+	for i := range values {
+		dst[i] = &values[i]
+	}
+	if err := iter.Scan(dst...); err != nil {
+		return nil, err
+	}
+	for i := range values {
+		values[i] = normalizeValue(kinds[i], values[i])
+	}
+	return values, nil
+}
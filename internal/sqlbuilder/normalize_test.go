@@ -0,0 +1,60 @@
+package sqlbuilder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	assert.Equal(t, columnKindString, kindOf("varchar(60)"))
+	assert.Equal(t, columnKindString, kindOf("CHARACTER VARYING"))
+	assert.Equal(t, columnKindInt, kindOf("UNSIGNED BIGINT"))
+	assert.Equal(t, columnKindFloat, kindOf("DOUBLE PRECISION"))
+	assert.Equal(t, columnKindTime, kindOf("timestamp with time zone"))
+	assert.Equal(t, columnKindDecimal, kindOf("DECIMAL(10,2)"))
+	assert.Equal(t, columnKindJSON, kindOf("JSONB"))
+	assert.Equal(t, columnKindBytes, kindOf("BYTEA"))
+	assert.Equal(t, columnKindUnknown, kindOf(""))
+}
+
+func TestNormalizeValue(t *testing.T) {
+	samples := []struct {
+		kind columnKind
+		in   interface{}
+		out  interface{}
+	}{
+		{columnKindString, []byte("hello"), "hello"},
+		{columnKindString, "hello", "hello"},
+		{columnKindInt, []byte("42"), int64(42)},
+		{columnKindInt, int32(42), int64(42)},
+		{columnKindInt, uint8(42), int64(42)},
+		{columnKindFloat, []byte("1.5"), 1.5},
+		{columnKindFloat, float32(1.5), 1.5},
+		{columnKindFloat, int64(2), 2.0},
+		{columnKindBool, []byte{1}, true},
+		{columnKindBool, []byte("0"), false},
+		{columnKindBool, int64(1), true},
+		{columnKindBool, "true", true},
+		{columnKindTime, []byte("2016-01-02 03:04:05"), time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{columnKindTime, "2016-01-02", time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{columnKindTime, []byte("never"), "never"},
+		{columnKindDecimal, []byte("10.20"), "10.20"},
+		{columnKindDecimal, 10.2, "10.2"},
+		{columnKindDecimal, int64(10), "10"},
+		{columnKindJSON, []byte(`{"a":1}`), json.RawMessage(`{"a":1}`)},
+		{columnKindJSON, `[1]`, json.RawMessage(`[1]`)},
+		{columnKindBytes, []byte{0, 1}, []byte{0, 1}},
+		{columnKindBytes, "ab", []byte("ab")},
+		{columnKindUnknown, int16(7), int64(7)},
+		{columnKindUnknown, []byte("raw"), "raw"},
+		{columnKindUnknown, []byte{0xff, 0xfe}, []byte{0xff, 0xfe}},
+		{columnKindInt, nil, nil},
+	}
+
+	for _, sample := range samples {
+		assert.Equal(t, sample.out, normalizeValue(sample.kind, sample.in), "%v", sample.in)
+	}
+}
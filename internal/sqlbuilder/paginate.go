@@ -171,7 +171,7 @@ func (pag *paginator) Iterator() db.Iterator {
 	pq, err := pag.buildWithCursor()
 	if err != nil {
 		sess := pq.sel.(*selector).SQL().sess
-		return &iterator{sess, nil, err}
+		return &iterator{sess: sess, err: err}
 	}
 	return pq.sel.Iterator()
 }
@@ -180,7 +180,7 @@ func (pag *paginator) IteratorContext(ctx context.Context) db.Iterator {
 	pq, err := pag.buildWithCursor()
 	if err != nil {
 		sess := pq.sel.(*selector).SQL().sess
-		return &iterator{sess, nil, err}
+		return &iterator{sess: sess, err: err}
 	}
 	return pq.sel.IteratorContext(ctx)
 }
@@ -41,6 +41,12 @@ type selectorQuery struct {
 }
 
 func (sq *selectorQuery) and(b *sqlBuilder, terms ...interface{}) error {
+	codecs := CodecsOf(b.sess)
+	terms, err := encryptConds(codecs, encryptedColumns(codecs, sq.tables()...), terms)
+	if err != nil {
+		return err
+	}
+
 	where, whereArgs := b.t.toWhereWithArguments(terms)
 
 	if sq.where == nil {
@@ -52,6 +58,17 @@ func (sq *selectorQuery) and(b *sqlBuilder, terms ...interface{}) error {
 	return nil
 }
 
+// tables returns the names of the tables the query selects from so far.
+func (sq *selectorQuery) tables() []string {
+	tables := tableNames(sq.table)
+	for _, join := range sq.joins {
+		if columns, ok := join.Table.(*exql.Columns); ok {
+			tables = append(tables, tableNames(columns)...)
+		}
+	}
+	return tables
+}
+
 func (sq *selectorQuery) arguments() []interface{} {
 	return joinArguments(
 		sq.columnsArgs,
@@ -474,11 +491,11 @@ func (sel *selector) IteratorContext(ctx context.Context) db.Iterator {
 	sess := sel.SQL().sess
 	sq, err := sel.build()
 	if err != nil {
-		return &iterator{sess, nil, err}
+		return &iterator{sess: sess, err: err}
 	}
 
 	rows, err := sess.StatementQuery(ctx, sq.statement(), sq.arguments()...)
-	return &iterator{sess, rows, err}
+	return &iterator{sess: sess, cursor: rows, err: err}
 }
 
 func (sel *selector) Paginate(pageSize uint) db.Paginator {
@@ -133,6 +133,9 @@ const (
     {{else}}
       (default)
     {{end}}
+    {{if defined .OnConflict}}
+      {{.OnConflict | compile}}
+    {{end}}
     {{if defined .Returning}}
       RETURNING {{.Returning | compile}}
     {{end}}
@@ -147,7 +150,7 @@ const (
   `
 
 	defaultDropTableLayout = `
-    DROP TABLE {{.Table | compile}}
+    DROP TABLE {{if .IfExists}}IF EXISTS {{end}}{{.Table | compile}}
   `
 
 	defaultGroupByLayout = `
@@ -155,38 +158,110 @@ const (
       GROUP BY {{.GroupColumns}}
     {{end}}
   `
+
+	defaultTrueKeyword  = `TRUE`
+	defaultFalseKeyword = `FALSE`
+
+	defaultCreateTableLayout = `
+    CREATE TABLE {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Table | compile}} (
+      {{.Definition | compile}}
+    )
+  `
+
+	defaultAlterTableLayout = `
+    {{.Definition | compile}}
+  `
+
+	defaultCreateIndexLayout = `
+    CREATE {{if .Unique}}UNIQUE {{end}}INDEX {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Name | compile}}
+      ON {{.Table | compile}} ({{.Columns | compile}})
+  `
+
+	defaultDropIndexLayout = `
+    DROP INDEX {{if .IfExists}}IF EXISTS {{end}}{{.Name | compile}}
+  `
+
+	defaultColumnDefinitionLayout = `{{.Name}} {{.Type}}{{if .NotNull}} NOT NULL{{end}}{{if .Default}} DEFAULT {{.Default}}{{end}}{{if .Unique}} UNIQUE{{end}}`
+	defaultPrimaryKeyLayout       = `PRIMARY KEY ({{.Columns}})`
+	defaultUniqueLayout           = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}UNIQUE ({{.Columns}})`
+	defaultForeignKeyLayout       = `{{if .Name}}CONSTRAINT {{.Name}} {{end}}FOREIGN KEY ({{.Columns}}) REFERENCES {{.ReferencedTable}} ({{.ReferencedColumns}}){{if .OnUpdate}} ON UPDATE {{.OnUpdate}}{{end}}{{if .OnDelete}} ON DELETE {{.OnDelete}}{{end}}`
+	defaultAddColumnLayout        = `ALTER TABLE {{.Table}} ADD COLUMN {{.Column}}`
+	defaultDropColumnLayout       = `ALTER TABLE {{.Table}} DROP COLUMN {{.Name}}`
+	defaultRenameColumnLayout     = `ALTER TABLE {{.Table}} RENAME COLUMN {{.Name}} TO {{.NewName}}`
+	defaultRenameTableLayout      = `ALTER TABLE {{.Table}} RENAME TO {{.NewName}}`
+	defaultAddConstraintLayout    = `ALTER TABLE {{.Table}} ADD {{.Constraint}}`
+	defaultDropConstraintLayout   = `ALTER TABLE {{.Table}} DROP CONSTRAINT {{.Name}}`
+
+	defaultOnConflictLayout     = `ON CONFLICT ({{.Columns}}) {{if .Update}}DO UPDATE SET {{.Update}} {{.Where}}{{else}}DO NOTHING{{end}}`
+	defaultConflictUpdateLayout = `{{.}} = EXCLUDED.{{.}}`
 )
 
+var defaultColumnTypes = map[string]string{
+	"serial":    `SERIAL`,
+	"bigserial": `BIGSERIAL`,
+	"integer":   `INTEGER`,
+	"bigint":    `BIGINT`,
+	"string":    `VARCHAR({{if .Size}}{{.Size}}{{else}}255{{end}})`,
+	"text":      `TEXT`,
+	"boolean":   `BOOLEAN`,
+	"float":     `DOUBLE PRECISION`,
+	"decimal":   `NUMERIC{{if .Precision}}({{.Precision}}, {{.Scale}}){{end}}`,
+	"timestamp": `TIMESTAMP WITH TIME ZONE`,
+	"date":      `DATE`,
+	"binary":    `BYTEA`,
+	"json":      `JSONB`,
+	"uuid":      `UUID`,
+}
+
 var testTemplate = exql.Template{
-	ColumnSeparator:     defaultColumnSeparator,
-	IdentifierSeparator: defaultIdentifierSeparator,
-	IdentifierQuote:     defaultIdentifierQuote,
-	ValueSeparator:      defaultValueSeparator,
-	ValueQuote:          defaultValueQuote,
-	AndKeyword:          defaultAndKeyword,
-	OrKeyword:           defaultOrKeyword,
-	DescKeyword:         defaultDescKeyword,
-	AscKeyword:          defaultAscKeyword,
-	AssignmentOperator:  defaultAssignmentOperator,
-	ClauseGroup:         defaultClauseGroup,
-	ClauseOperator:      defaultClauseOperator,
-	ColumnValue:         defaultColumnValue,
-	TableAliasLayout:    defaultTableAliasLayout,
-	ColumnAliasLayout:   defaultColumnAliasLayout,
-	SortByColumnLayout:  defaultSortByColumnLayout,
-	WhereLayout:         defaultWhereLayout,
-	OnLayout:            defaultOnLayout,
-	UsingLayout:         defaultUsingLayout,
-	JoinLayout:          defaultJoinLayout,
-	OrderByLayout:       defaultOrderByLayout,
-	InsertLayout:        defaultInsertLayout,
-	SelectLayout:        defaultSelectLayout,
-	UpdateLayout:        defaultUpdateLayout,
-	DeleteLayout:        defaultDeleteLayout,
-	TruncateLayout:      defaultTruncateLayout,
-	DropDatabaseLayout:  defaultDropDatabaseLayout,
-	DropTableLayout:     defaultDropTableLayout,
-	CountLayout:         defaultCountLayout,
-	GroupByLayout:       defaultGroupByLayout,
-	Cache:               cache.NewCache(),
+	ColumnSeparator:        defaultColumnSeparator,
+	IdentifierSeparator:    defaultIdentifierSeparator,
+	IdentifierQuote:        defaultIdentifierQuote,
+	ValueSeparator:         defaultValueSeparator,
+	ValueQuote:             defaultValueQuote,
+	AndKeyword:             defaultAndKeyword,
+	OrKeyword:              defaultOrKeyword,
+	DescKeyword:            defaultDescKeyword,
+	AscKeyword:             defaultAscKeyword,
+	AssignmentOperator:     defaultAssignmentOperator,
+	ClauseGroup:            defaultClauseGroup,
+	ClauseOperator:         defaultClauseOperator,
+	ColumnValue:            defaultColumnValue,
+	TableAliasLayout:       defaultTableAliasLayout,
+	ColumnAliasLayout:      defaultColumnAliasLayout,
+	SortByColumnLayout:     defaultSortByColumnLayout,
+	WhereLayout:            defaultWhereLayout,
+	OnLayout:               defaultOnLayout,
+	UsingLayout:            defaultUsingLayout,
+	JoinLayout:             defaultJoinLayout,
+	OrderByLayout:          defaultOrderByLayout,
+	InsertLayout:           defaultInsertLayout,
+	SelectLayout:           defaultSelectLayout,
+	UpdateLayout:           defaultUpdateLayout,
+	DeleteLayout:           defaultDeleteLayout,
+	TruncateLayout:         defaultTruncateLayout,
+	DropDatabaseLayout:     defaultDropDatabaseLayout,
+	DropTableLayout:        defaultDropTableLayout,
+	CountLayout:            defaultCountLayout,
+	GroupByLayout:          defaultGroupByLayout,
+	TrueKeyword:            defaultTrueKeyword,
+	FalseKeyword:           defaultFalseKeyword,
+	CreateTableLayout:      defaultCreateTableLayout,
+	AlterTableLayout:       defaultAlterTableLayout,
+	CreateIndexLayout:      defaultCreateIndexLayout,
+	DropIndexLayout:        defaultDropIndexLayout,
+	ColumnDefinitionLayout: defaultColumnDefinitionLayout,
+	PrimaryKeyLayout:       defaultPrimaryKeyLayout,
+	UniqueLayout:           defaultUniqueLayout,
+	ForeignKeyLayout:       defaultForeignKeyLayout,
+	AddColumnLayout:        defaultAddColumnLayout,
+	DropColumnLayout:       defaultDropColumnLayout,
+	RenameColumnLayout:     defaultRenameColumnLayout,
+	RenameTableLayout:      defaultRenameTableLayout,
+	AddConstraintLayout:    defaultAddConstraintLayout,
+	DropConstraintLayout:   defaultDropConstraintLayout,
+	OnConflictLayout:       defaultOnConflictLayout,
+	ConflictUpdateLayout:   defaultConflictUpdateLayout,
+	ColumnTypes:            defaultColumnTypes,
+	Cache:                  cache.NewCache(),
 }
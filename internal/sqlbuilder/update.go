@@ -24,6 +24,12 @@ type updaterQuery struct {
 }
 
 func (uq *updaterQuery) and(b *sqlBuilder, terms ...interface{}) error {
+	codecs := CodecsOf(b.sess)
+	terms, err := encryptConds(codecs, encryptedColumns(codecs, uq.table), terms)
+	if err != nil {
+		return err
+	}
+
 	where, whereArgs := b.t.toWhereWithArguments(terms)
 
 	if uq.where == nil {
@@ -108,7 +114,11 @@ func (upd *updater) Set(terms ...interface{}) db.Updater {
 		}
 
 		if len(terms) == 1 {
-			ff, vv, err := Map(terms[0], nil)
+			ff, vv, err := Map(terms[0], &MapOptions{
+				Mapper: MapperOf(upd.SQL().sess),
+				Codecs: CodecsOf(upd.SQL().sess),
+				Table:  uq.table,
+			})
 			if err == nil && len(ff) > 0 {
 				cvs := make([]exql.Fragment, 0, len(ff))
 				args := make([]interface{}, 0, len(vv))
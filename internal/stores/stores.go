@@ -0,0 +1,86 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package stores keeps what the data stores given to db.WithStores declare
+// about their collections.
+package stores
+
+import (
+	db "github.com/upper/db/v4"
+)
+
+// Declarations holds the declarations of the stores of a session, by
+// collection name. A nil *Declarations has no declarations.
+type Declarations struct {
+	scopes     map[string]db.Scopes
+	softDelete map[string]string
+}
+
+// Declare returns a copy of d with the declarations of the given stores,
+// which replace the ones of earlier stores of the same collections.
+func (d *Declarations) Declare(stores ...db.Store) *Declarations {
+	decls := &Declarations{
+		scopes:     map[string]db.Scopes{},
+		softDelete: map[string]string{},
+	}
+	if d != nil {
+		for name, scopes := range d.scopes {
+			decls.scopes[name] = scopes
+		}
+		for name, column := range d.softDelete {
+			decls.softDelete[name] = column
+		}
+	}
+	for _, store := range stores {
+		if store == nil {
+			continue
+		}
+		name := store.Name()
+		delete(decls.scopes, name)
+		delete(decls.softDelete, name)
+		if s, ok := store.(db.HasScopes); ok {
+			decls.scopes[name] = s.Scopes()
+		}
+		if s, ok := store.(db.SoftDeletableStore); ok && s.SoftDeleteColumn() != "" {
+			decls.softDelete[name] = s.SoftDeleteColumn()
+		}
+	}
+	return decls
+}
+
+// Scope returns the scope with the given name that the store of the given
+// collection declares.
+func (d *Declarations) Scope(collection string, name string) (db.Scope, bool) {
+	if d == nil {
+		return nil, false
+	}
+	scope, ok := d.scopes[collection][name]
+	return scope, ok && scope != nil
+}
+
+// SoftDeleteColumn returns the soft delete column that the store of the given
+// collection declares, or an empty string if it declares none.
+func (d *Declarations) SoftDeleteColumn(collection string) string {
+	if d == nil {
+		return ""
+	}
+	return d.softDelete[collection]
+}
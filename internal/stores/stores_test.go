@@ -0,0 +1,73 @@
+package stores
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+type testStore struct {
+	db.Collection
+	name   string
+	scopes db.Scopes
+}
+
+func (s *testStore) Name() string {
+	return s.name
+}
+
+type scopedStore struct {
+	testStore
+}
+
+func (s *scopedStore) Scopes() db.Scopes {
+	return s.scopes
+}
+
+type softDeletableStore struct {
+	testStore
+}
+
+func (*softDeletableStore) SoftDeleteColumn() string {
+	return "deleted_at"
+}
+
+func TestDeclarations(t *testing.T) {
+	active := func(res db.Result, args ...interface{}) db.Result {
+		return res.And(db.Cond{"active": true})
+	}
+
+	var decls *Declarations
+	_, ok := decls.Scope("users", "active")
+	assert.False(t, ok)
+
+	decls = decls.Declare(&scopedStore{testStore{name: "users", scopes: db.Scopes{"active": active}}})
+
+	scope, ok := decls.Scope("users", "active")
+	assert.True(t, ok)
+	assert.NotNil(t, scope)
+
+	_, ok = decls.Scope("accounts", "active")
+	assert.False(t, ok)
+
+	replaced := decls.Declare(&testStore{name: "users"})
+	_, ok = replaced.Scope("users", "active")
+	assert.False(t, ok)
+
+	_, ok = decls.Scope("users", "active")
+	assert.True(t, ok)
+
+	assert.Equal(t, "", decls.SoftDeleteColumn("users"))
+	assert.Equal(t, "", (*Declarations)(nil).SoftDeleteColumn("users"))
+
+	withBooks := decls.Declare(&softDeletableStore{testStore{name: "books"}})
+	assert.Equal(t, "deleted_at", withBooks.SoftDeleteColumn("books"))
+	assert.Equal(t, "", decls.SoftDeleteColumn("books"))
+
+	_, ok = withBooks.Scope("users", "active")
+	assert.True(t, ok)
+
+	replaced = withBooks.Declare(&testStore{name: "books"})
+	assert.Equal(t, "", replaced.SoftDeleteColumn("books"))
+}
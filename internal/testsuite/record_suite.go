@@ -3,7 +3,9 @@ package testsuite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/stretchr/testify/suite"
@@ -77,6 +79,164 @@ func (*User) Store(sess db.Session) db.Store {
 	return Users(sess)
 }
 
+type Book struct {
+	ID        uint64     `db:"id,omitempty"`
+	Title     string     `db:"title"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+func (*Book) Store(sess db.Session) db.Store {
+	return &booksStore{sess.Collection("soft_delete_books")}
+}
+
+func (*Book) SoftDeleteColumn() string {
+	return "deleted_at"
+}
+
+var _ = db.SoftDeletable(&Book{})
+
+type booksStore struct {
+	db.Collection
+}
+
+func (*booksStore) SoftDeleteColumn() string {
+	return "deleted_at"
+}
+
+var _ = db.SoftDeletableStore(&booksStore{})
+
+type Note struct {
+	ID        uint64     `db:"id,omitempty"`
+	Body      string     `db:"body"`
+	CreatedAt time.Time  `db:"created_at,autocreatetime"`
+	UpdatedAt *time.Time `db:"updated_at,autoupdatetime"`
+}
+
+func (*Note) Store(sess db.Session) db.Store {
+	return sess.Collection("timestamped_notes")
+}
+
+type Document struct {
+	ID      uint64 `db:"id,omitempty"`
+	Title   string `db:"title"`
+	Version int64  `db:"version,optlock"`
+}
+
+func (*Document) Store(sess db.Session) db.Store {
+	return sess.Collection("versioned_documents")
+}
+
+type TrackedAccount struct {
+	db.Tracking
+
+	ID       uint64 `db:"id,omitempty"`
+	Name     string `db:"name"`
+	Disabled bool   `db:"disabled"`
+}
+
+func (*TrackedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type LoadedAccount struct {
+	ID       uint64 `db:"id,omitempty"`
+	Name     string `db:"name"`
+	Disabled bool   `db:"disabled"`
+
+	Label string `db:"-"`
+}
+
+func (*LoadedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+func (account *LoadedAccount) AfterFind(sess db.Session) error {
+	if sess == nil {
+		return errors.New("missing session")
+	}
+	if account.Name == "" {
+		return errors.New("account has no name")
+	}
+	account.Label = fmt.Sprintf("%d: %s", account.ID, account.Name)
+	return nil
+}
+
+var _ = db.AfterFindHook(&LoadedAccount{})
+
+type Event struct {
+	ID   string `db:"id,generate=uuidv7"`
+	Seq  int64  `db:"seq,generate=snowflake"`
+	Name string `db:"name"`
+}
+
+func (*Event) Store(sess db.Session) db.Store {
+	return sess.Collection("generated_events")
+}
+
+type EventsStore struct {
+	db.Collection
+}
+
+func (*EventsStore) GenerateKey(column string) (interface{}, error) {
+	id, err := db.NewULID()
+	if err != nil {
+		return nil, err
+	}
+	return "evt_" + id, nil
+}
+
+var _ = db.KeyGenerator(&EventsStore{})
+
+type StoreKeyedEvent struct {
+	ID   string `db:"id"`
+	Seq  int64  `db:"seq"`
+	Name string `db:"name"`
+}
+
+func (*StoreKeyedEvent) Store(sess db.Session) db.Store {
+	return &EventsStore{sess.Collection("generated_events")}
+}
+
+type SnakeAccount struct {
+	db.Tracking
+
+	ID        uint64 `db:"id,omitempty"`
+	Name      string
+	Disabled  bool
+	CreatedAt *time.Time `db:"created_at,omitempty,autocreatetime"`
+}
+
+func (*SnakeAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type AccountProfile struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+type ProfileAccount struct {
+	db.Tracking
+
+	ID      uint64         `db:"id,omitempty"`
+	Profile AccountProfile `db:"name,codec=json"`
+}
+
+func (*ProfileAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type JSONAccount struct {
+	ID   uint64 `json:"id,omitempty"`
+	Name string `json:"name"`
+
+	Disabled bool `db:"disabled"`
+}
+
+func (*JSONAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
 type RecordTestSuite struct {
 	suite.Suite
 	Helper
@@ -392,6 +552,631 @@ func (s *RecordTestSuite) TestInheritedTx() {
 	s.NoError(err)
 }
 
+func (s *RecordTestSuite) TestSoftDelete() {
+	sess := s.Session()
+
+	_, err := sess.SQL().DropTable("soft_delete_books").IfExists().Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		CreateTable("soft_delete_books").
+		Column("id", db.TypeSerial).
+		Column("title", db.TypeString, db.Size(60), db.NotNull()).
+		Column("deleted_at", db.TypeTimestamp).
+		PrimaryKey("id").
+		Exec()
+	s.NoError(err)
+
+	sess, err = db.WithStores(sess, (&Book{}).Store(sess))
+	s.NoError(err)
+
+	books := (&Book{}).Store(sess)
+
+	dune := Book{Title: "Dune"}
+	err = sess.Save(&dune)
+	s.NoError(err)
+
+	solaris := Book{Title: "Solaris"}
+	err = sess.Save(&solaris)
+	s.NoError(err)
+
+	err = sess.Delete(&dune)
+	s.NoError(err)
+	s.NotNil(dune.DeletedAt)
+
+	err = sess.Get(&Book{}, dune.ID)
+	s.True(errors.Is(err, db.ErrNoMoreRows))
+
+	var all []Book
+	err = books.Find().All(&all)
+	s.NoError(err)
+	if s.Len(all, 1) {
+		s.Equal("Solaris", all[0].Title)
+	}
+
+	count, err := books.Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	count, err = books.Find().WithDeleted().Count()
+	s.NoError(err)
+	s.Equal(uint64(2), count)
+
+	exists, err := sess.Exists(&dune)
+	s.NoError(err)
+	s.False(exists)
+
+	// Sessions the store wasn't given to don't know about the soft delete
+	// column.
+	count, err = s.Session().Collection("soft_delete_books").Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(2), count)
+
+	err = s.Session().Get(&Book{}, dune.ID)
+	s.True(errors.Is(err, db.ErrNoMoreRows))
+
+	var deleted []*Book
+	err = books.Find().OnlyDeleted().All(&deleted)
+	s.NoError(err)
+	if s.Len(deleted, 1) {
+		s.Equal("Dune", deleted[0].Title)
+		s.NotNil(deleted[0].DeletedAt)
+	}
+
+	err = books.Find(dune.ID).Restore()
+	s.NoError(err)
+
+	var restored Book
+	err = sess.Get(&restored, dune.ID)
+	s.NoError(err)
+	s.Nil(restored.DeletedAt)
+
+	// Deleting through a result is also a soft delete.
+	err = books.Find(solaris.ID).Delete()
+	s.NoError(err)
+
+	count, err = books.Find().OnlyDeleted().Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	err = books.Find(solaris.ID).HardDelete()
+	s.NoError(err)
+
+	count, err = books.Find().WithDeleted().Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	err = Accounts(sess).Find().Restore()
+	s.True(errors.Is(err, db.ErrNotSoftDeletable))
+
+	_, err = sess.SQL().DropTable("soft_delete_books").Exec()
+	s.NoError(err)
+}
+
+func (s *RecordTestSuite) TestAutoTimestamps() {
+	sess := s.Session()
+
+	_, err := sess.SQL().DropTable("timestamped_notes").IfExists().Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		CreateTable("timestamped_notes").
+		Column("id", db.TypeSerial).
+		Column("body", db.TypeString, db.Size(60), db.NotNull()).
+		Column("created_at", db.TypeTimestamp).
+		Column("updated_at", db.TypeTimestamp).
+		PrimaryKey("id").
+		Exec()
+	s.NoError(err)
+
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	sess.SetClock(func() time.Time {
+		return now
+	})
+	defer sess.SetClock(nil)
+
+	// The clock is the one of the session and its transactions.
+	s.False(now.Equal(db.DefaultSettings.Now()))
+	err = sess.Tx(func(tx db.Session) error {
+		s.True(now.Equal(tx.Now()))
+		return nil
+	})
+	s.NoError(err)
+
+	created := now
+
+	note := Note{Body: "Buy milk"}
+	err = sess.Save(&note)
+	s.NoError(err)
+	s.True(created.Equal(note.CreatedAt))
+	if s.NotNil(note.UpdatedAt) {
+		s.True(created.Equal(*note.UpdatedAt))
+	}
+
+	now = now.Add(time.Hour)
+
+	note.Body = "Buy oat milk"
+	err = sess.Save(&note)
+	s.NoError(err)
+	s.True(created.Equal(note.CreatedAt))
+	s.True(now.Equal(*note.UpdatedAt))
+
+	now = now.Add(time.Hour)
+
+	update := note
+	update.Body = "Buy almond milk"
+	err = sess.Collection("timestamped_notes").Find(note.ID).Update(update)
+	s.NoError(err)
+
+	var stored Note
+	err = sess.Get(&stored, note.ID)
+	s.NoError(err)
+	s.Equal("Buy almond milk", stored.Body)
+	s.True(created.Equal(stored.CreatedAt))
+	if s.NotNil(stored.UpdatedAt) {
+		s.True(now.Equal(*stored.UpdatedAt))
+	}
+
+	_, err = sess.SQL().DropTable("timestamped_notes").Exec()
+	s.NoError(err)
+}
+
+func (s *RecordTestSuite) TestOptimisticLocking() {
+	sess := s.Session()
+
+	_, err := sess.SQL().DropTable("versioned_documents").IfExists().Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		CreateTable("versioned_documents").
+		Column("id", db.TypeSerial).
+		Column("title", db.TypeString, db.Size(60), db.NotNull()).
+		Column("version", db.TypeBigInteger, db.NotNull(), db.Default(0)).
+		PrimaryKey("id").
+		Exec()
+	s.NoError(err)
+
+	document := Document{Title: "Draft"}
+	err = sess.Save(&document)
+	s.NoError(err)
+	s.Equal(int64(0), document.Version)
+
+	var first, second Document
+	s.NoError(sess.Get(&first, document.ID))
+	s.NoError(sess.Get(&second, document.ID))
+
+	first.Title = "First"
+	err = sess.Save(&first)
+	s.NoError(err)
+	s.Equal(int64(1), first.Version)
+
+	second.Title = "Second"
+	err = sess.Save(&second)
+	s.True(errors.Is(err, db.ErrStaleRecord))
+	s.Equal(int64(0), second.Version)
+
+	var stored Document
+	s.NoError(sess.Get(&stored, document.ID))
+	s.Equal("First", stored.Title)
+	s.Equal(int64(1), stored.Version)
+
+	// Saving again with a fresh copy works.
+	stored.Title = "Final"
+	err = sess.Save(&stored)
+	s.NoError(err)
+	s.Equal(int64(2), stored.Version)
+
+	_, err = sess.SQL().DropTable("versioned_documents").Exec()
+	s.NoError(err)
+}
+
+func (s *RecordTestSuite) TestChangeTracking() {
+	sess := s.Session()
+
+	account := TrackedAccount{Name: "Ringo"}
+	err := sess.Save(&account)
+	s.NoError(err)
+	s.NotZero(account.ID)
+	s.Empty(db.Changed(&account))
+
+	var loaded TrackedAccount
+	err = sess.Get(&loaded, account.ID)
+	s.NoError(err)
+	s.Empty(db.Changed(&loaded))
+
+	// Another client disables the account.
+	err = Accounts(sess).Find(account.ID).Update(map[string]interface{}{"disabled": true})
+	s.NoError(err)
+
+	loaded.Name = "Ringo Starr"
+	s.Equal(map[string]db.Change{
+		"name": {From: "Ringo", To: "Ringo Starr"},
+	}, db.Changed(&loaded))
+
+	err = sess.Save(&loaded)
+	s.NoError(err)
+	s.Empty(db.Changed(&loaded))
+
+	// Only the name was sent, the concurrent change was preserved.
+	s.Equal("Ringo Starr", loaded.Name)
+	s.True(loaded.Disabled)
+
+	var accounts []*TrackedAccount
+	err = Accounts(sess).Find().All(&accounts)
+	s.NoError(err)
+	if s.Len(accounts, 1) {
+		s.Empty(db.Changed(accounts[0]))
+		s.True(accounts[0].Disabled)
+	}
+}
+
+func (s *RecordTestSuite) TestAfterFindHook() {
+	sess := s.Session()
+
+	account := LoadedAccount{Name: "Paul"}
+	err := sess.Save(&account)
+	s.NoError(err)
+	s.Empty(account.Label)
+
+	var loaded LoadedAccount
+	err = sess.Get(&loaded, account.ID)
+	s.NoError(err)
+	s.Equal(fmt.Sprintf("%d: Paul", account.ID), loaded.Label)
+
+	err = sess.Save(&LoadedAccount{Name: "John"})
+	s.NoError(err)
+
+	var accounts []LoadedAccount
+	err = Accounts(sess).Find().OrderBy("id").All(&accounts)
+	s.NoError(err)
+	if s.Len(accounts, 2) {
+		s.Equal(fmt.Sprintf("%d: Paul", accounts[0].ID), accounts[0].Label)
+		s.Equal(fmt.Sprintf("%d: John", accounts[1].ID), accounts[1].Label)
+	}
+
+	var pointers []*LoadedAccount
+	err = sess.SQL().SelectFrom("accounts").OrderBy("id").Iterator().All(&pointers)
+	s.NoError(err)
+	if s.Len(pointers, 2) {
+		s.Equal(fmt.Sprintf("%d: John", pointers[1].ID), pointers[1].Label)
+	}
+
+	res := Accounts(sess).Find().OrderBy("id")
+	labels := []string{}
+	var next LoadedAccount
+	for res.Next(&next) {
+		labels = append(labels, next.Label)
+	}
+	s.NoError(res.Err())
+	s.NoError(res.Close())
+	s.Equal([]string{accounts[0].Label, accounts[1].Label}, labels)
+
+	// Errors returned by the hook are returned by the loading operation.
+	err = Accounts(sess).Find(account.ID).Update(map[string]interface{}{"name": ""})
+	s.NoError(err)
+
+	err = sess.Get(&loaded, account.ID)
+	s.Error(err)
+
+	err = Accounts(sess).Find().All(&accounts)
+	s.Error(err)
+
+	// The cursor is closed when the hook fails.
+	iter := sess.SQL().SelectFrom("accounts").OrderBy("id").Iterator()
+	s.False(iter.Next(&next))
+	s.Error(iter.Err())
+	if drv, ok := sess.Driver().(*sql.DB); ok {
+		s.Zero(drv.Stats().InUse)
+	}
+}
+
+func (s *RecordTestSuite) TestGeneratedKeys() {
+	sess := s.Session()
+
+	_, err := sess.SQL().DropTable("generated_events").IfExists().Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		CreateTable("generated_events").
+		Column("id", db.TypeString, db.Size(40), db.NotNull()).
+		Column("seq", db.TypeBigInteger, db.NotNull(), db.Default(0)).
+		Column("name", db.TypeString, db.Size(60), db.NotNull()).
+		PrimaryKey("id").
+		Exec()
+	s.NoError(err)
+
+	event := Event{Name: "launch"}
+	err = sess.Save(&event)
+	s.NoError(err)
+	s.Len(event.ID, 36)
+	s.NotZero(event.Seq)
+
+	var stored Event
+	err = sess.Get(&stored, db.Cond{"id": event.ID})
+	s.NoError(err)
+	s.Equal(event, stored)
+
+	// Keys that are already set are kept.
+	custom := Event{ID: "custom", Seq: 1, Name: "custom"}
+	err = sess.Save(&custom)
+	s.NoError(err)
+	s.Equal("custom", custom.ID)
+	s.Equal(int64(1), custom.Seq)
+
+	events := []*Event{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	err = sess.SaveAll(events[0], events[1], events[2])
+	s.NoError(err)
+	for i := range events {
+		s.Len(events[i].ID, 36)
+	}
+	s.True(events[0].Seq < events[1].Seq)
+	s.True(events[1].Seq < events[2].Seq)
+
+	keyed := StoreKeyedEvent{Name: "store"}
+	err = sess.Save(&keyed)
+	s.NoError(err)
+	s.True(strings.HasPrefix(keyed.ID, "evt_"))
+
+	count, err := sess.Collection("generated_events").Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(6), count)
+
+	_, err = sess.SQL().DropTable("generated_events").Exec()
+	s.NoError(err)
+}
+
+func (s *RecordTestSuite) TestFieldMapper() {
+	snakeSess, err := db.WithFieldMapper(s.Session(), db.FieldMapper{
+		Naming: db.SnakeCase,
+	})
+	s.NoError(err)
+
+	account := SnakeAccount{Name: "Ringo"}
+	err = snakeSess.Save(&account)
+	s.NoError(err)
+	s.NotZero(account.ID)
+	s.NotNil(account.CreatedAt)
+	s.Empty(db.Changed(&account))
+
+	var row map[string]interface{}
+	err = s.Session().SQL().SelectFrom("accounts").Where("id", account.ID).One(&row)
+	s.NoError(err)
+	s.Equal("Ringo", fmt.Sprintf("%s", row["name"]))
+
+	var loaded SnakeAccount
+	err = snakeSess.Get(&loaded, account.ID)
+	s.NoError(err)
+	s.Equal("Ringo", loaded.Name)
+	s.False(loaded.Disabled)
+
+	loaded.Disabled = true
+	s.Equal(map[string]db.Change{
+		"disabled": {From: false, To: true},
+	}, db.Changed(&loaded))
+	err = snakeSess.Save(&loaded)
+	s.NoError(err)
+
+	var accounts []SnakeAccount
+	err = snakeSess.WithContext(context.Background()).Collection("accounts").Find().All(&accounts)
+	s.NoError(err)
+	if s.Len(accounts, 1) {
+		s.True(accounts[0].Disabled)
+	}
+
+	// Untagged fields are not mapped by the default mapper.
+	var unmapped SnakeAccount
+	err = s.Session().Get(&unmapped, account.ID)
+	s.NoError(err)
+	s.Equal(account.ID, unmapped.ID)
+	s.Empty(unmapped.Name)
+
+	jsonSess, err := db.WithFieldMapper(s.Session(), db.FieldMapper{TagName: "json"})
+	s.NoError(err)
+
+	var jsonAccount JSONAccount
+	err = jsonSess.Get(&jsonAccount, account.ID)
+	s.NoError(err)
+	s.Equal("Ringo", jsonAccount.Name)
+	// Fields without a json tag fall back to their db tag.
+	s.True(jsonAccount.Disabled)
+
+	err = jsonSess.Tx(func(tx db.Session) error {
+		jsonAccount.Name = "Ringo Starr"
+		return tx.Save(&jsonAccount)
+	})
+	s.NoError(err)
+
+	err = snakeSess.Get(&loaded, account.ID)
+	s.NoError(err)
+	s.Equal("Ringo Starr", loaded.Name)
+	s.True(loaded.Disabled)
+}
+
+func (s *RecordTestSuite) TestReloadAndExists() {
+	sess := s.Session()
+
+	account := Account{Name: "Mercury"}
+	exists, err := sess.Exists(&account)
+	s.NoError(err)
+	s.False(exists)
+
+	err = sess.Save(&account)
+	s.NoError(err)
+
+	exists, err = sess.Exists(&account)
+	s.NoError(err)
+	s.True(exists)
+
+	err = Accounts(sess).Find(account.ID).Update(map[string]interface{}{"name": "Freddie"})
+	s.NoError(err)
+
+	account.Disabled = true
+	err = sess.Reload(&account)
+	s.NoError(err)
+	s.Equal("Freddie", account.Name)
+	s.False(account.Disabled)
+
+	err = sess.Delete(&account)
+	s.NoError(err)
+
+	exists, err = sess.Exists(&account)
+	s.NoError(err)
+	s.False(exists)
+
+	err = sess.Reload(&account)
+	s.True(errors.Is(err, db.ErrNoMoreRows))
+}
+
+func (s *RecordTestSuite) TestSaveAll() {
+	sess := s.Session()
+
+	existing := Account{Name: "Existing"}
+	err := sess.Save(&existing)
+	s.NoError(err)
+
+	existing.Name = "Renamed"
+	created := Account{Name: "Created"}
+	batched := []*Account{
+		{ID: 100, Name: "Batched 1"},
+		{ID: 101, Name: "Batched 2"},
+		{ID: 102, Name: "Batched 3"},
+	}
+
+	err = sess.SaveAll(&existing, &created, batched[0], batched[1], batched[2])
+	s.NoError(err)
+
+	s.NotZero(created.ID)
+	for _, account := range batched {
+		// Values set by the database are read back.
+		s.NotNil(account.CreatedAt)
+	}
+
+	var accounts []Account
+	err = Accounts(sess).Find().OrderBy("id").All(&accounts)
+	s.NoError(err)
+	if s.Len(accounts, 5) {
+		s.Equal("Renamed", accounts[0].Name)
+		s.Equal("Created", accounts[1].Name)
+		s.Equal("Batched 3", accounts[4].Name)
+	}
+
+	// AfterCreate hooks ran for every new record.
+	count, err := Logs(sess).Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(5), count)
+
+	batched[0].Name = "Updated"
+	err = sess.SaveAll(batched[0], &Account{ID: 103, Name: "Batched 4"})
+	s.NoError(err)
+
+	var updated Account
+	err = sess.Get(&updated, uint64(100))
+	s.NoError(err)
+	s.Equal("Updated", updated.Name)
+
+	// An error rolls back all the changes.
+	err = sess.SaveAll(&Account{Name: "Rolled back"}, &Account{ID: 200}, &Account{ID: 200})
+	s.Error(err)
+
+	count, err = Accounts(sess).Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(6), count)
+
+	// New records without a key get the keys the database gives them.
+	keyless := []*Account{
+		{Name: "Keyless 1"},
+		{Name: "Keyless 2"},
+		{Name: "Keyless 3"},
+	}
+	err = sess.SaveAll(keyless[0], keyless[1], keyless[2])
+	s.NoError(err)
+
+	for _, account := range keyless {
+		s.NotZero(account.ID)
+		s.NotNil(account.CreatedAt)
+
+		var stored Account
+		err = sess.Get(&stored, account.ID)
+		s.NoError(err)
+		s.Equal(account.Name, stored.Name)
+	}
+
+	// Existing records are updated together, each with its own values.
+	for i, account := range keyless {
+		account.Name = fmt.Sprintf("Updated %d", i+1)
+	}
+	err = sess.SaveAll(keyless[0], keyless[1], keyless[2], &existing)
+	s.NoError(err)
+
+	for i, account := range keyless {
+		var stored Account
+		err = sess.Get(&stored, account.ID)
+		s.NoError(err)
+		s.Equal(fmt.Sprintf("Updated %d", i+1), stored.Name)
+	}
+
+	err = sess.Get(&updated, existing.ID)
+	s.NoError(err)
+	s.Equal("Renamed", updated.Name)
+
+	err = sess.SaveAll(&existing, nil)
+	s.True(errors.Is(err, db.ErrNilRecord))
+}
+
+func (s *RecordTestSuite) TestDeleteAll() {
+	sess := s.Session()
+
+	accounts := []db.Record{
+		&Account{Name: "Ann"},
+		&Account{Name: "Bob"},
+		&Account{Name: "Cid"},
+	}
+	err := sess.SaveAll(accounts...)
+	s.NoError(err)
+
+	err = sess.DeleteAll(accounts[0], accounts[2])
+	s.NoError(err)
+
+	var remaining []Account
+	err = Accounts(sess).Find().All(&remaining)
+	s.NoError(err)
+	if s.Len(remaining, 1) {
+		s.Equal("Bob", remaining[0].Name)
+	}
+
+	_, err = sess.SQL().DropTable("soft_delete_books").IfExists().Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		CreateTable("soft_delete_books").
+		Column("id", db.TypeSerial).
+		Column("title", db.TypeString, db.Size(60), db.NotNull()).
+		Column("deleted_at", db.TypeTimestamp).
+		PrimaryKey("id").
+		Exec()
+	s.NoError(err)
+
+	dune, solaris := Book{Title: "Dune"}, Book{Title: "Solaris"}
+	err = sess.SaveAll(&dune, &solaris)
+	s.NoError(err)
+
+	err = sess.DeleteAll(&dune, &solaris)
+	s.NoError(err)
+	s.NotNil(dune.DeletedAt)
+	s.NotNil(solaris.DeletedAt)
+
+	sess, err = db.WithStores(sess, (&Book{}).Store(sess))
+	s.NoError(err)
+
+	count, err := (&Book{}).Store(sess).Find().Count()
+	s.NoError(err)
+	s.Zero(count)
+
+	count, err = (&Book{}).Store(sess).Find().WithDeleted().Count()
+	s.NoError(err)
+	s.Equal(uint64(2), count)
+}
+
 func (s *RecordTestSuite) TestUnknownCollection() {
 	var err error
 	sess := s.Session()
@@ -426,3 +1211,30 @@ func (s *RecordTestSuite) TestContextCanceled() {
 		s.Equal(uint64(0), c)
 	}
 }
+
+func (s *RecordTestSuite) TestFieldCodec() {
+	sess := s.Session()
+
+	account := ProfileAccount{Profile: AccountProfile{First: "Ringo", Last: "Starr"}}
+	err := sess.Save(&account)
+	s.NoError(err)
+
+	var name string
+	err = sess.SQL().Select("name").From("accounts").Where("id", account.ID).One(&name)
+	s.NoError(err)
+	s.Equal(`{"first":"Ringo","last":"Starr"}`, name)
+
+	var loaded ProfileAccount
+	err = sess.Get(&loaded, account.ID)
+	s.NoError(err)
+	s.Equal(account.Profile, loaded.Profile)
+
+	// Only the changed column is updated, encoded with the codec of its field.
+	loaded.Profile.First = "Richard"
+	err = sess.Save(&loaded)
+	s.NoError(err)
+
+	err = sess.SQL().Select("name").From("accounts").Where("id", account.ID).One(&name)
+	s.NoError(err)
+	s.Equal(`{"first":"Richard","last":"Starr"}`, name)
+}
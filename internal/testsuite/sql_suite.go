@@ -1,11 +1,13 @@
 package testsuite
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +17,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/codegen"
 )
 
 type artistType struct {
@@ -22,6 +25,54 @@ type artistType struct {
 	Name string `db:"name"`
 }
 
+// shout is stored in upper case by shoutCodec.
+type shout string
+
+type shoutCodec struct{}
+
+// reviewKeys provides the keys of TestEncryptedFields.
+type reviewKeys struct {
+	current string
+	keys    map[string][]byte
+}
+
+func (k *reviewKeys) CurrentKey() (string, []byte, error) {
+	return k.current, k.keys[k.current], nil
+}
+
+func (k *reviewKeys) Key(id string) ([]byte, error) {
+	if key, ok := k.keys[id]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", id)
+}
+
+func (k *reviewKeys) KeyIDs() ([]string, error) {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (shoutCodec) Encode(v interface{}) (interface{}, error) {
+	return strings.ToUpper(string(v.(shout))), nil
+}
+
+func (shoutCodec) Decode(src interface{}, dst interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*dst.(*shout) = ""
+	case []byte:
+		*dst.(*shout) = shout(strings.ToLower(string(v)))
+	case string:
+		*dst.(*shout) = shout(strings.ToLower(v))
+	default:
+		return fmt.Errorf("can't decode %T", src)
+	}
+	return nil
+}
+
 type itemWithCompoundKey struct {
 	Code    string `db:"code"`
 	UserID  string `db:"user_id"`
@@ -1975,3 +2026,1345 @@ func (s *SQLTestSuite) TestSelectFromSubquery() {
 	}
 
 }
+
+func (s *SQLTestSuite) TestSchema() {
+	sess := s.Session()
+
+	tables, err := sess.Schema().Tables()
+	s.NoError(err)
+
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	s.Contains(names, "artist")
+
+	artist, err := sess.Schema().Table("artist")
+	s.NoError(err)
+	s.Equal("artist", artist.Name)
+	s.Equal(1, len(artist.PrimaryKey))
+
+	s.NotNil(artist.Column(artist.PrimaryKey[0]))
+	s.NotNil(artist.Column("name"))
+	s.Nil(artist.Column("does_not_exist"))
+
+	s.NotEmpty(artist.Column("name").Type)
+
+	_, err = sess.Schema().Table("does_not_exist")
+	s.Equal(db.ErrCollectionDoesNotExist, err)
+
+	views, err := sess.Schema().Views()
+	s.NoError(err)
+	s.NotNil(views)
+}
+
+func (s *SQLTestSuite) TestDDL() {
+	sess := s.Session()
+
+	_, err := sess.SQL().DropTable("ddl_test").IfExists().Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		CreateTable("ddl_test").
+		Column("id", db.TypeSerial).
+		Column("name", db.TypeString, db.Size(60), db.NotNull()).
+		Column("active", db.TypeBoolean, db.Default(true)).
+		PrimaryKey("id").
+		Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		AlterTable("ddl_test").
+		AddColumn("country", db.TypeString, db.Size(2)).
+		Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		CreateIndex("ddl_test_name_idx").
+		On("ddl_test", "name").
+		Unique().
+		Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().
+		InsertInto("ddl_test").
+		Values(map[string]interface{}{"name": "Hayao Miyazaki", "country": "JP"}).
+		Exec()
+	s.NoError(err)
+
+	var row struct {
+		Name    string `db:"name"`
+		Active  bool   `db:"active"`
+		Country string `db:"country"`
+	}
+	err = sess.SQL().SelectFrom("ddl_test").One(&row)
+	s.NoError(err)
+	s.Equal("Hayao Miyazaki", row.Name)
+	s.Equal("JP", row.Country)
+	s.True(row.Active)
+
+	table, err := sess.Schema().Table("ddl_test")
+	s.NoError(err)
+	s.NotNil(table.Column("country"))
+	var index *db.Index
+	for i := range table.Indexes {
+		if table.Indexes[i].Name == "ddl_test_name_idx" {
+			index = table.Indexes[i]
+		}
+	}
+	if s.NotNil(index) {
+		s.True(index.Unique)
+		s.Equal([]string{"name"}, index.Columns)
+	}
+
+	_, err = sess.SQL().DropIndex("ddl_test_name_idx").On("ddl_test").Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().DropTable("ddl_test").Exec()
+	s.NoError(err)
+}
+
+type diffArtist struct {
+	ID      int64  `db:"id,omitempty"`
+	Name    string `db:"name"`
+	Country string `db:"country"`
+}
+
+func (*diffArtist) Store(sess db.Session) db.Store {
+	return sess.Collection("artist")
+}
+
+type diffGenre struct {
+	ID   int64  `db:"id,omitempty"`
+	Name string `db:"name"`
+}
+
+func (*diffGenre) Store(sess db.Session) db.Store {
+	return sess.Collection("diff_genre")
+}
+
+type diffSnakeArtist struct {
+	ID      int64 `db:"id,omitempty"`
+	Name    string
+	Country string
+}
+
+func (*diffSnakeArtist) Store(sess db.Session) db.Store {
+	return sess.Collection("artist")
+}
+
+func (s *SQLTestSuite) TestSchemaDiff() {
+	sess := s.Session()
+
+	// Not all databases can add NOT NULL columns to tables with data.
+	err := sess.Collection("artist").Truncate()
+	s.NoError(err)
+
+	_, err = sess.SQL().DropTable("diff_genre").IfExists().Exec()
+	s.NoError(err)
+
+	diffs, err := codegen.Diff(sess, s.Adapter(), &diffArtist{}, &diffGenre{})
+	s.NoError(err)
+
+	kinds := map[string]codegen.DifferenceKind{}
+	for _, diff := range diffs {
+		kinds[diff.Table+"."+diff.Column] = diff.Kind
+	}
+	s.Equal(codegen.NullabilityMismatch, kinds["artist.name"])
+	s.Equal(codegen.MissingColumn, kinds["artist.country"])
+	s.Equal(codegen.MissingTable, kinds["diff_genre."])
+
+	statements := codegen.DDL(sess.SQL(), diffs)
+	s.Len(statements, 2)
+	for _, stmt := range statements {
+		_, err = sess.SQL().Exec(stmt)
+		s.NoError(err)
+	}
+
+	diffs, err = codegen.Diff(sess, s.Adapter(), &diffArtist{}, &diffGenre{})
+	s.NoError(err)
+	for _, diff := range diffs {
+		s.NotEqual(codegen.MissingTable, diff.Kind, diff.String())
+		s.NotEqual(codegen.MissingColumn, diff.Kind, diff.String())
+		s.NotEqual(codegen.ExtraColumn, diff.Kind, diff.String())
+	}
+
+	// Fields are mapped to columns like the session maps them.
+	diffs, err = codegen.Diff(sess, s.Adapter(), &diffSnakeArtist{})
+	s.NoError(err)
+	kinds = map[string]codegen.DifferenceKind{}
+	for _, diff := range diffs {
+		kinds[diff.Table+"."+diff.Column] = diff.Kind
+	}
+	s.Equal(codegen.ExtraColumn, kinds["artist.name"])
+
+	snakeSess, err := db.WithFieldMapper(sess, db.FieldMapper{Naming: db.SnakeCase})
+	s.NoError(err)
+
+	diffs, err = codegen.Diff(snakeSess, s.Adapter(), &diffSnakeArtist{})
+	s.NoError(err)
+	for _, diff := range diffs {
+		s.NotEqual(codegen.ExtraColumn, diff.Kind, diff.String())
+	}
+
+	_, err = sess.SQL().AlterTable("artist").DropColumn("country").Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().DropTable("diff_genre").Exec()
+	s.NoError(err)
+}
+
+type preloadArtist struct {
+	ID    int64         `db:"id,omitempty"`
+	Name  string        `db:"name"`
+	Posts []preloadPost `db:"-" rel:"has_many,fk=artist_id"`
+}
+
+func (*preloadArtist) Store(sess db.Session) db.Store {
+	return sess.Collection("artist")
+}
+
+type preloadPost struct {
+	Code     int64            `db:"code"`
+	ArtistID int64            `db:"artist_id"`
+	Title    string           `db:"title"`
+	Artist   *preloadArtist   `db:"-" rel:"belongs_to,fk=artist_id"`
+	Tags     []*preloadTag    `db:"-" rel:"many_to_many,through=preload_post_tag,fk=post_code,references=tag_code,key=code"`
+	Comments []preloadComment `db:"-"`
+}
+
+func (*preloadPost) Store(sess db.Session) db.Store {
+	return sess.Collection("preload_post")
+}
+
+func (*preloadPost) Relations() db.Relations {
+	return db.Relations{
+		"Comments": {Kind: db.RelationHasMany, ForeignKey: "post_code", Key: "code", Table: "preload_comment"},
+	}
+}
+
+type preloadTag struct {
+	Code int64  `db:"code"`
+	Name string `db:"name"`
+}
+
+func (*preloadTag) Store(sess db.Session) db.Store {
+	return sess.Collection("preload_tag")
+}
+
+type preloadComment struct {
+	Code     int64  `db:"code"`
+	PostCode int64  `db:"post_code"`
+	Body     string `db:"body"`
+}
+
+func (s *SQLTestSuite) TestPreload() {
+	sess := s.Session()
+
+	tables := map[string][]string{
+		"preload_post":     {"code", "artist_id", "title"},
+		"preload_tag":      {"code", "name"},
+		"preload_post_tag": {"post_code", "tag_code"},
+		"preload_comment":  {"code", "post_code", "body"},
+	}
+	for table, columns := range tables {
+		_, err := sess.SQL().DropTable(table).IfExists().Exec()
+		s.NoError(err)
+
+		create := sess.SQL().CreateTable(table)
+		for _, column := range columns {
+			if column == "title" || column == "name" || column == "body" {
+				create = create.Column(column, db.TypeString, db.Size(60))
+			} else {
+				create = create.Column(column, db.TypeBigInteger)
+			}
+		}
+		_, err = create.Exec()
+		s.NoError(err)
+	}
+
+	err := sess.Collection("artist").Truncate()
+	s.NoError(err)
+
+	artists := map[string]int64{}
+	for _, name := range []string{"Frida Kahlo", "Diego Rivera", "Remedios Varo"} {
+		res, err := sess.Collection("artist").Insert(map[string]string{"name": name})
+		s.NoError(err)
+		artists[name] = res.ID().(int64)
+	}
+
+	rows := map[string][][]interface{}{
+		"preload_post": {
+			{1, artists["Frida Kahlo"], "Self-portraits"},
+			{2, artists["Frida Kahlo"], "The Two Fridas"},
+			{3, artists["Diego Rivera"], "Murals"},
+		},
+		"preload_tag": {
+			{1, "painting"},
+			{2, "mexico"},
+		},
+		"preload_post_tag": {
+			{1, 1},
+			{1, 2},
+			{3, 2},
+		},
+		"preload_comment": {
+			{1, 1, "Beautiful"},
+			{2, 1, "Striking"},
+			{3, 3, "Huge"},
+		},
+	}
+	for table, values := range rows {
+		for _, row := range values {
+			_, err := sess.SQL().InsertInto(table).Columns(tables[table]...).Values(row...).Exec()
+			s.NoError(err)
+		}
+	}
+
+	var posts []preloadPost
+	err = sess.Collection("preload_post").
+		Find().
+		OrderBy("code").
+		Preload("Artist", "Tags", "Comments").
+		All(&posts)
+	s.NoError(err)
+	s.Len(posts, 3)
+
+	s.Equal("Frida Kahlo", posts[0].Artist.Name)
+	s.Equal("Frida Kahlo", posts[1].Artist.Name)
+	s.Equal("Diego Rivera", posts[2].Artist.Name)
+
+	s.Len(posts[0].Tags, 2)
+	s.Len(posts[1].Tags, 0)
+	s.Len(posts[2].Tags, 1)
+	s.Equal("mexico", posts[2].Tags[0].Name)
+
+	s.Len(posts[0].Comments, 2)
+	s.NotNil(posts[1].Comments)
+	s.Len(posts[1].Comments, 0)
+	s.Equal("Huge", posts[2].Comments[0].Body)
+
+	var artist preloadArtist
+	err = sess.Collection("artist").
+		Find(db.Cond{"name": "Frida Kahlo"}).
+		Preload("Posts.Tags", "Posts.Comments").
+		One(&artist)
+	s.NoError(err)
+	s.Len(artist.Posts, 2)
+	for _, post := range artist.Posts {
+		switch post.Title {
+		case "Self-portraits":
+			s.Len(post.Tags, 2)
+			s.Len(post.Comments, 2)
+		case "The Two Fridas":
+			s.Len(post.Tags, 0)
+			s.Len(post.Comments, 0)
+		default:
+			s.Fail("unexpected post", post.Title)
+		}
+	}
+
+	var nobody []*preloadArtist
+	err = sess.Collection("artist").
+		Find(db.Cond{"name": "Remedios Varo"}).
+		Preload("Posts").
+		All(&nobody)
+	s.NoError(err)
+	if s.Len(nobody, 1) {
+		s.Len(nobody[0].Posts, 0)
+	}
+
+	err = sess.Collection("artist").Find().Preload("Albums").All(&nobody)
+	s.True(errors.Is(err, db.ErrUnknownRelation))
+
+	for table := range tables {
+		_, err = sess.SQL().DropTable(table).Exec()
+		s.NoError(err)
+	}
+}
+
+type scopedArtistsStore struct {
+	db.Collection
+}
+
+func (*scopedArtistsStore) Scopes() db.Scopes {
+	return db.Scopes{
+		db.DefaultScope: func(res db.Result, args ...interface{}) db.Result {
+			return res.And(db.Cond{"name": db.NotEq("Chrono")})
+		},
+		"named": func(res db.Result, args ...interface{}) db.Result {
+			return res.And(db.Cond{"name": db.In(args...)})
+		},
+	}
+}
+
+var _ = db.HasScopes(&scopedArtistsStore{})
+
+type scopedArtist struct {
+	ID   int64  `db:"id,omitempty"`
+	Name string `db:"name"`
+}
+
+func (*scopedArtist) Store(sess db.Session) db.Store {
+	return &scopedArtistsStore{sess.Collection("artist")}
+}
+
+func (s *SQLTestSuite) TestScopes() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	for _, name := range []string{"Ozzie", "Flea", "Slash", "Chrono"} {
+		_, err := artist.Insert(map[string]string{"name": name})
+		s.NoError(err)
+	}
+
+	sess, err = db.WithStores(sess, &scopedArtistsStore{sess.Collection("artist")})
+	s.NoError(err)
+
+	artist = sess.Collection("artist")
+
+	count, err := s.Session().Collection("artist").Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(4), count)
+
+	count, err = artist.Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(3), count)
+
+	count, err = artist.Find().Unscoped().Count()
+	s.NoError(err)
+	s.Equal(uint64(4), count)
+
+	var artists []artistType
+	err = artist.Find().Scope("named", "Slash", "Chrono").All(&artists)
+	s.NoError(err)
+	if s.Len(artists, 1) {
+		s.Equal("Slash", artists[0].Name)
+	}
+
+	err = artist.Find().Scope("named", "Slash", "Chrono").Unscoped().OrderBy("name").All(&artists)
+	s.NoError(err)
+	s.Len(artists, 2)
+
+	err = artist.Find().Scope("unknown").All(&artists)
+	s.True(errors.Is(err, db.ErrUnknownScope))
+
+	if s.Adapter() == "ql" {
+		// ql has no id column to load records by.
+		return
+	}
+
+	// Records are saved and loaded by key whatever their scope.
+	var rec scopedArtist
+	err = sess.Get(&rec, db.Cond{"name": "Ozzie"})
+	s.NoError(err)
+
+	rec.Name = "Chrono"
+	err = sess.Save(&rec)
+	s.NoError(err)
+	s.Equal("Chrono", rec.Name)
+
+	err = sess.Reload(&rec)
+	s.NoError(err)
+	s.Equal("Chrono", rec.Name)
+
+	exists, err := sess.Exists(&rec)
+	s.NoError(err)
+	s.True(exists)
+
+	err = sess.Get(&rec, rec.ID)
+	s.NoError(err)
+
+	err = sess.Delete(&rec)
+	s.NoError(err)
+
+	count, err = artist.Find().Unscoped().Count()
+	s.NoError(err)
+	s.Equal(uint64(3), count)
+}
+
+func (s *SQLTestSuite) TestTenancy() {
+	sess := s.Session()
+
+	_, err := sess.SQL().DropTable("tenant_note").IfExists().Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().CreateTable("tenant_note").
+		Column("code", db.TypeBigInteger).
+		Column("tenant_id", db.TypeBigInteger).
+		Column("body", db.TypeString, db.Size(60)).
+		Exec()
+	s.NoError(err)
+
+	type tenantNote struct {
+		Code     int64  `db:"code"`
+		TenantID int64  `db:"tenant_id"`
+		Body     string `db:"body"`
+	}
+
+	tenantSess, err := db.WithTenancy(sess, db.Tenancy{
+		Column: "tenant_id",
+		Tables: []string{"tenant_note"},
+	})
+	s.NoError(err)
+
+	acme := tenantSess.WithContext(db.ContextWithTenant(context.Background(), int64(1)))
+	globex := tenantSess.WithContext(db.ContextWithTenant(context.Background(), int64(2)))
+
+	_, err = acme.Collection("tenant_note").Insert(tenantNote{Code: 1, Body: "first"})
+	s.NoError(err)
+
+	// The tenant column is always set to the current tenant.
+	_, err = acme.SQL().InsertInto("tenant_note").
+		Values(map[string]interface{}{"code": 2, "tenant_id": 2, "body": "second"}).
+		Exec()
+	s.NoError(err)
+
+	_, err = globex.Collection("tenant_note").Insert(tenantNote{Code: 3, Body: "third"})
+	s.NoError(err)
+
+	count, err := sess.Collection("tenant_note").Find(db.Cond{"tenant_id": 1}).Count()
+	s.NoError(err)
+	s.Equal(uint64(2), count)
+
+	var notes []tenantNote
+	err = acme.Collection("tenant_note").Find().OrderBy("code").All(&notes)
+	s.NoError(err)
+	if s.Len(notes, 2) {
+		s.Equal(int64(1), notes[0].Code)
+		s.Equal(int64(2), notes[1].Code)
+		s.Equal(int64(1), notes[1].TenantID)
+	}
+
+	count, err = globex.Collection("tenant_note").Find(db.Cond{"code": 1}).Count()
+	s.NoError(err)
+	s.Equal(uint64(0), count)
+
+	err = acme.SQL().SelectFrom("tenant_note").Where("code > ?", 1).All(&notes)
+	s.NoError(err)
+	s.Len(notes, 1)
+
+	if s.Adapter() != "ql" {
+		// Joined tables are filtered as well.
+		err = acme.SQL().Select("a.code").
+			From("tenant_note AS a").
+			Join("tenant_note AS b").On("b.code = ?", 3).
+			All(&notes)
+		s.NoError(err)
+		s.Len(notes, 0)
+
+		err = globex.SQL().Select("a.code").
+			From("tenant_note AS a").
+			Join("tenant_note AS b").On("b.code = ?", 3).
+			All(&notes)
+		s.NoError(err)
+		s.Len(notes, 1)
+	}
+
+	err = globex.Collection("tenant_note").Find().Update(map[string]interface{}{"body": "changed"})
+	s.NoError(err)
+
+	count, err = sess.Collection("tenant_note").Find(db.Cond{"body": "changed"}).Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	err = acme.Collection("tenant_note").Find().Update(map[string]interface{}{"tenant_id": 2})
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	if s.Adapter() != "ql" {
+		_, err = sess.SQL().CreateIndex("tenant_note_code_idx").On("tenant_note", "code").Unique().Exec()
+		s.NoError(err)
+
+		// Upserts only update the rows of the current tenant.
+		_, err = acme.SQL().InsertInto("tenant_note").
+			Values(map[string]interface{}{"code": 1, "body": "upserted"}).
+			OnConflictUpdate([]string{"code"}, "body").
+			Exec()
+		s.NoError(err)
+
+		_, err = acme.SQL().InsertInto("tenant_note").
+			Values(map[string]interface{}{"code": 3, "body": "hijacked"}).
+			OnConflictUpdate([]string{"code"}, "body").
+			Exec()
+		s.NoError(err)
+
+		_, err = acme.SQL().InsertInto("tenant_note").
+			Values(map[string]interface{}{"code": 3, "body": "hijacked"}).
+			OnConflictUpdate([]string{"code"}, "body", "tenant_id").
+			Exec()
+		s.True(errors.Is(err, db.ErrTenantIsolation))
+
+		count, err = sess.Collection("tenant_note").Find(db.Cond{"code": 1, "body": "upserted", "tenant_id": 1}).Count()
+		s.NoError(err)
+		s.Equal(uint64(1), count)
+
+		count, err = sess.Collection("tenant_note").Find(db.Cond{"code": 3, "body": "changed", "tenant_id": 2}).Count()
+		s.NoError(err)
+		s.Equal(uint64(1), count)
+	}
+
+	_, err = globex.SQL().DeleteFrom("tenant_note").Exec()
+	s.NoError(err)
+
+	count, err = sess.Collection("tenant_note").Find().Count()
+	s.NoError(err)
+	s.Equal(uint64(2), count)
+
+	_, err = acme.SQL().Query("SELECT * FROM tenant_note")
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	// Comments don't hide tables from raw queries.
+	_, err = acme.SQL().Query("SELECT * FROM/**/tenant_note")
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	_, err = acme.SQL().Query("SELECT * FROM -- notes\ntenant_note")
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	// Raw fragments in the select list, ORDER BY and GROUP BY are checked
+	// too.
+	var leaked []map[string]interface{}
+	err = acme.SQL().
+		Select(db.Raw("(SELECT count(1) FROM tenant_note) AS n")).
+		From("artist").
+		All(&leaked)
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	err = acme.SQL().
+		SelectFrom("artist").
+		OrderBy(db.Raw("(SELECT count(1) FROM tenant_note)")).
+		All(&leaked)
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	err = acme.SQL().
+		Select("name").
+		From("artist").
+		GroupBy(db.Raw("(SELECT count(1) FROM tenant_note)")).
+		All(&leaked)
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	rows, err := acme.WithContext(db.ContextAllowingRaw(acme.Context())).SQL().Query("SELECT * FROM tenant_note")
+	if s.NoError(err) {
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		s.NoError(rows.Err())
+		s.Equal(2, n)
+		s.NoError(rows.Close())
+	}
+
+	err = acme.Collection("tenant_note").Truncate()
+	s.True(errors.Is(err, db.ErrTenantIsolation))
+
+	_, err = tenantSess.Collection("tenant_note").Find().Count()
+	s.True(errors.Is(err, db.ErrMissingTenant))
+
+	_, err = sess.SQL().DropTable("tenant_note").Exec()
+	s.NoError(err)
+}
+
+func (s *SQLTestSuite) TestStrictScan() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	_, err = artist.Insert(map[string]string{"name": "Ozzie"})
+	s.NoError(err)
+
+	var artists []artistType
+	err = artist.Find().Strict().All(&artists)
+	s.NoError(err)
+	s.Len(artists, 1)
+
+	type artistWithoutName struct {
+		ID int64 `db:"id,omitempty"`
+	}
+
+	var noName artistWithoutName
+	err = artist.Find().One(&noName)
+	s.NoError(err)
+
+	err = artist.Find().Strict().One(&noName)
+	s.True(errors.Is(err, db.ErrStrictScan))
+	if scanErr := (*db.ScanError)(nil); s.True(errors.As(err, &scanErr)) {
+		s.Equal([]string{"name"}, scanErr.UnmappedColumns)
+		s.Empty(scanErr.MissingFields)
+	}
+
+	type artistWithGenre struct {
+		ID    int64  `db:"id,omitempty"`
+		Name  string `db:"name"`
+		Genre string `db:"genre"`
+	}
+
+	strictSess, err := db.WithStrictScan(sess)
+	s.NoError(err)
+
+	var withGenre []artistWithGenre
+	err = strictSess.Collection("artist").Find().All(&withGenre)
+	s.True(errors.Is(err, db.ErrStrictScan))
+	if scanErr := (*db.ScanError)(nil); s.True(errors.As(err, &scanErr)) {
+		s.Empty(scanErr.UnmappedColumns)
+		s.Equal([]string{"genre"}, scanErr.MissingFields)
+	}
+
+	var oneWithGenre artistWithGenre
+	res := strictSess.Collection("artist").Find()
+	s.False(res.Next(&oneWithGenre))
+	s.True(errors.Is(res.Err(), db.ErrStrictScan))
+	s.NoError(res.Close())
+
+	var names []struct {
+		Name string `db:"name"`
+	}
+	err = strictSess.SQL().Select("name").From("artist").All(&names)
+	s.NoError(err)
+	s.Len(names, 1)
+
+	// Maps are not affected.
+	var rows []map[string]interface{}
+	err = strictSess.Collection("artist").Find().All(&rows)
+	s.NoError(err)
+	s.Len(rows, 1)
+}
+
+func (s *SQLTestSuite) TestScanScalars() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	for _, name := range []string{"Ozzie", "Flea", "Slash"} {
+		_, err := artist.Insert(map[string]string{"name": name})
+		s.NoError(err)
+	}
+
+	var names []string
+	err = artist.Find().OrderBy("name").Pluck("name", &names)
+	s.NoError(err)
+	s.Equal([]string{"Flea", "Ozzie", "Slash"}, names)
+
+	var name string
+	err = artist.Find(db.Cond{"name": "Slash"}).Select("name").One(&name)
+	s.NoError(err)
+	s.Equal("Slash", name)
+
+	var nullName sql.NullString
+	err = sess.SQL().Select("name").From("artist").Where("name", "Flea").One(&nullName)
+	s.NoError(err)
+	s.Equal(sql.NullString{String: "Flea", Valid: true}, nullName)
+
+	var namePtrs []*string
+	err = sess.SQL().Select("name").From("artist").OrderBy("name").All(&namePtrs)
+	s.NoError(err)
+	if s.Len(namePtrs, 3) {
+		s.Equal("Flea", *namePtrs[0])
+	}
+
+	var count int64
+	err = sess.SQL().Select(db.Raw("count(1)")).From("artist").One(&count)
+	s.NoError(err)
+	s.Equal(int64(3), count)
+
+	iter := sess.SQL().Select("name").From("artist").OrderBy("name").Iterator()
+	names = names[:0]
+	for iter.Next(&name) {
+		names = append(names, name)
+	}
+	s.NoError(iter.Err())
+	s.NoError(iter.Close())
+	s.Equal([]string{"Flea", "Ozzie", "Slash"}, names)
+
+	err = artist.Find().One(&name)
+	s.True(errors.Is(err, db.ErrExpectingSingleColumn))
+}
+
+func (s *SQLTestSuite) TestNestedStructColumns() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+	publication := sess.Collection("publication")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	err = publication.Truncate()
+	s.NoError(err)
+
+	type nestedArtist struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	type artistAlias struct {
+		Artist nestedArtist `db:"artist"`
+	}
+
+	res, err := artist.Insert(map[string]string{"name": "Ozzie"})
+	s.NoError(err)
+
+	var alias artistAlias
+	err = sess.SQL().Select(db.Raw("name AS artist__name")).From("artist").One(&alias)
+	s.NoError(err)
+	s.Equal("Ozzie", alias.Artist.Name)
+
+	if s.Adapter() == "ql" {
+		return
+	}
+
+	authorID := res.ID()
+
+	_, err = publication.Insert(map[string]interface{}{"title": "Blizzard", "author_id": authorID})
+	s.NoError(err)
+
+	_, err = publication.Insert(map[string]interface{}{"title": "Orphan", "author_id": 999})
+	s.NoError(err)
+
+	type publicationWithAuthor struct {
+		Title  string        `db:"title"`
+		Author *nestedArtist `db:"author"`
+	}
+
+	var publications []publicationWithAuthor
+	err = sess.SQL().
+		Select("p.title", "a.id AS author__id", "a.name AS author__name").
+		From("publication AS p").
+		LeftJoin("artist AS a").On("a.id = p.author_id").
+		OrderBy("p.title").
+		All(&publications)
+	s.NoError(err)
+	if s.Len(publications, 2) {
+		s.Equal("Blizzard", publications[0].Title)
+		if s.NotNil(publications[0].Author) {
+			s.Equal("Ozzie", publications[0].Author.Name)
+			s.Equal(authorID, publications[0].Author.ID)
+		}
+		s.Equal("Orphan", publications[1].Title)
+		s.Nil(publications[1].Author)
+	}
+
+	if s.Adapter() != "mysql" {
+		var publication publicationWithAuthor
+		err = sess.SQL().
+			Select("p.title", db.Raw(`a.name AS "author.name"`)).
+			From("publication AS p").
+			Join("artist AS a").On("a.id = p.author_id").
+			One(&publication)
+		s.NoError(err)
+		if s.NotNil(publication.Author) {
+			s.Equal("Ozzie", publication.Author.Name)
+		}
+	}
+}
+
+func (s *SQLTestSuite) TestStreamResults() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	names := []string{"Chrono", "Flea", "Ozzie", "Slash", "Tony"}
+	for _, name := range names {
+		_, err := artist.Insert(map[string]string{"name": name})
+		s.NoError(err)
+	}
+
+	var visited []string
+	err = artist.Find().OrderBy("name").Each(func(a *artistType) error {
+		visited = append(visited, a.Name)
+		return nil
+	})
+	s.NoError(err)
+	s.Equal(names, visited)
+
+	visited = visited[:0]
+	err = artist.Find().OrderBy("name").Each(func(a artistType) error {
+		if a.Name == "Ozzie" {
+			return db.ErrNoMoreRows
+		}
+		visited = append(visited, a.Name)
+		return nil
+	})
+	s.True(errors.Is(err, db.ErrNoMoreRows))
+	s.Equal([]string{"Chrono", "Flea"}, visited)
+
+	var batches [][]string
+	err = artist.Find().OrderBy("name").Select("name").Chunk(2, func(batch []string) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([][]string{{"Chrono", "Flea"}, {"Ozzie", "Slash"}, {"Tony"}}, batches)
+
+	err = artist.Find().Chunk(0, func([]artistType) error { return nil })
+	s.True(errors.Is(err, db.ErrInvalidChunkSize))
+
+	err = artist.Find().Each(func(a *artistType) {})
+	s.True(errors.Is(err, db.ErrInvalidCallback))
+
+	visited = visited[:0]
+	items, errs := sess.SQL().SelectFrom("artist").OrderBy("name").Iterator().Stream(context.Background(), &artistType{})
+	for item := range items {
+		visited = append(visited, item.(*artistType).Name)
+	}
+	s.NoError(<-errs)
+	s.Equal(names, visited)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	items, errs = sess.SQL().SelectFrom("artist").OrderBy("name").Iterator().Stream(ctx, &artistType{})
+	item := <-items
+	s.Equal("Chrono", item.(*artistType).Name)
+	cancel()
+	for range items {
+	}
+	s.True(errors.Is(<-errs, context.Canceled))
+
+	cancelledSess := sess.WithContext(ctx)
+	err = cancelledSess.Collection("artist").Find().Each(func(a *artistType) error {
+		return nil
+	})
+	s.True(errors.Is(err, context.Canceled))
+}
+
+func (s *SQLTestSuite) TestCodecs() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	type artistWithPoint struct {
+		Name point `db:"name,codec=json"`
+	}
+
+	_, err = artist.Insert(artistWithPoint{Name: point{X: 1, Y: 2}})
+	s.NoError(err)
+
+	var name string
+	err = artist.Find().Select("name").One(&name)
+	s.NoError(err)
+	s.Equal(`{"x":1,"y":2}`, name)
+
+	var withPoint artistWithPoint
+	err = artist.Find().Select("name").One(&withPoint)
+	s.NoError(err)
+	s.Equal(point{X: 1, Y: 2}, withPoint.Name)
+
+	type artistWithUnknownCodec struct {
+		Name point `db:"name,codec=unknown"`
+	}
+	err = artist.Find().Select("name").One(&artistWithUnknownCodec{})
+	s.True(errors.Is(err, db.ErrUnknownCodec))
+
+	err = artist.Truncate()
+	s.NoError(err)
+
+	codecs := &db.Codecs{}
+	codecs.Register(shout(""), shoutCodec{})
+
+	codecSess, err := db.WithCodecs(sess, codecs)
+	s.NoError(err)
+
+	type artistWithShout struct {
+		Name shout `db:"name"`
+	}
+
+	type artistWithShoutPtr struct {
+		Name *shout `db:"name"`
+	}
+
+	_, err = codecSess.Collection("artist").Insert(artistWithShout{Name: "ozzie"})
+	s.NoError(err)
+
+	_, err = codecSess.SQL().InsertInto("artist").Values(artistWithShoutPtr{}).Exec()
+	s.NoError(err)
+
+	var names []*string
+	err = artist.Find().OrderBy("name").Pluck("name", &names)
+	s.NoError(err)
+	if s.Len(names, 2) {
+		s.Nil(names[0])
+		s.Equal("OZZIE", *names[1])
+	}
+
+	var shouts []*shout
+	err = codecSess.Collection("artist").Find().OrderBy("name").Pluck("name", &shouts)
+	s.NoError(err)
+	if s.Len(shouts, 2) {
+		s.Nil(shouts[0])
+		s.Equal(shout("ozzie"), *shouts[1])
+	}
+
+	var withShout artistWithShout
+	err = codecSess.Collection("artist").Find(db.Cond{"name": "OZZIE"}).Select("name").One(&withShout)
+	s.NoError(err)
+	s.Equal(shout("ozzie"), withShout.Name)
+
+	err = codecSess.Collection("artist").Find(db.Cond{"name": "OZZIE"}).Update(artistWithShout{Name: "flea"})
+	s.NoError(err)
+
+	err = artist.Find(db.Cond{"name IS NOT": nil}).Select("name").One(&name)
+	s.NoError(err)
+	s.Equal("FLEA", name)
+}
+
+func (s *SQLTestSuite) TestNormalizedMaps() {
+	sess := s.Session()
+
+	birthdays := sess.Collection("birthdays")
+
+	err := birthdays.Truncate()
+	s.NoError(err)
+
+	born := time.Date(1941, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	_, err = birthdays.Insert(birthday{Name: "Hayao Miyazaki", Born: born, BornUT: &unixTimestamp{born}})
+	s.NoError(err)
+
+	var row map[string]interface{}
+	err = sess.SQL().Select("name", "born", "born_ut").From("birthdays").One(&row)
+	s.NoError(err)
+
+	s.Equal("Hayao Miyazaki", row["name"])
+	s.IsType(int64(0), row["born_ut"])
+	if s.IsType(time.Time{}, row["born"]) {
+		s.True(born.Equal(row["born"].(time.Time)))
+	}
+
+	var rows []map[string]interface{}
+	err = birthdays.Find().Select("name").All(&rows)
+	s.NoError(err)
+	if s.Len(rows, 1) {
+		s.Equal("Hayao Miyazaki", rows[0]["name"])
+	}
+
+	err = birthdays.Truncate()
+	s.NoError(err)
+}
+
+func (s *SQLTestSuite) TestExport() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	for _, name := range []string{"Ozzie", "Flea", "Slash"} {
+		_, err = artist.Insert(artistType{Name: name})
+		s.NoError(err)
+	}
+
+	var buf bytes.Buffer
+	err = db.ExportCSV(&buf, artist.Find().Select("name").OrderBy("name"))
+	s.NoError(err)
+	s.Equal("name\nFlea\nOzzie\nSlash\n", buf.String())
+
+	buf.Reset()
+	err = db.ExportJSONLines(&buf, sess.SQL().Select("name").From("artist").OrderBy("name").Iterator())
+	s.NoError(err)
+	s.Equal(`{"name":"Flea"}`+"\n"+`{"name":"Ozzie"}`+"\n"+`{"name":"Slash"}`+"\n", buf.String())
+
+	var names []string
+	var sizes []int
+	err = db.ExportColumnar(artist.Find().Select("name").OrderBy("name"), 2, func(batch *db.ColumnBatch) error {
+		sizes = append(sizes, batch.Len)
+		values := reflect.ValueOf(batch.Values[0])
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, fmt.Sprintf("%v", values.Index(i).Interface()))
+		}
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([]int{2, 1}, sizes)
+	s.Equal([]string{"Flea", "Ozzie", "Slash"}, names)
+
+	err = db.ExportCSV(&buf, sess.SQL().Select("name").From("unknown_table").Iterator())
+	s.Error(err)
+}
+
+func (s *SQLTestSuite) TestImport() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+
+	err := artist.Truncate()
+	s.NoError(err)
+
+	var progress []db.ImportStats
+	stats, err := db.Import(artist, strings.NewReader("Artist Name,Genre\nOzzie,metal\nFlea,funk\nSlash,rock\n"), db.ImportOptions{
+		Columns:   map[string]string{"Artist Name": "name", "Genre": "-"},
+		BatchSize: 2,
+		Progress: func(stats db.ImportStats) {
+			progress = append(progress, stats)
+		},
+	})
+	s.NoError(err)
+	s.Equal(db.ImportStats{Read: 3, Imported: 3}, stats)
+	s.Equal([]db.ImportStats{{Read: 2, Imported: 2}, {Read: 3, Imported: 3}}, progress)
+
+	var names []string
+	err = artist.Find().OrderBy("name").Pluck("name", &names)
+	s.NoError(err)
+	s.Equal([]string{"Flea", "Ozzie", "Slash"}, names)
+
+	var rejects bytes.Buffer
+	stats, err = db.Import(artist, strings.NewReader("name\nAnthony\n\"Chad\",\"Smith\"\n"), db.ImportOptions{
+		Rejects: &rejects,
+	})
+	s.NoError(err)
+	s.Equal(db.ImportStats{Read: 2, Imported: 1, Rejected: 1}, stats)
+	s.True(strings.HasPrefix(rejects.String(), "name,error\nChad,Smith,"))
+
+	_, err = db.Import(artist, strings.NewReader("name\nAnthony\n\"Chad\",\"Smith\"\n"), db.ImportOptions{})
+	s.Error(err)
+
+	publication := sess.Collection("publication")
+
+	err = publication.Truncate()
+	s.NoError(err)
+
+	rejects.Reset()
+	stats, err = db.Import(publication, strings.NewReader(`{"title": "Canaan", "author_id": 1}
+{"title": "Sebastian", "author_id": "two"}
+{"title": "Silas",
+{"title": "The Garden", "author_id": "3", "pages": 120}
+`), db.ImportOptions{
+		Format:  db.ImportJSONLines,
+		Columns: map[string]string{"pages": "-"},
+		Types:   map[string]reflect.Type{"author_id": reflect.TypeOf(int64(0))},
+		Rejects: &rejects,
+	})
+	s.NoError(err)
+	s.Equal(db.ImportStats{Read: 4, Imported: 2, Rejected: 2}, stats)
+
+	lines := strings.Split(strings.TrimSpace(rejects.String()), "\n")
+	if s.Len(lines, 2) {
+		s.True(strings.HasPrefix(lines[0], `{"line":2,"error":`))
+		s.True(strings.HasSuffix(lines[0], `"row":{"title":"Sebastian","author_id":"two"}}`))
+		s.True(strings.HasSuffix(lines[1], `"row":"{\"title\": \"Silas\","}`))
+	}
+
+	var authorIDs []int64
+	err = publication.Find().OrderBy("author_id").Pluck("author_id", &authorIDs)
+	s.NoError(err)
+	s.Equal([]int64{1, 3}, authorIDs)
+
+	err = publication.Truncate()
+	s.NoError(err)
+
+	switch s.Adapter() {
+	case "ql", "mssql":
+		_, err = db.Import(artist, strings.NewReader("id,name\n1,Ozzy\n"), db.ImportOptions{
+			ConflictColumns: []string{"id"},
+		})
+		s.True(errors.Is(err, db.ErrNotSupportedByAdapter))
+		return
+	}
+
+	err = artist.Truncate()
+	s.NoError(err)
+
+	stats, err = db.Import(artist, strings.NewReader("id,name\n1,Ozzie\n2,Flea\n"), db.ImportOptions{})
+	s.NoError(err)
+	s.Equal(db.ImportStats{Read: 2, Imported: 2}, stats)
+
+	stats, err = db.Import(artist, strings.NewReader("id,name\n1,Ozzy\n3,Slash\n"), db.ImportOptions{
+		ConflictColumns: []string{"id"},
+	})
+	s.NoError(err)
+	s.Equal(db.ImportStats{Read: 2, Imported: 2}, stats)
+
+	names = nil
+	err = artist.Find().OrderBy("id").Pluck("name", &names)
+	s.NoError(err)
+	s.Equal([]string{"Ozzy", "Flea", "Slash"}, names)
+
+	// Conflicting rows are skipped without update columns.
+	_, err = sess.SQL().InsertInto("artist").
+		Values(map[string]interface{}{"id": 1, "name": "Ozzie"}).
+		OnConflictUpdate([]string{"id"}).
+		Exec()
+	s.NoError(err)
+
+	names = nil
+	err = artist.Find().OrderBy("id").Pluck("name", &names)
+	s.NoError(err)
+	s.Equal([]string{"Ozzy", "Flea", "Slash"}, names)
+
+	err = artist.Truncate()
+	s.NoError(err)
+}
+
+func (s *SQLTestSuite) TestEncryptedFields() {
+	keys := &reviewKeys{
+		current: "k1",
+		keys: map[string][]byte{
+			"k1": []byte("0123456789abcdef0123456789abcdef"),
+			"k2": []byte("fedcba9876543210fedcba9876543210"),
+		},
+	}
+	codecs := &db.Codecs{}
+	codecs.SetKeyProvider(keys)
+	codecs.EncryptColumnsDeterministically("review", "name")
+	codecs.EncryptColumns("review", "comments")
+
+	sess, err := db.WithCodecs(s.Session(), codecs)
+	s.NoError(err)
+
+	review := sess.Collection("review")
+
+	err = review.Truncate()
+	s.NoError(err)
+
+	type encryptedReview struct {
+		PublicationID int64  `db:"publication_id"`
+		Name          string `db:"name,encrypted=deterministic"`
+		Comments      string `db:"comments,encrypted"`
+	}
+
+	// Maps and conditions are encrypted before any record is used.
+	_, err = review.Insert(map[string]interface{}{"publication_id": 2, "name": "ann", "comments": "fine"})
+	s.NoError(err)
+
+	count, err := review.Find(db.Cond{"name": "ann"}).Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	err = review.Find(db.Cond{"name": "ann"}).Delete()
+	s.NoError(err)
+
+	_, err = review.Insert(encryptedReview{PublicationID: 1, Name: "joe", Comments: "great"})
+	s.NoError(err)
+
+	// Encrypted fields must be declared.
+	_, err = s.Session().Collection("review").Insert(encryptedReview{PublicationID: 2, Name: "ann"})
+	s.True(errors.Is(err, db.ErrUndeclaredEncryption))
+
+	var raw struct {
+		Name     string `db:"name"`
+		Comments string `db:"comments"`
+	}
+	err = review.Find().Select("name", "comments").One(&raw)
+	s.True(errors.Is(err, db.ErrUndeclaredEncryption))
+
+	err = sess.SQL().SelectFrom("review").Columns("name", "comments").One(&raw)
+	s.NoError(err)
+	s.True(strings.HasPrefix(raw.Name, "k1:"))
+	s.True(strings.HasPrefix(raw.Comments, "k1:"))
+	s.NotContains(raw.Comments, "great")
+
+	var rec encryptedReview
+	err = review.Find(db.Cond{"name": "joe"}).One(&rec)
+	s.NoError(err)
+	s.Equal(encryptedReview{PublicationID: 1, Name: "joe", Comments: "great"}, rec)
+
+	// So are the maps and conditions given to the SQL builder.
+	_, err = sess.SQL().InsertInto("review").
+		Values(map[string]interface{}{"publication_id": 2, "name": "sue", "comments": "fine"}).
+		Exec()
+	s.NoError(err)
+
+	_, err = sess.SQL().Update("review").
+		Set(map[string]interface{}{"comments": "good"}).
+		Where(db.Cond{"name": "sue"}).
+		Exec()
+	s.NoError(err)
+
+	err = sess.SQL().SelectFrom("review").Columns("name", "comments").Where(db.Cond{"name": "sue"}).One(&raw)
+	s.NoError(err)
+	s.True(strings.HasPrefix(raw.Name, "k1:"))
+	s.True(strings.HasPrefix(raw.Comments, "k1:"))
+	s.NotContains(raw.Comments, "good")
+
+	err = review.Find(db.Cond{"name": "sue"}).One(&rec)
+	s.NoError(err)
+	s.Equal(encryptedReview{PublicationID: 2, Name: "sue", Comments: "good"}, rec)
+
+	err = sess.SQL().Select("r.name").From("review AS r").Where(db.Cond{"r.comments": "good"}).One(&raw)
+	s.True(errors.Is(err, db.ErrEncryptedCondition))
+
+	_, err = sess.SQL().DeleteFrom("review").Where(db.Cond{"comments": "good"}).Exec()
+	s.True(errors.Is(err, db.ErrEncryptedCondition))
+
+	_, err = sess.SQL().DeleteFrom("review").Where(db.Cond{"name": "sue"}).Exec()
+	s.NoError(err)
+
+	err = review.Find(db.Cond{"comments": "great"}).One(&rec)
+	s.True(errors.Is(err, db.ErrEncryptedCondition))
+
+	err = review.Find(db.Cond{"name LIKE": "j%"}).One(&rec)
+	s.True(errors.Is(err, db.ErrEncryptedCondition))
+
+	count, err = review.Find(db.Cond{"comments": db.IsNull()}).Count()
+	s.NoError(err)
+	s.Equal(uint64(0), count)
+
+	err = review.Find(db.Cond{"name": db.Eq("joe")}).Update(map[string]interface{}{"comments": "awful"})
+	s.NoError(err)
+
+	keys.current = "k2"
+
+	rec = encryptedReview{}
+	err = review.Find().One(&rec)
+	s.NoError(err)
+	s.Equal("awful", rec.Comments)
+
+	// Values encrypted with rotated keys are still found.
+	count, err = review.Find(db.Cond{"name": "joe"}).Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	count, err = review.Find(db.Cond{"name =": db.Eq("joe")}).Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	err = review.Find().Update(rec)
+	s.NoError(err)
+
+	count, err = review.Find(db.Cond{"name": "joe"}).Count()
+	s.NoError(err)
+	s.Equal(uint64(1), count)
+
+	err = sess.SQL().SelectFrom("review").Columns("name", "comments").One(&raw)
+	s.NoError(err)
+	s.True(strings.HasPrefix(raw.Name, "k2:"))
+	s.True(strings.HasPrefix(raw.Comments, "k2:"))
+
+	// Imported rows are encrypted too.
+	stats, err := db.Import(review, strings.NewReader("publication_id,name,comments\n3,ann,meh\n"), db.ImportOptions{
+		Types: map[string]reflect.Type{"publication_id": reflect.TypeOf(int64(0))},
+	})
+	s.NoError(err)
+	s.Equal(db.ImportStats{Read: 1, Imported: 1}, stats)
+
+	err = sess.SQL().SelectFrom("review").Columns("name", "comments").Where("publication_id = ?", 3).One(&raw)
+	s.NoError(err)
+	s.True(strings.HasPrefix(raw.Name, "k2:"))
+	s.True(strings.HasPrefix(raw.Comments, "k2:"))
+	s.NotContains(raw.Comments, "meh")
+
+	err = review.Find(db.Cond{"name": "ann"}).One(&rec)
+	s.NoError(err)
+	s.Equal(encryptedReview{PublicationID: 3, Name: "ann", Comments: "meh"}, rec)
+
+	err = review.Truncate()
+	s.NoError(err)
+}
@@ -21,6 +21,10 @@
 
 package db
 
+import (
+	"context"
+)
+
 // Iterator provides methods for iterating over query results.
 type Iterator interface {
 	// ResultMapper provides methods to retrieve and map results.
@@ -39,6 +43,39 @@ type Iterator interface {
 	// a pointer to either a map or a struct.
 	Next(dest ...interface{}) bool
 
+	// Stream fetches the results in a goroutine and sends them through the
+	// returned channel, each one as a new pointer to a value of the type dst
+	// points to. The iterator is closed once all results were sent, on error or
+	// when ctx is done, then both channels are closed. The error channel
+	// receives at most one error.
+	//
+	// Example:
+	//
+	//   items, errs := iter.Stream(ctx, &User{})
+	//   for item := range items {
+	//     user := item.(*User)
+	//     ...
+	//   }
+	//   if err := <-errs; err != nil {
+	//     ...
+	//   }
+	Stream(ctx context.Context, dst interface{}) (<-chan interface{}, <-chan error)
+
+	// Columns returns the names and types of the columns of the results.
+	Columns() ([]ResultColumn, error)
+
+	// Values returns the values of the current result, in the same order as
+	// its columns. Values are normalized like the values of a
+	// map[string]interface{}, see ResultMapper.One.
+	//
+	// Example:
+	//
+	//   for iter.Next() {
+	//     values, err := iter.Values()
+	//     ...
+	//   }
+	Values() ([]interface{}, error)
+
 	// Err returns the last error produced by the cursor.
 	Err() error
 
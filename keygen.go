@@ -0,0 +1,242 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Names of the built-in key generators, see RegisterKeyGenerator.
+const (
+	KeyGeneratorUUIDv4    = "uuidv4"
+	KeyGeneratorUUIDv7    = "uuidv7"
+	KeyGeneratorULID      = "ulid"
+	KeyGeneratorSnowflake = "snowflake"
+)
+
+// KeyGenerator is an interface for data stores that generate the primary keys
+// of the records they create instead of having the database generate them.
+// GenerateKey is called for every primary key column whose field is empty
+// before the record is inserted.
+type KeyGenerator interface {
+	GenerateKey(column string) (interface{}, error)
+}
+
+// KeyGeneratorFunc is a function that returns a new key.
+type KeyGeneratorFunc func() (interface{}, error)
+
+var keyGeneratorRegistry = struct {
+	sync.RWMutex
+	generators map[string]KeyGeneratorFunc
+}{generators: map[string]KeyGeneratorFunc{
+	KeyGeneratorUUIDv4: func() (interface{}, error) {
+		return NewUUIDv4()
+	},
+	KeyGeneratorUUIDv7: func() (interface{}, error) {
+		return NewUUIDv7()
+	},
+	KeyGeneratorULID: func() (interface{}, error) {
+		return NewULID()
+	},
+	KeyGeneratorSnowflake: func() (interface{}, error) {
+		return defaultSnowflake.Next()
+	},
+}}
+
+// RegisterKeyGenerator registers a key generator with the given name, the
+// built-in generators can be replaced. A nil generator removes the generator
+// registered with that name.
+//
+// Fields tagged with the generate option are set with a key of the named
+// generator when the record is created and the field is empty:
+//
+//  type Event struct {
+//    ID   string `db:"id,generate=uuidv7"`
+//    Name string `db:"name"`
+//  }
+func RegisterKeyGenerator(name string, fn KeyGeneratorFunc) {
+	keyGeneratorRegistry.Lock()
+	defer keyGeneratorRegistry.Unlock()
+
+	if fn == nil {
+		delete(keyGeneratorRegistry.generators, name)
+		return
+	}
+	keyGeneratorRegistry.generators[name] = fn
+}
+
+// GenerateKey returns a new key of the key generator with the given name.
+func GenerateKey(name string) (interface{}, error) {
+	keyGeneratorRegistry.RLock()
+	fn, ok := keyGeneratorRegistry.generators[name]
+	keyGeneratorRegistry.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKeyGenerator
+	}
+	return fn()
+}
+
+// formatUUID returns the canonical text representation of a UUID.
+func formatUUID(u [16]byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// NewUUIDv4 returns a random UUID (version 4) in its canonical text form.
+func NewUUIDv4() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return formatUUID(u), nil
+}
+
+// NewUUIDv7 returns a time-ordered UUID (version 7) in its canonical text
+// form, UUIDs generated later sort after the ones generated earlier (with
+// millisecond precision).
+func NewUUIDv7() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		return "", err
+	}
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = (u[6] & 0x0f) | 0x70
+	u[8] = (u[8] & 0x3f) | 0x80
+	return formatUUID(u), nil
+}
+
+// crockfordAlphabet is the alphabet used to encode ULIDs.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a ULID, a 26 characters long time-ordered identifier made
+// of a millisecond timestamp and 80 random bits.
+func NewULID() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		return "", err
+	}
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	binary.BigEndian.PutUint16(u[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+
+	// 128 bits are encoded as 26 characters of 5 bits, the first character
+	// holds the 3 most significant bits.
+	hi := binary.BigEndian.Uint64(u[0:8])
+	lo := binary.BigEndian.Uint64(u[8:16])
+	buf := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1f]
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+	return string(buf), nil
+}
+
+// Snowflake bit layout: 41 bits for milliseconds since SnowflakeEpoch, 10 bits
+// for the node and 12 bits for a sequence number.
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+
+	snowflakeMaxNode     = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence = 1<<snowflakeSequenceBits - 1
+)
+
+// SnowflakeEpoch is the time snowflake IDs are counted from.
+var SnowflakeEpoch = time.Date(2010, time.November, 4, 1, 42, 54, 657000000, time.UTC)
+
+// Snowflake generates unique and time-ordered 64-bit integers, every process
+// generating snowflake IDs for the same table must use a different node.
+type Snowflake struct {
+	node int64
+
+	mu       sync.Mutex
+	last     int64
+	sequence int64
+}
+
+var defaultSnowflake = &Snowflake{}
+
+// NewSnowflake returns a snowflake ID generator for the given node, which
+// must be between 0 and 1023. The built-in snowflake key generator uses node
+// 0, use RegisterKeyGenerator to replace it.
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, ErrInvalidSnowflakeNode
+	}
+	return &Snowflake{node: node}, nil
+}
+
+// Next returns a new snowflake ID.
+func (s *Snowflake) Next() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Since(SnowflakeEpoch).Milliseconds()
+	if now < s.last {
+		// The clock moved backwards, keep counting from the last timestamp.
+		now = s.last
+	}
+	if now == s.last {
+		s.sequence = (s.sequence + 1) & snowflakeMaxSequence
+		if s.sequence == 0 {
+			// The sequence is exhausted, wait for the next millisecond.
+			for now <= s.last {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Since(SnowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.last = now
+
+	if now >= 1<<41 {
+		return 0, errors.New("upper: snowflake timestamp overflow")
+	}
+	return now<<(snowflakeNodeBits+snowflakeSequenceBits) |
+		s.node<<snowflakeSequenceBits |
+		s.sequence, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDs(t *testing.T) {
+	v4, err := NewUUIDv4()
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), v4)
+
+	v7, err := NewUUIDv7()
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), v7)
+
+	time.Sleep(2 * time.Millisecond)
+	later, err := NewUUIDv7()
+	assert.NoError(t, err)
+	assert.True(t, v7 < later)
+}
+
+func TestULID(t *testing.T) {
+	id, err := NewULID()
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), id)
+
+	time.Sleep(2 * time.Millisecond)
+	later, err := NewULID()
+	assert.NoError(t, err)
+	assert.True(t, id < later)
+}
+
+func TestSnowflake(t *testing.T) {
+	_, err := NewSnowflake(1024)
+	assert.True(t, errors.Is(err, ErrInvalidSnowflakeNode))
+
+	s, err := NewSnowflake(7)
+	assert.NoError(t, err)
+
+	prev := int64(0)
+	for i := 0; i < 10000; i++ {
+		id, err := s.Next()
+		assert.NoError(t, err)
+		if id <= prev {
+			t.Fatalf("expecting %d to be greater than %d", id, prev)
+		}
+		assert.Equal(t, int64(7), (id>>snowflakeSequenceBits)&snowflakeMaxNode)
+		prev = id
+	}
+}
+
+func TestKeyGeneratorRegistry(t *testing.T) {
+	_, err := GenerateKey("unknown")
+	assert.True(t, errors.Is(err, ErrUnknownKeyGenerator))
+
+	RegisterKeyGenerator("prefixed", func() (interface{}, error) {
+		id, err := NewULID()
+		return "key_" + id, err
+	})
+	defer RegisterKeyGenerator("prefixed", nil)
+
+	key, err := GenerateKey("prefixed")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key.(string), "key_"))
+
+	for _, name := range []string{KeyGeneratorUUIDv4, KeyGeneratorUUIDv7, KeyGeneratorULID, KeyGeneratorSnowflake} {
+		key, err := GenerateKey(name)
+		assert.NoError(t, err)
+		assert.NotZero(t, key)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FieldMapper defines how the fields of structs are mapped to columns, see
+// WithFieldMapper. The zero value maps fields the default way: by their "db"
+// tag, fields without tag are not mapped.
+type FieldMapper struct {
+	// TagName is the name of the struct tag that holds the column name and the
+	// options of a field, like "json". Defaults to "db", fields without that
+	// tag are mapped by their "db" tag if they have one.
+	TagName string
+
+	// Naming returns the column name of a field that has no tag given its Go
+	// name, like SnakeCase or CamelCase. Fields without tag are not mapped if
+	// Naming is nil.
+	Naming func(field string) string
+}
+
+// WithFieldMapper returns a copy of sess that maps the fields of structs to
+// columns as defined by mapper, when inserting and updating values, loading
+// results and saving records.
+//
+// Example:
+//
+//  snakeSess, err := db.WithFieldMapper(sess, db.FieldMapper{
+//    Naming: db.SnakeCase,
+//  })
+//  ...
+//  // Name is mapped to "name" and CreatedAt to "created_at".
+//  err = snakeSess.Collection("accounts").Find().All(&accounts)
+func WithFieldMapper(sess Session, mapper FieldMapper) (Session, error) {
+	if s, ok := sess.(interface {
+		WithFieldMapper(*FieldMapper) Session
+	}); ok {
+		return s.WithFieldMapper(&mapper), nil
+	}
+	return nil, ErrNotSupportedByAdapter
+}
+
+// SnakeCase converts a Go name to snake case, like "created_at" for
+// "CreatedAt" or "user_id" for "UserID".
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// CamelCase converts a Go name to lower camel case, like "createdAt" for
+// "CreatedAt" or "userID" for "UserID".
+func CamelCase(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		// The last upper case letter of a leading acronym starts the next word,
+		// like "Url" in "HTTPUrl".
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	names := map[string][2]string{
+		"Name":      {"name", "name"},
+		"CreatedAt": {"created_at", "createdAt"},
+		"UserID":    {"user_id", "userID"},
+		"HTTPUrl":   {"http_url", "httpUrl"},
+		"Address2":  {"address2", "address2"},
+		"ID":        {"id", "id"},
+	}
+	for name, expected := range names {
+		assert.Equal(t, expected[0], SnakeCase(name), name)
+		assert.Equal(t, expected[1], CamelCase(name), name)
+	}
+}
+
+type mappedAccount struct {
+	Tracking
+
+	ID   uint64 `json:"id"`
+	Name string
+}
+
+func (*mappedAccount) Store(sess Session) Store {
+	return nil
+}
+
+type mappedSession struct {
+	Session
+}
+
+func (mappedSession) Mapper() *reflectx.Mapper {
+	return reflectx.NewMapperTagsFunc([]string{"json", "db"}, SnakeCase)
+}
+
+func TestSnapshotWithFieldMapper(t *testing.T) {
+	record := &mappedAccount{ID: 1, Name: "Joe"}
+	SnapshotWith(record, mappedSession{})
+	assert.Equal(t, map[string]Change{}, Changed(record))
+
+	record.Name = "Joan"
+	assert.Equal(t, map[string]Change{"name": {From: "Joe", To: "Joan"}}, Changed(record))
+}
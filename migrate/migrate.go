@@ -0,0 +1,305 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package migrate provides tools for applying and reverting versioned SQL
+// migrations on upper/db sessions.
+//
+// Migrations are plain SQL files stored in a directory, each version has an
+// "up" file and an optional "down" file:
+//
+//  20220301120000_create_accounts.up.sql
+//  20220301120000_create_accounts.down.sql
+//
+// Applied versions are recorded in a tracking table (schema_migrations by
+// default) so that every migration runs exactly once.
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/upper/db/v4"
+)
+
+// DefaultTable is the name of the table used to keep track of applied
+// migrations.
+const DefaultTable = `schema_migrations`
+
+// Error messages
+var (
+	ErrMissingDownMigration = errors.New(`upper: migration can't be reverted: missing down migration`)
+	ErrDuplicatedVersion    = errors.New(`upper: duplicated migration version`)
+	ErrUnknownVersion       = errors.New(`upper: applied versions are not among the known migrations`)
+)
+
+// Migration represents a single versioned change to the database schema.
+type Migration struct {
+	Version uint64
+	Name    string
+
+	// Up and Down hold the SQL statements that apply and revert the
+	// migration.
+	Up   string
+	Down string
+}
+
+// String returns a human readable name for the migration.
+func (m *Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Status describes whether a migration was applied or not.
+type Status struct {
+	*Migration
+
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// appliedMigration represents a row of the tracking table.
+type appliedMigration struct {
+	Version   uint64    `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// Migrator applies and reverts a set of migrations on a database session.
+type Migrator struct {
+	adapter    string
+	sess       db.Session
+	table      string
+	migrations []*Migration
+}
+
+// New creates a Migrator for the given migrations. The adapter name is used to
+// create the tracking table with types the database understands.
+func New(adapter string, sess db.Session, migrations []*Migration) (*Migrator, error) {
+	if adapter == "mongo" {
+		return nil, db.ErrNotSupportedByAdapter
+	}
+
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicatedVersion, sorted[i].Version)
+		}
+	}
+
+	return &Migrator{
+		adapter:    adapter,
+		sess:       sess,
+		table:      DefaultTable,
+		migrations: sorted,
+	}, nil
+}
+
+// SetTable changes the name of the tracking table.
+func (m *Migrator) SetTable(name string) {
+	m.table = name
+}
+
+// Migrations returns all known migrations sorted by version.
+func (m *Migrator) Migrations() []*Migration {
+	return m.migrations
+}
+
+// Status returns the status of every known migration, sorted by version. If
+// versions that are not among the known migrations were applied the status
+// is returned along with an ErrUnknownVersion error that lists them.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		st := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		status = append(status, st)
+	}
+
+	if len(applied) > 0 {
+		unknown := make([]uint64, 0, len(applied))
+		for version := range applied {
+			unknown = append(unknown, version)
+		}
+		sort.Slice(unknown, func(i, j int) bool {
+			return unknown[i] < unknown[j]
+		})
+		versions := make([]string, len(unknown))
+		for i := range unknown {
+			versions[i] = strconv.FormatUint(unknown[i], 10)
+		}
+		return status, fmt.Errorf("%w: %s", ErrUnknownVersion, strings.Join(versions, ", "))
+	}
+
+	return status, nil
+}
+
+// Up applies at most n pending migrations in ascending order, a non-positive
+// n applies all of them. Each migration runs within its own transaction. Up
+// returns the migrations that were applied.
+func (m *Migrator) Up(n int) ([]*Migration, error) {
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	done := []*Migration{}
+	for _, st := range status {
+		if st.Applied {
+			continue
+		}
+		if n > 0 && len(done) >= n {
+			break
+		}
+		if err := m.apply(st.Migration); err != nil {
+			return done, fmt.Errorf("migration %s: %w", st.Migration, err)
+		}
+		done = append(done, st.Migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts at most n applied migrations in descending order, a
+// non-positive n reverts only the latest one. Down returns the migrations
+// that were reverted.
+func (m *Migrator) Down(n int) ([]*Migration, error) {
+	if n < 1 {
+		n = 1
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	done := []*Migration{}
+	for i := len(status) - 1; i >= 0 && len(done) < n; i-- {
+		if !status[i].Applied {
+			continue
+		}
+		if err := m.revert(status[i].Migration); err != nil {
+			return done, fmt.Errorf("migration %s: %w", status[i].Migration, err)
+		}
+		done = append(done, status[i].Migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) apply(migration *Migration) error {
+	return m.sess.Tx(func(tx db.Session) error {
+		if err := execStatements(tx, migration.Up); err != nil {
+			return err
+		}
+		_, err := tx.SQL().
+			InsertInto(m.table).
+			Values(appliedMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			}).
+			Exec()
+		return err
+	})
+}
+
+func (m *Migrator) revert(migration *Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %s", ErrMissingDownMigration, migration)
+	}
+	return m.sess.Tx(func(tx db.Session) error {
+		if err := execStatements(tx, migration.Down); err != nil {
+			return err
+		}
+		_, err := tx.SQL().
+			DeleteFrom(m.table).
+			Where(db.Cond{"version": migration.Version}).
+			Exec()
+		return err
+	})
+}
+
+// applied returns all rows from the tracking table, creating the table if it
+// does not exist.
+func (m *Migrator) applied() (map[uint64]appliedMigration, error) {
+	if err := m.createTable(); err != nil {
+		return nil, err
+	}
+
+	var rows []appliedMigration
+	if err := m.sess.SQL().SelectFrom(m.table).All(&rows); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint64]appliedMigration, len(rows))
+	for i := range rows {
+		applied[rows[i].Version] = rows[i]
+	}
+	return applied, nil
+}
+
+func (m *Migrator) createTable() error {
+	if ok, _ := m.sess.Collection(m.table).Exists(); ok {
+		return nil
+	}
+	_, err := m.sess.SQL().Exec(createTableStatement(m.adapter, m.table))
+	return err
+}
+
+func createTableStatement(adapter string, table string) string {
+	switch adapter {
+	case "ql":
+		return fmt.Sprintf(`CREATE TABLE %s (version int64, name string, applied_at time)`, table)
+	case "mysql":
+		return fmt.Sprintf("CREATE TABLE `%s` (`version` BIGINT UNSIGNED NOT NULL PRIMARY KEY, `name` VARCHAR(255) NOT NULL, `applied_at` DATETIME NOT NULL)", table)
+	case "mssql":
+		return fmt.Sprintf(`CREATE TABLE [%s] ([version] BIGINT NOT NULL PRIMARY KEY, [name] NVARCHAR(255) NOT NULL, [applied_at] DATETIME2 NOT NULL)`, table)
+	case "sqlite":
+		return fmt.Sprintf(`CREATE TABLE "%s" ("version" INTEGER NOT NULL PRIMARY KEY, "name" VARCHAR(255) NOT NULL, "applied_at" DATETIME NOT NULL)`, table)
+	}
+	return fmt.Sprintf(`CREATE TABLE "%s" ("version" BIGINT NOT NULL PRIMARY KEY, "name" VARCHAR(255) NOT NULL, "applied_at" TIMESTAMP NOT NULL)`, table)
+}
+
+func execStatements(sess db.Session, source string) error {
+	for _, stmt := range SplitStatements(source) {
+		if _, err := sess.SQL().Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
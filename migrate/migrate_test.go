@@ -0,0 +1,55 @@
+package migrate
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4/adapter/sqlite"
+)
+
+func TestStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upper-migrate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sess, err := sqlite.Open(sqlite.ConnectionURL{Database: filepath.Join(dir, "migrate.db")})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer sess.Close()
+
+	migrations := []*Migration{
+		{Version: 1, Name: "create_a", Up: `CREATE TABLE a (id int)`, Down: `DROP TABLE a`},
+		{Version: 2, Name: "create_b", Up: `CREATE TABLE b (id int)`},
+		{Version: 3, Name: "create_c", Up: `CREATE TABLE c (id int)`, Down: `DROP TABLE c`},
+	}
+
+	m, err := New("sqlite", sess, migrations)
+	assert.NoError(t, err)
+
+	done, err := m.Up(0)
+	assert.NoError(t, err)
+	assert.Len(t, done, 3)
+
+	// Versions the migrator doesn't know about are all reported.
+	m, err = New("sqlite", sess, migrations[:1])
+	assert.NoError(t, err)
+
+	status, err := m.Status()
+	assert.True(t, errors.Is(err, ErrUnknownVersion))
+	assert.EqualError(t, err, ErrUnknownVersion.Error()+": 2, 3")
+	if assert.Len(t, status, 1) {
+		assert.True(t, status[0].Applied)
+	}
+
+	m, err = New("sqlite", sess, migrations)
+	assert.NoError(t, err)
+
+	done, err = m.Down(2)
+	assert.True(t, errors.Is(err, ErrMissingDownMigration))
+	assert.Len(t, done, 1)
+}
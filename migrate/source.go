@@ -0,0 +1,229 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionLayout is the time layout used by Create to generate versions.
+const VersionLayout = `20060102150405`
+
+var (
+	migrationFilePattern = regexp.MustCompile(`^(\d+)_([^.]+)\.(up|down)\.sql$`)
+	migrationNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ErrInvalidMigrationName is returned when a migration name has no usable
+// characters.
+var ErrInvalidMigrationName = errors.New(`upper: invalid migration name %q`)
+
+// Load reads all migration files from the given directory. Files that don't
+// follow the "<version>_<name>.(up|down).sql" convention are ignored.
+func Load(dir string) ([]*Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		matches := migrationFilePattern.FindStringSubmatch(file.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicatedVersion, version)
+		}
+
+		buf, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(buf)
+		} else {
+			migration.Down = string(buf)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Create writes a new pair of empty up and down migration files into dir,
+// using the given time as version. It returns the paths of the new files.
+func Create(dir string, name string, t time.Time) ([]string, error) {
+	slug := strings.Trim(migrationNamePattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return nil, fmt.Errorf(ErrInvalidMigrationName.Error(), name)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	version := t.UTC().Format(VersionLayout)
+
+	paths := []string{}
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%s_%s.%s.sql", version, slug, direction))
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("file %q already exists", path)
+		}
+		content := fmt.Sprintf("-- %s migration for %s_%s\n", direction, version, slug)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// SplitStatements splits a SQL script into individual statements separated by
+// semicolons. Semicolons within quotes, dollar-quoted blocks and comments are
+// not considered separators. Empty statements and comment-only statements are
+// discarded.
+func SplitStatements(source string) []string {
+	statements := []string{}
+
+	var buf strings.Builder
+	hasCode := false
+
+	flush := func() {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(buf.String()))
+		}
+		buf.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+
+		switch {
+		case c == '-' && i+1 < len(source) && source[i+1] == '-':
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				end = len(source) - i
+			}
+			buf.WriteString(source[i : i+end])
+			i += end - 1
+			continue
+		case c == '/' && i+1 < len(source) && source[i+1] == '*':
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				end = len(source) - i - 2
+			} else {
+				end += 2
+			}
+			buf.WriteString(source[i : i+2+end])
+			i += 1 + end
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(source[i+1:], c)
+			if end < 0 {
+				end = len(source) - i - 1
+			} else {
+				end++
+			}
+			buf.WriteString(source[i : i+1+end])
+			i += end
+			hasCode = true
+			continue
+		case c == '$':
+			tag := dollarQuoteTag(source[i:])
+			if tag == "" {
+				break
+			}
+			end := strings.Index(source[i+len(tag):], tag)
+			if end < 0 {
+				end = len(source) - i - len(tag)
+			} else {
+				end += len(tag)
+			}
+			buf.WriteString(source[i : i+len(tag)+end])
+			i += len(tag) + end - 1
+			hasCode = true
+			continue
+		case c == ';':
+			flush()
+			continue
+		}
+
+		buf.WriteByte(c)
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			hasCode = true
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// dollarQuoteTag returns the PostgreSQL dollar-quote tag (like $$ or $body$)
+// at the beginning of s, if any.
+func dollarQuoteTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 1 && c >= '0' && c <= '9')) {
+			return ""
+		}
+	}
+	return ""
+}
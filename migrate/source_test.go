@@ -0,0 +1,102 @@
+package migrate
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out []string
+	}{
+		{
+			in:  ``,
+			out: []string{},
+		},
+		{
+			in:  "-- nothing to see here;\n",
+			out: []string{},
+		},
+		{
+			in:  `CREATE TABLE a (id int); CREATE TABLE b (id int);`,
+			out: []string{`CREATE TABLE a (id int)`, `CREATE TABLE b (id int)`},
+		},
+		{
+			in:  "INSERT INTO a (name) VALUES ('x;y');\n-- comment; with semicolon\nDELETE FROM a",
+			out: []string{`INSERT INTO a (name) VALUES ('x;y')`, "-- comment; with semicolon\nDELETE FROM a"},
+		},
+		{
+			in:  `SELECT "a;b", /* ; */ 1;`,
+			out: []string{`SELECT "a;b", /* ; */ 1`},
+		},
+		{
+			in: `CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.x = 1; RETURN NEW; END; $body$ LANGUAGE plpgsql;
+			SELECT $1`,
+			out: []string{
+				`CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.x = 1; RETURN NEW; END; $body$ LANGUAGE plpgsql`,
+				`SELECT $1`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.out, SplitStatements(tc.in), tc.in)
+	}
+}
+
+func TestCreateAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upper-migrate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	paths, err := Create(dir, "Create Accounts!", time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "20220301120000_create_accounts.up.sql"),
+		filepath.Join(dir, "20220301120000_create_accounts.down.sql"),
+	}, paths)
+
+	_, err = Create(dir, "create accounts", time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+
+	_, err = Create(dir, "!!", time.Now())
+	assert.Error(t, err)
+
+	_, err = Create(dir, "add users", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0644)
+	assert.NoError(t, err)
+
+	migrations, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+
+	assert.Equal(t, uint64(20210101000000), migrations[0].Version)
+	assert.Equal(t, "add_users", migrations[0].Name)
+	assert.Equal(t, uint64(20220301120000), migrations[1].Version)
+	assert.Equal(t, "create_accounts", migrations[1].Name)
+	assert.Equal(t, "20220301120000_create_accounts", migrations[1].String())
+	assert.Contains(t, migrations[1].Up, "up migration")
+	assert.Contains(t, migrations[1].Down, "down migration")
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("mongo", nil, nil)
+	assert.Error(t, err)
+
+	_, err = New("sqlite", nil, []*Migration{{Version: 1}, {Version: 1}})
+	assert.True(t, errors.Is(err, ErrDuplicatedVersion))
+
+	m, err := New("sqlite", nil, []*Migration{{Version: 2}, {Version: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), m.Migrations()[0].Version)
+	assert.Equal(t, uint64(2), m.Migrations()[1].Version)
+}
@@ -21,6 +21,29 @@
 package db
 
 // Record is the equivalence between concrete database schemas and Go values.
+//
+// Fields tagged with the autocreatetime option are set to the current time
+// when the record is created (unless they're already set), and fields tagged
+// with the autoupdatetime option are set to the current time whenever the
+// record is saved, see Settings.SetClock.
+//
+//  type Post struct {
+//    ID        uint64    `db:"id,omitempty"`
+//    CreatedAt time.Time `db:"created_at,autocreatetime"`
+//    UpdatedAt time.Time `db:"updated_at,autoupdatetime"`
+//  }
+//
+// An integer field tagged with the optlock option, like
+// `db:"version,optlock"`, enables optimistic locking: saving an existing
+// record only updates the row if its version still matches the version of
+// the record, and increments it. ErrStaleRecord is returned otherwise.
+//
+// Fields tagged with the generate option, like `db:"id,generate=uuidv7"`, are
+// set with a key of the named generator when the record is created and the
+// field is empty, see RegisterKeyGenerator and KeyGenerator.
+//
+// Fields tagged with the codec option, like `db:"payload,codec=json"`, are
+// encoded and decoded with the named codec, see RegisterCodec and Codecs.
 type Record interface {
 	Store(sess Session) Store
 }
@@ -80,3 +103,38 @@ type BeforeDeleteHook interface {
 type AfterDeleteHook interface {
 	AfterDelete(Session) error
 }
+
+// AfterFindHook is an interface for records that defines an AfterFind method
+// that is called after a record is loaded with Session.Get, Result.One,
+// Result.All, Result.Next, Iterator.One, Iterator.All or Iterator.Next. If
+// AfterFind returns an error the loading operation fails with that error.
+type AfterFindHook interface {
+	AfterFind(Session) error
+}
+
+// SoftDeletable is an interface for records that are marked as deleted instead
+// of being removed from the database. SoftDeleteColumn returns the name of the
+// nullable timestamp column that holds the time the record was deleted, like
+// "deleted_at".
+//
+// Deleting a SoftDeletable record with Session.Delete sets that column (and
+// the matching field of the record, if any) to the current time. Results
+// fetched into SoftDeletable records exclude deleted rows, see
+// Result.WithDeleted and Result.OnlyDeleted. Other result sets of the
+// collection only do so if its store is a SoftDeletableStore given to
+// WithStores.
+type SoftDeletable interface {
+	Record
+	SoftDeleteColumn() string
+}
+
+// SoftDeletableStore is an interface for data stores of soft-deletable
+// collections. SoftDeleteColumn returns the name of the column that holds the
+// time an item was deleted, like SoftDeletable does for records.
+//
+// Result sets of the collection exclude deleted items, and Result.Delete marks
+// them as deleted, once the store is given to WithStores.
+type SoftDeletableStore interface {
+	Store
+	SoftDeleteColumn() string
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+// RelationKind identifies the kind of association between two records.
+type RelationKind uint8
+
+// Kinds of relations.
+const (
+	// RelationBelongsTo means the record holds a foreign key that points to
+	// the related record.
+	RelationBelongsTo RelationKind = iota + 1
+
+	// RelationHasOne means the related record holds a foreign key that points
+	// to the record and there's at most one of them.
+	RelationHasOne
+
+	// RelationHasMany means the related records hold a foreign key that points
+	// to the record.
+	RelationHasMany
+
+	// RelationManyToMany means records are associated through a join table
+	// that holds foreign keys to both sides.
+	RelationManyToMany
+)
+
+// Relation describes an association between a record and other records.
+//
+// Relations are declared either with a Relations method or with a "rel"
+// struct tag on the field that holds the related records. Relation fields are
+// not columns, so they must be tagged with `db:"-"`:
+//
+//  type Post struct {
+//    ID       uint64     `db:"id,omitempty"`
+//    AuthorID uint64     `db:"author_id"`
+//    Author   *User      `db:"-" rel:"belongs_to,fk=author_id"`
+//    Comments []*Comment `db:"-" rel:"has_many,fk=post_id"`
+//    Tags     []Tag      `db:"-" rel:"many_to_many,through=post_tags,fk=post_id,references=tag_id"`
+//  }
+//
+// The related type is expected to be a Record, unless Table is set.
+type Relation struct {
+	Kind RelationKind
+
+	// ForeignKey is the column that holds the reference: a column of the
+	// record for RelationBelongsTo, a column of the related table for
+	// RelationHasOne and RelationHasMany, and a column of the join table that
+	// points to the record for RelationManyToMany.
+	ForeignKey string
+
+	// Key is the column ForeignKey points to, "id" by default. For
+	// RelationManyToMany it's the key of both the record and the related
+	// table.
+	Key string
+
+	// Through is the join table of a RelationManyToMany.
+	Through string
+
+	// References is the column of the join table that points to the related
+	// table, only used by RelationManyToMany.
+	References string
+
+	// Table is the name of the related table, it's only required when the
+	// related type does not implement Record.
+	Table string
+}
+
+// Relations maps the names of struct fields to the relations they hold.
+type Relations map[string]Relation
+
+// HasRelations is an interface for records that describe their associations
+// with a Relations method instead of "rel" struct tags.
+//
+// Example:
+//
+//  func (*Post) Relations() db.Relations {
+//    return db.Relations{
+//      "Author":   db.BelongsTo("author_id"),
+//      "Comments": db.HasMany("post_id"),
+//      "Tags":     db.ManyToMany("post_tags", "post_id", "tag_id"),
+//    }
+//  }
+type HasRelations interface {
+	Relations() Relations
+}
+
+// BelongsTo creates a relation with the record foreignKey points to.
+func BelongsTo(foreignKey string) Relation {
+	return Relation{Kind: RelationBelongsTo, ForeignKey: foreignKey}
+}
+
+// HasOne creates a relation with the record whose foreignKey points back to
+// this record.
+func HasOne(foreignKey string) Relation {
+	return Relation{Kind: RelationHasOne, ForeignKey: foreignKey}
+}
+
+// HasMany creates a relation with the records whose foreignKey points back to
+// this record.
+func HasMany(foreignKey string) Relation {
+	return Relation{Kind: RelationHasMany, ForeignKey: foreignKey}
+}
+
+// ManyToMany creates a relation through a join table, foreignKey is the
+// column of the join table that points to this record and references the one
+// that points to the related record.
+func ManyToMany(through string, foreignKey string, references string) Relation {
+	return Relation{
+		Kind:       RelationManyToMany,
+		Through:    through,
+		ForeignKey: foreignKey,
+		References: references,
+	}
+}
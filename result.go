@@ -60,9 +60,29 @@ type Result interface {
 	GroupBy(...interface{}) Result
 
 	// Delete deletes all items within the result set. `Offset()` and `Limit()`
-	// are not honoured by `Delete()`.
+	// are not honoured by `Delete()`. Items of a soft-deletable collection are
+	// marked as deleted instead of being removed, see `HardDelete()`.
 	Delete() error
 
+	// HardDelete removes all items within the result set from the collection,
+	// including items that were soft deleted.
+	HardDelete() error
+
+	// WithDeleted includes soft deleted items in the result set. See
+	// `SoftDeletable`.
+	WithDeleted() Result
+
+	// OnlyDeleted restricts the result set to soft deleted items.
+	OnlyDeleted() Result
+
+	// Restore clears the deletion mark of all soft deleted items within the
+	// result set.
+	//
+	// Example:
+	//
+	//   err = books.Find(id).Restore()
+	Restore() error
+
 	// Update modifies all items within the result set. `Offset()` and `Limit()`
 	// are not honoured by `Update()`.
 	Update(interface{}) error
@@ -88,14 +108,85 @@ type Result interface {
 	// given pointer to struct or pointer to map. The result set is automatically
 	// closed after picking the element, so there is no need to call Close()
 	// after using One().
+	//
+	// Values dumped into a map[string]interface{} are normalized as described
+	// in ResultMapper.One.
+	//
+	// Results with a single column can also be dumped into a pointer to a
+	// single value, like a *time.Time or a pointer to a sql.Scanner.
 	One(ptrToStruct interface{}) error
 
 	// All fetches all results within the result set and dumps them into the
 	// given pointer to slice of maps or structs.  The result set is
 	// automatically closed, so there is no need to call Close() after
 	// using All().
+	//
+	// Results with a single column can also be dumped into a pointer to slice
+	// of single values, like a *[]int64, see `Pluck()`.
 	All(sliceOfStructs interface{}) error
 
+	// Pluck fetches the values of the given column of all the results within
+	// the result set into the given pointer to slice.
+	//
+	// Example:
+	//
+	//   var ids []int64
+	//   err = users.Find(db.Cond{"active": true}).Pluck("id", &ids)
+	Pluck(column string, sliceOfValues interface{}) error
+
+	// Each fetches the results within the result set one at a time and calls
+	// fn with each one of them. fn must be a function like func(*T) error or
+	// func(T) error, where T is anything One() accepts a pointer to. Each stops
+	// and returns the error of fn if it fails, or the error of the context of
+	// the session if it's cancelled. The result set is closed before Each
+	// returns.
+	//
+	// Example:
+	//
+	//   err = users.Find().Each(func(user *User) error {
+	//     return enc.Encode(user)
+	//   })
+	Each(fn interface{}) error
+
+	// Chunk fetches the results within the result set in batches of up to size
+	// items and calls fn with each batch. fn must be a function like
+	// func([]T) error, where T is anything One() accepts a pointer to. Each
+	// batch is a new slice. Chunk stops like `Each()` does.
+	//
+	// Example:
+	//
+	//   err = users.Find().Chunk(500, func(batch []*User) error {
+	//     return index(batch)
+	//   })
+	Chunk(size int, fn interface{}) error
+
+	// Scope applies the named scope that the store of the collection declares,
+	// see `HasScopes` and `WithStores()`, args are passed to the scope.
+	//
+	// Example:
+	//
+	//   err = users.Find().Scope("tenant", tenantID).All(&items)
+	Scope(name string, args ...interface{}) Result
+
+	// Strict makes One, All and Next fail with a *ScanError when the columns
+	// of the result don't match the fields of the destination struct, see
+	// `WithStrictScan()`.
+	Strict() Result
+
+	// Unscoped prevents the default scope of the collection from being
+	// applied to the result set.
+	Unscoped() Result
+
+	// Preload loads the given relations of the records fetched by `One()` and
+	// `All()`. Nested relations are separated by dots. Related records are
+	// fetched with one query per relation, using IN (...) conditions on the
+	// keys of all the fetched records.
+	//
+	// Example:
+	//
+	//   err = posts.Find().Preload("Author", "Comments.User").All(&items)
+	Preload(relations ...string) Result
+
 	// Paginate splits the results of the query into pages containing pageSize
 	// items. When using pagination previous settings for `Limit()` and
 	// `Offset()` are ignored. Page numbering starts at 1.
@@ -0,0 +1,89 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScanError is the error returned when the columns of a result don't match
+// the fields of the struct they're scanned into and strict scanning is
+// enabled, see WithStrictScan and Result.Strict. ScanError wraps
+// ErrStrictScan.
+type ScanError struct {
+	// Type is the name of the struct type the result was scanned into.
+	Type string
+
+	// UnmappedColumns are the result columns that no field is mapped to.
+	UnmappedColumns []string
+
+	// MissingFields are the columns of the struct fields that no result
+	// column populated.
+	MissingFields []string
+}
+
+// Error returns a description of the mismatch.
+func (e *ScanError) Error() string {
+	problems := make([]string, 0, 2)
+	if len(e.UnmappedColumns) > 0 {
+		problems = append(problems, fmt.Sprintf("unmapped columns %s", quoteNames(e.UnmappedColumns)))
+	}
+	if len(e.MissingFields) > 0 {
+		problems = append(problems, fmt.Sprintf("no column for fields %s", quoteNames(e.MissingFields)))
+	}
+	return fmt.Sprintf("upper: can't scan into %s: %s", e.Type, strings.Join(problems, "; "))
+}
+
+// Unwrap returns ErrStrictScan.
+func (e *ScanError) Unwrap() error {
+	return ErrStrictScan
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i := range names {
+		quoted[i] = fmt.Sprintf("%q", names[i])
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// WithStrictScan returns a copy of sess that fails with a *ScanError instead
+// of ignoring result columns that are not mapped to any field of the
+// destination struct, or leaving fields that no column populated at their zero
+// value. Results are checked when their first row is scanned, scanning into
+// maps is not affected.
+//
+// Example:
+//
+//  strictSess, err := db.WithStrictScan(sess)
+//  ...
+//  // Fails if the accounts table has columns Account doesn't map or Account
+//  // has fields the accounts table lacks.
+//  err = strictSess.Collection("accounts").Find().All(&accounts)
+func WithStrictScan(sess Session) (Session, error) {
+	if s, ok := sess.(interface {
+		WithStrictScan() Session
+	}); ok {
+		return s.WithStrictScan(), nil
+	}
+	return nil, ErrNotSupportedByAdapter
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+// Schema provides methods to inspect the structure of a database, like the
+// columns, indexes and constraints of its tables.
+type Schema interface {
+	// Tables returns the definitions of all non-system tables on the
+	// database.
+	Tables() ([]*Table, error)
+
+	// Table returns the definition of the given table. If the table does not
+	// exist ErrCollectionDoesNotExist is returned.
+	Table(name string) (*Table, error)
+
+	// Views returns the definitions of all views on the database.
+	Views() ([]*View, error)
+}
+
+// Table describes a database table or collection.
+type Table struct {
+	Name string
+
+	Columns     []*Column
+	PrimaryKey  []string
+	Indexes     []*Index
+	Uniques     []*UniqueConstraint
+	ForeignKeys []*ForeignKey
+}
+
+// Column returns the column with the given name, or nil if the table has no
+// such column.
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// Column describes a table column.
+type Column struct {
+	Name string
+
+	// Type is the column type as reported by the database, like
+	// "character varying(60)" or "int(10) unsigned".
+	Type string
+
+	Nullable bool
+
+	// Default is the default value expression of the column, or nil if the
+	// column has no default value.
+	Default *string
+
+	// AutoIncrement is true for columns that get their values from a
+	// sequence, identity or auto increment mechanism.
+	AutoIncrement bool
+}
+
+// Index describes a table index.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// UniqueConstraint describes a UNIQUE constraint on a table.
+type UniqueConstraint struct {
+	Name    string
+	Columns []string
+}
+
+// ForeignKey describes a foreign key constraint on a table.
+type ForeignKey struct {
+	Name string
+
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+
+	// OnUpdate and OnDelete hold the referential actions, like "CASCADE" or
+	// "NO ACTION".
+	OnUpdate string
+	OnDelete string
+}
+
+// View describes a database view.
+type View struct {
+	Name       string
+	Definition string
+	Columns    []*Column
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+// DefaultScope is the name of the scope that is applied to every result set
+// of a collection, unless Result.Unscoped is called. Records are looked up by
+// key without scopes by the methods of Session, like Get, Save and Reload.
+const DefaultScope = "default"
+
+// Scope is a function that narrows a result set, like a set of conditions
+// that is used often. args are the arguments given to Result.Scope.
+type Scope func(res Result, args ...interface{}) Result
+
+// Scopes maps scope names to scopes.
+type Scopes map[string]Scope
+
+// HasScopes is an interface for data stores that defines a Scopes method that
+// returns the named scopes of the store, see WithStores. The scope named
+// DefaultScope is applied to all the result sets of the collection of the
+// store.
+//
+// Example:
+//
+//  func (*UsersStore) Scopes() db.Scopes {
+//    return db.Scopes{
+//      db.DefaultScope: func(res db.Result, args ...interface{}) db.Result {
+//        return res.And(db.Cond{"active": true})
+//      },
+//      "tenant": func(res db.Result, args ...interface{}) db.Result {
+//        return res.And(db.Cond{"tenant_id": args[0]})
+//      },
+//    }
+//  }
+//
+//  sess, err = db.WithStores(sess, Users(sess))
+//  ...
+//  err = sess.Collection("users").Find().Scope("tenant", tenantID).All(&items)
+type HasScopes interface {
+	Scopes() Scopes
+}
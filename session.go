@@ -45,6 +45,10 @@ type Session interface {
 	// database.
 	Collections() ([]Collection, error)
 
+	// Schema returns an interface to inspect the tables, columns, indexes and
+	// constraints of the database.
+	Schema() Schema
+
 	// Save creates or updates a record.
 	Save(record Record) error
 
@@ -54,6 +58,24 @@ type Session interface {
 	// Delete deletes a record.
 	Delete(record Record) error
 
+	// Reload replaces the values of a record with the ones of its row, which
+	// is looked up by primary key.
+	Reload(record Record) error
+
+	// Exists returns true if the row of a record exists.
+	Exists(record Record) (bool, error)
+
+	// SaveAll creates or updates the given records within a single
+	// transaction. Hooks run for every record, while the rows of new records
+	// are inserted in batches (records without a primary key only on
+	// databases that support RETURNING) and the rows of existing records are
+	// updated in batches, unless they use optimistic locking.
+	SaveAll(records ...Record) error
+
+	// DeleteAll deletes the given records within a single transaction. Hooks
+	// run for every record, while the rows are deleted in batches.
+	DeleteAll(records ...Record) error
+
 	// Reset resets all the caching mechanisms the adapter is using.
 	Reset()
 
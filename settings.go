@@ -76,6 +76,16 @@ type Settings interface {
 	// MaxTransactionRetries returns the maximum number of times a
 	// transaction can be retried.
 	MaxTransactionRetries() int
+
+	// SetClock sets the function used to get the current time when setting
+	// automatic timestamps and marking records as deleted. A nil function
+	// restores the default, time.Now. Each SQL session and its transactions
+	// have their own clock, the clock of DefaultSettings is the one of the
+	// sessions that don't set theirs.
+	SetClock(func() time.Time)
+
+	// Now returns the current time according to the clock set with SetClock.
+	Now() time.Time
 }
 
 type settings struct {
@@ -89,6 +99,8 @@ type settings struct {
 	maxIdleConns    int
 
 	maxTransactionRetries int
+
+	clock func() time.Time
 }
 
 func (c *settings) binaryOption(opt *uint32) bool {
@@ -174,6 +186,22 @@ func (c *settings) MaxOpenConns() int {
 	return c.maxOpenConns
 }
 
+func (c *settings) SetClock(fn func() time.Time) {
+	c.Lock()
+	c.clock = fn
+	c.Unlock()
+}
+
+func (c *settings) Now() time.Time {
+	c.RLock()
+	clock := c.clock
+	c.RUnlock()
+	if clock == nil {
+		return time.Now()
+	}
+	return clock()
+}
+
 // NewSettings returns a new settings value prefilled with the current default
 // settings.
 func NewSettings() Settings {
@@ -185,6 +213,7 @@ func NewSettings() Settings {
 		maxIdleConns:                  def.maxIdleConns,
 		maxOpenConns:                  def.maxOpenConns,
 		maxTransactionRetries:         def.maxTransactionRetries,
+		clock:                         def.clock,
 	}
 }
 
@@ -81,6 +81,41 @@ type SQL interface {
 	//  q := sqlbuilder.Update("profile").Set(...).Where(...)
 	Update(table string) Updater
 
+	// CreateTable prepares a TableCreator for the given table.
+	//
+	// Example:
+	//
+	//  q := sqlbuilder.CreateTable("people").Column("id", db.TypeSerial).PrimaryKey("id")
+	CreateTable(table string) TableCreator
+
+	// AlterTable prepares a TableAlterer for the given table.
+	//
+	// Example:
+	//
+	//  q := sqlbuilder.AlterTable("people").AddColumn("email", db.TypeString)
+	AlterTable(table string) TableAlterer
+
+	// DropTable prepares a TableDropper for the given table.
+	//
+	// Example:
+	//
+	//  q := sqlbuilder.DropTable("people").IfExists()
+	DropTable(table string) TableDropper
+
+	// CreateIndex prepares an IndexCreator for an index with the given name.
+	//
+	// Example:
+	//
+	//  q := sqlbuilder.CreateIndex("people_email_idx").On("people", "email").Unique()
+	CreateIndex(name string) IndexCreator
+
+	// DropIndex prepares an IndexDropper for the index with the given name.
+	//
+	// Example:
+	//
+	//  q := sqlbuilder.DropIndex("people_email_idx").On("people")
+	DropIndex(name string) IndexDropper
+
 	// Exec executes a SQL query that does not return any rows, like sql.Exec.
 	// Queries can be either strings or upper-db statements.
 	//
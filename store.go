@@ -55,3 +55,24 @@ type StoreUpdater interface {
 type StoreGetter interface {
 	Get(record Record, id interface{}) error
 }
+
+// WithStores returns a copy of sess that applies what the given data stores
+// declare to their collections, however they're accessed, like through
+// Session.Collection:
+//
+//  - The scopes of stores that implement HasScopes can be applied with
+//    Result.Scope, and their DefaultScope is applied to every result set.
+//  - The collections of stores that implement SoftDeletableStore are
+//    soft-deletable.
+//
+// Only the names and the declarations of the stores are used, so they can be
+// stores of any session. The declarations of stores of the same collections
+// that were given before are replaced.
+func WithStores(sess Session, stores ...Store) (Session, error) {
+	if s, ok := sess.(interface {
+		WithStores(...Store) Session
+	}); ok {
+		return s.WithStores(stores...), nil
+	}
+	return nil, ErrNotSupportedByAdapter
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"context"
+)
+
+// Tenancy configures a session that isolates the rows of different tenants
+// sharing the same tables, see WithTenancy.
+type Tenancy struct {
+	// Column is the name of the column that holds the tenant identifier, like
+	// "tenant_id".
+	Column string
+
+	// Tables lists the tables that are protected, all of them must have the
+	// tenant column.
+	Tables []string
+
+	// Value returns the identifier of the current tenant. If Value is nil the
+	// identifier is read from the context with TenantFromContext.
+	Value func(ctx context.Context) (interface{}, error)
+}
+
+type tenantContextKey struct{}
+
+type allowRawContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx that carries the given tenant
+// identifier.
+func ContextWithTenant(ctx context.Context, tenant interface{}) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant identifier that was set on ctx with
+// ContextWithTenant.
+func TenantFromContext(ctx context.Context) (interface{}, bool) {
+	tenant := ctx.Value(tenantContextKey{})
+	return tenant, tenant != nil
+}
+
+// ContextAllowingRaw returns a copy of ctx that allows raw SQL queries on the
+// protected tables of a session created with WithTenancy. Raw queries are not
+// rewritten, so they must filter by tenant themselves.
+func ContextAllowingRaw(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRawContextKey{}, true)
+}
+
+// RawAllowed reports whether ctx was created with ContextAllowingRaw.
+func RawAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(allowRawContextKey{}).(bool)
+	return allowed
+}
+
+// WithTenancy returns a copy of sess that restricts every statement on the
+// protected tables to the current tenant:
+//
+//  - SELECT, UPDATE and DELETE statements, including the ones built by
+//    collections and result sets, get a "column = tenant" condition for
+//    every protected table, joined tables included.
+//  - INSERT statements get the tenant column set to the current tenant.
+//    Upserts only update conflicting rows of the current tenant, and are
+//    refused if they change the tenant column or if the database can't
+//    restrict the updated rows, like MySQL.
+//  - UPDATE statements that change the tenant column, TRUNCATE statements
+//    and prepared statements on protected tables are refused.
+//  - Raw SQL statements that mention a protected table are refused, unless
+//    the context of the query was created with ContextAllowingRaw.
+//
+// Statements that touch protected tables fail with ErrMissingTenant if there
+// is no current tenant. Refused statements fail with ErrTenantIsolation.
+//
+// Example:
+//
+//  tenantSess, err := db.WithTenancy(sess, db.Tenancy{
+//    Column: "tenant_id",
+//    Tables: []string{"accounts", "invoices"},
+//  })
+//  ...
+//  ctx := db.ContextWithTenant(req.Context(), tenantID)
+//  err = tenantSess.WithContext(ctx).Collection("invoices").Find().All(&invoices)
+func WithTenancy(sess Session, tenancy Tenancy) (Session, error) {
+	if tenancy.Column == "" || len(tenancy.Tables) == 0 {
+		return nil, ErrInvalidTenancy
+	}
+	if s, ok := sess.(interface {
+		WithTenancy(Tenancy) Session
+	}); ok {
+		return s.WithTenancy(tenancy), nil
+	}
+	return nil, ErrNotSupportedByAdapter
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"database/sql/driver"
+	"reflect"
+
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+var trackingMapper = reflectx.NewMapper("db")
+
+// Tracked is an interface for records that keep a snapshot of the values they
+// were loaded or saved with. Only the columns that changed since the snapshot
+// was taken are sent when a tracked record is saved. Records implement Tracked
+// by embedding Tracking.
+//
+// Example:
+//
+//  type Book struct {
+//    db.Tracking
+//
+//    ID    uint64 `db:"id,omitempty"`
+//    Title string `db:"title"`
+//  }
+type Tracked interface {
+	Record
+
+	snapshot() (map[string]interface{}, *reflectx.Mapper)
+	setSnapshot(map[string]interface{}, *reflectx.Mapper)
+}
+
+// Tracking holds the snapshot of a tracked record, see Tracked.
+type Tracking struct {
+	values map[string]interface{}
+	mapper *reflectx.Mapper
+}
+
+func (t *Tracking) snapshot() (map[string]interface{}, *reflectx.Mapper) {
+	return t.values, t.mapper
+}
+
+func (t *Tracking) setSnapshot(values map[string]interface{}, mapper *reflectx.Mapper) {
+	t.values, t.mapper = values, mapper
+}
+
+// Change represents the old and new values of a column of a tracked record.
+type Change struct {
+	From interface{}
+	To   interface{}
+}
+
+// Snapshot saves the current values of a tracked record, future changes are
+// computed against them. Records are snapshotted automatically after being
+// loaded with Result.One, Result.All or Session.Get and after being saved with
+// Session.Save.
+func Snapshot(record Record) {
+	SnapshotWith(record, nil)
+}
+
+// SnapshotWith is like Snapshot but maps the fields of the record to columns
+// like the given session does, Changed maps them the same way. Sessions
+// created with WithFieldMapper snapshot records with their mapper.
+func SnapshotWith(record Record, sess Session) {
+	if tracked, ok := record.(Tracked); ok {
+		mapper := mapperOf(sess)
+		tracked.setSnapshot(trackedValues(record, mapper), mapper)
+	}
+}
+
+// mapperOf returns the struct mapper of the given session, or the default
+// one if the session has none.
+func mapperOf(sess Session) *reflectx.Mapper {
+	if s, ok := sess.(interface {
+		Mapper() *reflectx.Mapper
+	}); ok {
+		if mapper := s.Mapper(); mapper != nil {
+			return mapper
+		}
+	}
+	return trackingMapper
+}
+
+// Changed returns the columns of a tracked record whose values changed since
+// the record was snapshotted, or nil if the record is not tracked or has no
+// snapshot.
+func Changed(record Record) map[string]Change {
+	tracked, ok := record.(Tracked)
+	if !ok {
+		return nil
+	}
+	snapshot, mapper := tracked.snapshot()
+	if snapshot == nil {
+		return nil
+	}
+
+	changes := map[string]Change{}
+	for column, value := range currentValues(record, mapper) {
+		prev, ok := snapshot[column]
+		if ok && reflect.DeepEqual(prev, comparableValue(value)) {
+			continue
+		}
+		changes[column] = Change{From: prev, To: value}
+	}
+	return changes
+}
+
+// currentValues returns the values of the mapped fields of a record.
+func currentValues(record Record, mapper *reflectx.Mapper) map[string]interface{} {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+
+	fields := mapper.TypeMap(v.Type()).Names
+	values := make(map[string]interface{}, len(fields))
+	for name, fi := range fields {
+		values[name] = reflectx.FieldByIndexesReadOnly(v, fi.Index).Interface()
+	}
+	return values
+}
+
+func trackedValues(record Record, mapper *reflectx.Mapper) map[string]interface{} {
+	values := currentValues(record, mapper)
+	for column := range values {
+		values[column] = comparableValue(values[column])
+	}
+	return values
+}
+
+// comparableValue returns a copy of value that is not affected by later
+// changes to the record, values that implement driver.Valuer are converted
+// to their database representation.
+func comparableValue(value interface{}) interface{} {
+	if valuer, ok := value.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(valuer); rv.Kind() != reflect.Ptr || !rv.IsNil() {
+			if v, err := valuer.Value(); err == nil {
+				value = v
+			}
+		}
+	}
+	if b, ok := value.([]byte); ok {
+		return append([]byte(nil), b...)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		return comparableValue(rv.Elem().Interface())
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type trackedBook struct {
+	Tracking
+
+	ID       uint64         `db:"id,omitempty"`
+	Title    string         `db:"title"`
+	Tags     []byte         `db:"tags"`
+	Subtitle sql.NullString `db:"subtitle"`
+	Notes    *string        `db:"notes"`
+}
+
+func (*trackedBook) Store(sess Session) Store {
+	return nil
+}
+
+func TestChanged(t *testing.T) {
+	book := &trackedBook{ID: 1, Title: "Dune", Tags: []byte("scifi")}
+	assert.Nil(t, Changed(book))
+
+	Snapshot(book)
+	assert.Equal(t, map[string]Change{}, Changed(book))
+
+	book.Title = "Dune Messiah"
+	book.Tags[0] = 'S'
+	book.Subtitle = sql.NullString{String: "Book two", Valid: true}
+	notes := "Sequel"
+	book.Notes = &notes
+
+	changes := Changed(book)
+	assert.Equal(t, 4, len(changes))
+	assert.Equal(t, Change{From: "Dune", To: "Dune Messiah"}, changes["title"])
+	assert.Equal(t, []byte("scifi"), changes["tags"].From)
+	assert.Equal(t, nil, changes["subtitle"].From)
+	assert.Equal(t, nil, changes["notes"].From)
+	assert.Equal(t, &notes, changes["notes"].To)
+
+	Snapshot(book)
+	assert.Equal(t, map[string]Change{}, Changed(book))
+
+	// Changes to the value pointed to are detected too.
+	notes = "Second book"
+	assert.Equal(t, Change{From: "Sequel", To: &notes}, Changed(book)["notes"])
+}
+
+func TestChangedUntracked(t *testing.T) {
+	record := struct {
+		Record
+		Title string `db:"title"`
+	}{}
+	Snapshot(&record)
+	assert.Nil(t, Changed(&record))
+}